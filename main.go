@@ -3,10 +3,16 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
+	"yokiyoki/pkg/cache"
+	"yokiyoki/pkg/config"
 	"yokiyoki/pkg/formatter"
 	"yokiyoki/pkg/interactive"
 	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/parallel"
+	"yokiyoki/pkg/repository"
 	"yokiyoki/pkg/services"
 
 	"github.com/spf13/cobra"
@@ -17,10 +23,50 @@ var (
 	startDate      string
 	endDate        string
 	byUser         bool
+	byTeam         bool
+	teamsFile      string
+	teams          models.Teams
 	format         string
 	sortBy         string
 	normalizeUsers bool
 	detailedStats  bool
+	backend        string
+	refresh        bool
+	offline        bool
+	noCache        bool
+	concurrency    int
+	commitSource   string
+	orgs           []string
+	users          []string
+	discoverName   string
+	topic          string
+	language       string
+	visibility     string
+	excludeArchive bool
+	excludeForks   bool
+	pushedSince    string
+	reviews        bool
+	deployments    bool
+	timeline       bool
+	percentiles    bool
+	bucket         string
+	compare        string
+	byBranch       bool
+	coAuthors      string
+	excludeBots    bool
+	botsOnly       bool
+	groupBots      bool
+	bots           services.BotOptions
+	mode           string
+)
+
+// Report modes selected by --mode or the interactive mode prompt
+const (
+	modeMetrics       = "metrics"
+	modeCommits       = "commits"
+	modeConversations = "conversations"
+	modeReleases      = "releases"
+	modeCI            = "ci"
 )
 
 var rootCmd = &cobra.Command{
@@ -40,21 +86,65 @@ Examples:
   yokiyoki --days 7 --by-user owner/repo      # Last 7 days, by user
   yokiyoki --start 2024-01-01 --end 2024-01-31 owner/repo  # Date range
   yokiyoki --normalize-users --by-user owner/repo  # Merge similar usernames
+  yokiyoki --by-user --co-authors split owner/repo # Share co-authored commits between their authors
+  yokiyoki --by-user --group-bots owner/repo  # Report bots in one automation row
+  yokiyoki --by-team owner/repo1 owner/repo2  # Break down by the teams in config.toml
   yokiyoki --format csv owner/repo            # CSV output
   yokiyoki --sort-by user,repository owner/repo  # Sort by user then repository
-  yokiyoki --detailed-stats owner/repo        # Enable detailed line stats (slower)`,
+  yokiyoki --detailed-stats owner/repo        # Enable detailed line stats (slower)
+  yokiyoki --reviews owner/repo               # Add review latency metrics (slower)
+  yokiyoki --deployments owner/repo           # Add DORA metrics per environment
+  yokiyoki --timeline owner/repo              # Add first response, reopen, assignment and label metrics (slower)
+  yokiyoki --percentiles owner/repo           # Add median and p90 columns next to each average time
+  yokiyoki --bucket week owner/repo           # Show the metrics of each ISO week as a trend
+  yokiyoki --compare previous owner/repo      # Compare with the previous period of the same length
+  yokiyoki --mode releases owner/repo         # Release cadence report
+  yokiyoki --mode ci --by-branch owner/repo   # GitHub Actions health per workflow and branch
+  yokiyoki --backend api owner/repo           # Use the REST API directly (GITHUB_TOKEN) instead of gh
+  yokiyoki --offline owner/repo               # Report from the local cache without network access
+  yokiyoki --org myorg --exclude-archived     # All active repositories of an organization
+  yokiyoki 'myorg/service-*'                  # Repositories matching a pattern`,
 	Run: runCollect,
 }
 
 func main() {
+	rootCmd.Flags().StringVarP(&mode, "mode", "m", modeMetrics, "Report: metrics, commits, conversations, releases or ci")
 	rootCmd.Flags().IntVarP(&days, "days", "d", 30, "Number of days to analyze (default 30)")
 	rootCmd.Flags().StringVar(&startDate, "start", "", "Start date (YYYY-MM-DD format, e.g., 2024-01-01)")
 	rootCmd.Flags().StringVar(&endDate, "end", "", "End date (YYYY-MM-DD format, e.g., 2024-01-31)")
 	rootCmd.Flags().BoolVarP(&byUser, "by-user", "u", false, "Break down metrics by user")
+	rootCmd.Flags().BoolVar(&byTeam, "by-team", false, "Break down metrics by team, with users in no team under other")
+	rootCmd.Flags().StringVar(&teamsFile, "teams", "", "Team definition file with a [teams] section (default: the [teams] section of config.toml)")
 	rootCmd.Flags().StringVarP(&format, "format", "f", "markdown", "Output format: markdown, csv, or json")
-	rootCmd.Flags().StringVarP(&sortBy, "sort-by", "s", "repository", "Sort order: repository, repository,user, user,repository")
+	rootCmd.Flags().StringVarP(&sortBy, "sort-by", "s", "repository", "Sort order: repository, repository,user, user,repository, repository,team, team,repository")
 	rootCmd.Flags().BoolVarP(&normalizeUsers, "normalize-users", "n", false, "Normalize usernames by removing spaces (merge 'kotaoue' and 'kota oue')")
-	rootCmd.Flags().BoolVar(&detailedStats, "detailed-stats", false, "Enable detailed line change statistics (requires individual API calls per commit - slower)")
+	rootCmd.Flags().BoolVar(&detailedStats, "detailed-stats", false, "Enable detailed line change statistics (requires individual API calls per commit - slower, unless commits are read from git)")
+	rootCmd.Flags().StringVar(&backend, "backend", repository.BackendGH, "Data source: gh (GitHub CLI) or api (REST API with GITHUB_TOKEN/GH_TOKEN or config.toml)")
+	rootCmd.Flags().BoolVar(&refresh, "refresh", false, "Ignore the local cache and refetch everything")
+	rootCmd.Flags().BoolVar(&offline, "offline", false, "Use only the local cache without touching the network")
+	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "Disable the local cache")
+	rootCmd.Flags().BoolVar(&reviews, "reviews", false, "Fetch pull request reviews for review latency metrics (requires individual API calls per PR - slower)")
+	rootCmd.Flags().BoolVar(&deployments, "deployments", false, "Fetch deployments for DORA metrics per environment (deployment frequency, lead time, change failure rate, time to restore)")
+	rootCmd.Flags().BoolVar(&timeline, "timeline", false, "Fetch issue and pull request timelines for first response, reopen, assignment and label metrics (requires individual API calls per item - slower)")
+	rootCmd.Flags().BoolVar(&percentiles, "percentiles", false, "Show the median and 90th percentile next to each average time (JSON output always includes them)")
+	rootCmd.Flags().StringVar(&bucket, "bucket", "", "Split the period into week, month or quarter buckets (JST) and report the metrics of each")
+	rootCmd.Flags().StringVar(&compare, "compare", "", "Compare the metrics with another period: previous, same-last-year or YYYY-MM-DD..YYYY-MM-DD")
+	rootCmd.Flags().StringVar(&coAuthors, "co-authors", services.AttributionPrimary, "Credit for Co-authored-by commits with --by-user: primary (author only), full (each co-author too) or split (shared evenly)")
+	rootCmd.Flags().BoolVar(&excludeBots, "exclude-bots", false, "Leave out activity of bots (logins ending in [bot] and bots listed in config.toml)")
+	rootCmd.Flags().BoolVar(&botsOnly, "bots-only", false, "Report only activity of bots")
+	rootCmd.Flags().BoolVar(&groupBots, "group-bots", false, "Report activity of all bots under one automation user with --by-user, or as the author in commits and conversations modes")
+	rootCmd.Flags().BoolVar(&byBranch, "by-branch", false, "Break down the CI report by branch")
+	rootCmd.Flags().StringVar(&commitSource, "commit-source", "", "Commit source for all repositories: api or git (local clone or mirror in the cache; overrides commit_source in config.toml)")
+	rootCmd.Flags().IntVarP(&concurrency, "concurrency", "c", 4, "Maximum number of requests in flight at once, shared by all repositories")
+	rootCmd.Flags().StringSliceVar(&orgs, "org", nil, "Analyze the repositories of an organization ([host/]org, repeatable)")
+	rootCmd.Flags().StringSliceVar(&users, "user", nil, "Analyze the repositories owned by a user ([host/]user, repeatable)")
+	rootCmd.Flags().StringVar(&discoverName, "name", "", "Only discover repositories whose name matches a glob (e.g. 'service-*')")
+	rootCmd.Flags().StringVar(&topic, "topic", "", "Only discover repositories with this topic")
+	rootCmd.Flags().StringVar(&language, "language", "", "Only discover repositories with this primary language")
+	rootCmd.Flags().StringVar(&visibility, "visibility", "", "Only discover repositories with this visibility: public, private or internal")
+	rootCmd.Flags().BoolVar(&excludeArchive, "exclude-archived", false, "Skip archived repositories when discovering")
+	rootCmd.Flags().BoolVar(&excludeForks, "exclude-forks", false, "Skip forks when discovering")
+	rootCmd.Flags().StringVar(&pushedSince, "pushed-since", "", "Only discover repositories pushed on or after this date (YYYY-MM-DD)")
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -66,25 +156,84 @@ func runCollect(cmd *cobra.Command, args []string) {
 	fmt.Println("GitHub Metrics Collector")
 	fmt.Println("========================")
 
+	if err := setupFetcher(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch mode {
+	case modeMetrics, modeCommits, modeConversations, modeReleases, modeCI:
+	default:
+		fmt.Printf("Error: unknown mode %q (expected %s, %s, %s, %s or %s)\n", mode, modeMetrics, modeCommits, modeConversations, modeReleases, modeCI)
+		os.Exit(1)
+	}
+
+	switch coAuthors {
+	case services.AttributionPrimary, services.AttributionFull, services.AttributionSplit:
+	default:
+		fmt.Printf("Error: unknown --co-authors %q (expected %s, %s or %s)\n", coAuthors, services.AttributionPrimary, services.AttributionFull, services.AttributionSplit)
+		os.Exit(1)
+	}
+
+	switch bucket {
+	case "", services.BucketWeek, services.BucketMonth, services.BucketQuarter:
+	default:
+		fmt.Printf("Error: unknown --bucket %q (expected %s, %s or %s)\n", bucket, services.BucketWeek, services.BucketMonth, services.BucketQuarter)
+		os.Exit(1)
+	}
+	if compare != "" && bucket != "" {
+		fmt.Println("Error: --compare and --bucket cannot be used together")
+		os.Exit(1)
+	}
+	if byTeam && byUser {
+		fmt.Println("Error: --by-team and --by-user cannot be used together")
+		os.Exit(1)
+	}
+	if byTeam && teams.Empty() {
+		fmt.Println("Error: --by-team requires teams defined in the [teams] section of config.toml or --teams")
+		os.Exit(1)
+	}
+
 	// Ask for language when running in interactive mode (no repository arguments provided)
 	lang := "en"
-	mode := "metrics"
-	isInteractive := len(args) == 0
+	isInteractive := len(args) == 0 && len(orgs) == 0 && len(users) == 0
 	if isInteractive {
 		lang = interactive.GetLanguage(services.NewPrompter())
-		mode = interactive.NewMetrics(lang).GetMode()
+		if !cmd.Flags().Changed("mode") {
+			mode = interactive.NewMetrics(lang).GetMode()
+		}
 		if !cmd.Flags().Changed("format") {
 			format = interactive.NewMetrics(lang).GetFormat()
 		}
 	}
 
+	// Options only some modes support are checked once the mode is known, which may have been asked above
+	if bucket != "" && mode != modeMetrics {
+		fmt.Printf("Error: --bucket is only available in %s mode\n", modeMetrics)
+		os.Exit(1)
+	}
+	if compare != "" && mode != modeMetrics {
+		fmt.Printf("Error: --compare is only available in %s mode\n", modeMetrics)
+		os.Exit(1)
+	}
+	if byTeam && mode != modeMetrics {
+		fmt.Printf("Error: --by-team is only available in %s mode\n", modeMetrics)
+		os.Exit(1)
+	}
+	policy, err := botPolicy()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	bots.Policy = policy
+
 	repos := collectRepositories(cmd, args, lang)
 	if len(repos) == 0 {
 		fmt.Println("No repositories selected. Exiting.")
 		return
 	}
 
-	if mode == "commits" {
+	if mode == modeCommits {
 		collectMissingCommitOptions(cmd, lang, isInteractive)
 		period := createPeriod()
 		allCommits := processRepositoriesForCommits(repos, period)
@@ -92,34 +241,225 @@ func runCollect(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	if mode == "conversations" {
-		collectMissingConversationOptions(cmd, lang, isInteractive)
+	if mode == modeConversations {
+		collectMissingReportOptions(cmd, lang, isInteractive)
 		period := createPeriod()
 		allComments := processRepositoriesForConversations(repos, period)
 		outputConversationResults(allComments, period)
 		return
 	}
 
+	if mode == modeReleases {
+		collectMissingReportOptions(cmd, lang, isInteractive)
+		period := createPeriod()
+		allReleases := processRepositoriesForReleases(repos, period)
+		outputReleaseResults(allReleases, period)
+		return
+	}
+
+	if mode == modeCI {
+		collectMissingReportOptions(cmd, lang, isInteractive)
+		period := createPeriod()
+		allCI := processRepositoriesForCI(repos, period)
+		outputCIResults(allCI, period)
+		return
+	}
+
 	collectMissingOptions(cmd, lang, isInteractive)
+	if detailedStats && !cmd.Flags().Changed("detailed-stats") {
+		// Merge request diffs are only fetched for detailed statistics, which were asked after
+		// the fetcher listing the repositories was set up
+		if err := setupFetcher(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	period := createPeriod()
-	allMetrics := processRepositories(repos, period)
-	outputResults(allMetrics, period)
+	if compare != "" {
+		comparisonPeriod, err := period.Comparison(compare)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		results := processRepositories(repos, period, deployments)
+		fmt.Printf("\nComparison period: %s to %s\n",
+			comparisonPeriod.StartTime().Format("2006-01-02"),
+			comparisonPeriod.EndTime().Format("2006-01-02"))
+		// Deployments are not compared, so they are only fetched for the period itself
+		previous := processRepositories(repos, comparisonPeriod, false)
+		outputComparisonResults(results, previous, period, comparisonPeriod)
+		return
+	}
+
+	results := processRepositories(repos, period, deployments)
+	outputResults(results, period)
+}
+
+// botPolicy returns the bot policy selected by --exclude-bots, --bots-only and --group-bots
+func botPolicy() (string, error) {
+	selected := 0
+	for _, set := range []bool{excludeBots, botsOnly, groupBots} {
+		if set {
+			selected++
+		}
+	}
+	switch {
+	case selected > 1:
+		return "", fmt.Errorf("--exclude-bots, --bots-only and --group-bots cannot be used together")
+	case groupBots && mode != modeMetrics && mode != modeCommits && mode != modeConversations:
+		return "", fmt.Errorf("--group-bots is only available in %s, %s and %s modes", modeMetrics, modeCommits, modeConversations)
+	case excludeBots:
+		return services.BotsExclude, nil
+	case botsOnly:
+		return services.BotsOnly, nil
+	case groupBots:
+		return services.BotsGroup, nil
+	}
+	return services.BotsInclude, nil
+}
+
+func setupFetcher() error {
+	if refresh && offline {
+		return fmt.Errorf("--refresh and --offline cannot be used together")
+	}
+	repository.Concurrency = concurrency
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+	for name, p := range cfg.Providers {
+		if p.Host != "" {
+			models.SetDefaultHost(name, p.Host)
+		}
+	}
+	for host := range cfg.Hosts {
+		models.AddHost(host)
+	}
+	bots.Bots = models.NewBots(cfg.Bots)
+
+	teamDefinitions := cfg.Teams
+	if teamsFile != "" {
+		teamDefinitions, err = config.LoadTeams(teamsFile)
+		if err != nil {
+			return fmt.Errorf("could not load teams: %w", err)
+		}
+	}
+	members := make(map[string][]string, len(teamDefinitions))
+	for name, team := range teamDefinitions {
+		members[name] = team.Users()
+	}
+	teams = models.NewTeams(members)
+
+	var store *cache.Store
+	if !noCache {
+		dir, err := cache.DefaultDir()
+		if err != nil {
+			return fmt.Errorf("could not locate cache directory: %w", err)
+		}
+		store = cache.NewStore(dir)
+	}
+
+	fetcher, err := repository.NewFetcher(backend, cfg, store, detailedStats)
+	if err != nil {
+		return err
+	}
+
+	if store != nil {
+		mode := repository.CacheMode{Refresh: refresh, Offline: offline}
+		fetcher = repository.NewCachedFetcher(fetcher, store, mode)
+	} else if offline {
+		return fmt.Errorf("--offline requires the cache")
+	}
+
+	fetcher, err = withGitCommits(fetcher, cfg)
+	if err != nil {
+		return err
+	}
+
+	repository.DefaultFetcher = fetcher
+	return nil
+}
+
+// withGitCommits reads commits from git for the repositories selected by --commit-source or config.toml
+func withGitCommits(fetcher repository.Fetcher, cfg *config.Config) (repository.Fetcher, error) {
+	clones := cfg.GitClones()
+	switch commitSource {
+	case "":
+	case repository.CommitSourceAPI:
+		return fetcher, nil
+	case repository.CommitSourceGit:
+	default:
+		return nil, fmt.Errorf("unknown commit source %q (expected %s or %s)", commitSource, repository.CommitSourceAPI, repository.CommitSourceGit)
+	}
+
+	all := commitSource == repository.CommitSourceGit
+	if !all && len(clones) == 0 {
+		return fetcher, nil
+	}
+
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, fmt.Errorf("could not locate cache directory: %w", err)
+	}
+
+	return repository.NewGitFetcher(fetcher, repository.GitOptions{
+		Clones:    clones,
+		All:       all,
+		MirrorDir: filepath.Join(dir, "git"),
+		Token:     cfg.Token,
+		Offline:   offline,
+	}), nil
 }
 
 func collectRepositories(cmd *cobra.Command, args []string, lang string) []models.Repository {
 	metricsInput := interactive.NewMetrics(lang)
 
-	if len(args) > 0 {
-		repos, err := metricsInput.GetRepositoriesFromArgs(args)
+	if len(args) == 0 && len(orgs) == 0 && len(users) == 0 {
+		return metricsInput.GetRepositories()
+	}
+
+	filter, err := discoverFilter()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return nil
+	}
+
+	repos, err := metricsInput.GetRepositoriesFromArgs(args, filter)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return nil
+	}
+
+	filter.Name = discoverName
+	discovered, err := metricsInput.DiscoverRepositories(orgs, users, filter)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return nil
+	}
+
+	// A repository both listed and discovered, possibly spelled differently, is processed once
+	return models.UniqueRepositories(append(repos, discovered...))
+}
+
+// discoverFilter builds the filter applied to repositories discovered by --org, --user and name patterns
+func discoverFilter() (repository.DiscoverFilter, error) {
+	filter := repository.DiscoverFilter{
+		Topic:           topic,
+		Language:        language,
+		Visibility:      visibility,
+		ExcludeArchived: excludeArchive,
+		ExcludeForks:    excludeForks,
+	}
+	if pushedSince != "" {
+		t, err := time.ParseInLocation("2006-01-02", pushedSince, time.Local)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return nil
+			return filter, fmt.Errorf("invalid --pushed-since date %q: %w", pushedSince, err)
 		}
-		return repos
+		filter.PushedSince = t
 	}
-
-	return metricsInput.GetRepositories()
+	return filter, nil
 }
 
 func collectMissingOptions(cmd *cobra.Command, lang string, isInteractive bool) {
@@ -134,7 +474,7 @@ func collectMissingOptions(cmd *cobra.Command, lang string, isInteractive bool)
 		}
 	}
 
-	if !cmd.Flags().Changed("by-user") {
+	if !cmd.Flags().Changed("by-user") && !byTeam {
 		byUser = metricsInput.GetByUser()
 	}
 
@@ -143,7 +483,12 @@ func collectMissingOptions(cmd *cobra.Command, lang string, isInteractive bool)
 	}
 
 	if !cmd.Flags().Changed("sort-by") {
-		sortBy = metricsInput.GetSortBy()
+		if byTeam {
+			// Team rows add up every repository, so they are in effect sorted by team
+			sortBy = "team,repository"
+		} else {
+			sortBy = metricsInput.GetSortBy()
+		}
 	}
 
 	if !cmd.Flags().Changed("normalize-users") {
@@ -153,6 +498,18 @@ func collectMissingOptions(cmd *cobra.Command, lang string, isInteractive bool)
 	if !cmd.Flags().Changed("detailed-stats") {
 		detailedStats = metricsInput.GetDetailedStats()
 	}
+
+	if !cmd.Flags().Changed("reviews") {
+		reviews = metricsInput.GetReviews()
+	}
+
+	if !cmd.Flags().Changed("deployments") {
+		deployments = metricsInput.GetDeployments()
+	}
+
+	if !cmd.Flags().Changed("timeline") {
+		timeline = metricsInput.GetTimeline()
+	}
 }
 
 func createPeriod() *services.Chronometer {
@@ -172,42 +529,146 @@ func createPeriod() *services.Chronometer {
 	return chronometer
 }
 
-func processRepositories(repos []models.Repository, period *services.Chronometer) []models.Metrics {
-	var allMetrics []models.Metrics
-
+func processRepositories(repos []models.Repository, period *services.Chronometer, fetchDeployments bool) services.Report {
+	var all services.Report
+
+	showHost := models.MixedHosts(repos)
+	options := services.MetricsOptions{
+		Period:         period,
+		ByUser:         byUser,
+		ByTeam:         byTeam,
+		Teams:          teams,
+		NormalizeUsers: normalizeUsers,
+		DetailedStats:  detailedStats,
+		SortBy:         sortBy,
+		ShowHost:       showHost,
+		Reviews:        reviews,
+		Concurrency:    concurrency,
+		Deployments:    fetchDeployments,
+		Timeline:       timeline,
+		Bucket:         bucket,
+		Attribution:    coAuthors,
+		Bots:           bots,
+	}
 	fmt.Println()
-	for _, repo := range repos {
-		fmt.Printf("Processing repository: %s/%s\n", repo.Owner, repo.Name)
-		options := services.MetricsOptions{
-			Period:         period,
-			ByUser:         byUser,
-			NormalizeUsers: normalizeUsers,
-			DetailedStats:  detailedStats,
-			SortBy:         sortBy,
-		}
-		metrics := services.Execute(repo, options)
-		allMetrics = append(allMetrics, metrics...)
+	results := parallel.Map(repos, concurrency, func(repo models.Repository) services.Report {
+		fmt.Printf("Processing repository: %s\n", repo.DisplayName(showHost))
+		return services.Collect(repo, options)
+	})
+	for _, report := range results {
+		all.Metrics = append(all.Metrics, report.Metrics...)
+		all.Deployments = append(all.Deployments, report.Deployments...)
+		all.Labels = append(all.Labels, report.Labels...)
+	}
+	if byTeam {
+		// Each team gets one row for its activity across all repositories
+		all.Metrics = services.MergeTeams(results, options)
+	}
+	if sortBy != "" {
+		services.SortMetrics(all.Metrics, sortBy)
 	}
 
-	return allMetrics
+	return all
 }
 
-func outputResults(allMetrics []models.Metrics, period *services.Chronometer) {
+func outputResults(results services.Report, period *services.Chronometer) {
+	allMetrics := results.Metrics
 	fmt.Println("Report")
 	fmt.Printf("Analyzing data from %s to %s (%d days)\n\n",
 		period.StartTime().Format("2006-01-02"),
 		period.EndTime().Format("2006-01-02"),
 		days)
 
+	opts := formatter.MetricsOutputOptions{
+		ByUser:        byUser,
+		DetailedStats: detailedStats,
+		Reviews:       reviews,
+		Timeline:      timeline,
+		Percentiles:   percentiles,
+	}
 	if format == "csv" {
 		csv := formatter.NewMetricsCsv(allMetrics)
-		csv.Output(byUser, detailedStats)
+		csv.Output(opts)
 	} else if format == "json" {
 		jsonFmt := formatter.NewMetricsJson(allMetrics)
-		jsonFmt.Output(byUser, detailedStats)
+		jsonFmt.Output(opts)
+	} else if bucket != "" {
+		formatter.NewMetricsTrendTable(allMetrics).Output(opts)
 	} else {
 		table := formatter.NewMetricsTable(allMetrics)
-		table.Output(byUser, detailedStats)
+		table.Output(opts)
+	}
+
+	if deployments {
+		outputDeploymentResults(results.Deployments)
+	}
+	if timeline {
+		outputLabelResults(results.Labels)
+	}
+}
+
+// outputComparisonResults prints each metric of the period next to its value in the comparison period
+func outputComparisonResults(results, previous services.Report, period, comparisonPeriod *services.Chronometer) {
+	fmt.Println("Report")
+	fmt.Printf("Analyzing data from %s to %s compared with %s to %s\n\n",
+		period.StartTime().Format("2006-01-02"),
+		period.EndTime().Format("2006-01-02"),
+		comparisonPeriod.StartTime().Format("2006-01-02"),
+		comparisonPeriod.EndTime().Format("2006-01-02"))
+
+	options := services.MetricsOptions{DetailedStats: detailedStats, Reviews: reviews, Timeline: timeline}
+	comparisons := services.CompareMetrics(results.Metrics, previous.Metrics, options)
+	if format == "csv" {
+		formatter.NewComparisonCsv(comparisons).Output(byUser)
+	} else if format == "json" {
+		formatter.NewComparisonJson(comparisons).Output(byUser)
+	} else {
+		formatter.NewComparisonTable(comparisons).Output(byUser)
+	}
+
+	if deployments {
+		outputDeploymentResults(results.Deployments)
+	}
+	if timeline {
+		outputLabelResults(results.Labels)
+	}
+}
+
+// outputDeploymentResults prints the DORA metrics after the other metrics
+func outputDeploymentResults(allDeployments []models.DeploymentMetrics) {
+	fmt.Println()
+	fmt.Println("Deployments (DORA)")
+	if len(allDeployments) == 0 {
+		fmt.Println("No deployments found.")
+		return
+	}
+	fmt.Println()
+
+	if format == "csv" {
+		formatter.NewDeploymentsCsv(allDeployments).Output()
+	} else if format == "json" {
+		formatter.NewDeploymentsJson(allDeployments).Output()
+	} else {
+		formatter.NewDeploymentsTable(allDeployments).Output()
+	}
+}
+
+// outputLabelResults prints the time items spent in each label after the other metrics
+func outputLabelResults(allLabels []models.LabelMetrics) {
+	fmt.Println()
+	fmt.Println("Labels")
+	if len(allLabels) == 0 {
+		fmt.Println("No labels found.")
+		return
+	}
+	fmt.Println()
+
+	if format == "csv" {
+		formatter.NewLabelsCsv(allLabels).Output()
+	} else if format == "json" {
+		formatter.NewLabelsJson(allLabels).Output()
+	} else {
+		formatter.NewLabelsTable(allLabels).Output()
 	}
 }
 
@@ -235,14 +696,19 @@ func collectMissingCommitOptions(cmd *cobra.Command, lang string, isInteractive
 func processRepositoriesForCommits(repos []models.Repository, period *services.Chronometer) []models.Commit {
 	var allCommits []models.Commit
 
+	showHost := models.MixedHosts(repos)
 	fmt.Println()
-	for _, repo := range repos {
-		fmt.Printf("Processing repository: %s/%s\n", repo.Owner, repo.Name)
+	results := parallel.Map(repos, concurrency, func(repo models.Repository) []models.Commit {
+		fmt.Printf("Processing repository: %s\n", repo.DisplayName(showHost))
 		opts := services.CommitsOptions{
 			Period:        period,
 			DetailedStats: detailedStats,
+			ShowHost:      showHost,
+			Bots:          bots,
 		}
-		commits := services.ExecuteCommits(repo, opts)
+		return services.ExecuteCommits(repo, opts)
+	})
+	for _, commits := range results {
 		allCommits = append(allCommits, commits...)
 	}
 
@@ -269,7 +735,8 @@ func outputCommitResults(allCommits []models.Commit, period *services.Chronomete
 	}
 }
 
-func collectMissingConversationOptions(cmd *cobra.Command, lang string, isInteractive bool) {
+// collectMissingReportOptions asks for the period and format, for modes without further options
+func collectMissingReportOptions(cmd *cobra.Command, lang string, isInteractive bool) {
 	metricsInput := interactive.NewMetrics(lang)
 
 	if !cmd.Flags().Changed("days") && !cmd.Flags().Changed("start") && !cmd.Flags().Changed("end") {
@@ -289,13 +756,19 @@ func collectMissingConversationOptions(cmd *cobra.Command, lang string, isIntera
 func processRepositoriesForConversations(repos []models.Repository, period *services.Chronometer) []models.Comment {
 	var allComments []models.Comment
 
+	showHost := models.MixedHosts(repos)
 	fmt.Println()
-	for _, repo := range repos {
-		fmt.Printf("Processing repository: %s/%s\n", repo.Owner, repo.Name)
+	results := parallel.Map(repos, concurrency, func(repo models.Repository) []models.Comment {
+		fmt.Printf("Processing repository: %s\n", repo.DisplayName(showHost))
 		opts := services.ConversationsOptions{
-			Period: period,
+			Period:      period,
+			Concurrency: concurrency,
+			ShowHost:    showHost,
+			Bots:        bots,
 		}
-		comments := services.ExecuteConversations(repo, opts)
+		return services.ExecuteConversations(repo, opts)
+	})
+	for _, comments := range results {
 		allComments = append(allComments, comments...)
 	}
 
@@ -320,3 +793,79 @@ func outputConversationResults(allComments []models.Comment, period *services.Ch
 		table.Output()
 	}
 }
+
+func processRepositoriesForReleases(repos []models.Repository, period *services.Chronometer) []models.ReleaseMetrics {
+	showHost := models.MixedHosts(repos)
+	fmt.Println()
+	allReleases := parallel.Map(repos, concurrency, func(repo models.Repository) models.ReleaseMetrics {
+		fmt.Printf("Processing repository: %s\n", repo.DisplayName(showHost))
+		opts := services.ReleasesOptions{
+			Period:   period,
+			ShowHost: showHost,
+		}
+		return services.ExecuteReleases(repo, opts)
+	})
+
+	services.SortReleaseMetrics(allReleases)
+	return allReleases
+}
+
+func outputReleaseResults(allReleases []models.ReleaseMetrics, period *services.Chronometer) {
+	fmt.Println("Report")
+	fmt.Printf("Analyzing data from %s to %s (%d days)\n\n",
+		period.StartTime().Format("2006-01-02"),
+		period.EndTime().Format("2006-01-02"),
+		days)
+
+	if format == "csv" {
+		csv := formatter.NewReleasesCsv(allReleases)
+		csv.Output()
+	} else if format == "json" {
+		jsonFmt := formatter.NewReleasesJson(allReleases)
+		jsonFmt.Output()
+	} else {
+		table := formatter.NewReleasesTable(allReleases)
+		table.Output()
+	}
+}
+
+func processRepositoriesForCI(repos []models.Repository, period *services.Chronometer) []models.CIMetrics {
+	var allCI []models.CIMetrics
+
+	showHost := models.MixedHosts(repos)
+	fmt.Println()
+	results := parallel.Map(repos, concurrency, func(repo models.Repository) []models.CIMetrics {
+		fmt.Printf("Processing repository: %s\n", repo.DisplayName(showHost))
+		opts := services.CIOptions{
+			Period:   period,
+			ByBranch: byBranch,
+			ShowHost: showHost,
+		}
+		return services.ExecuteCI(repo, opts)
+	})
+	for _, metrics := range results {
+		allCI = append(allCI, metrics...)
+	}
+
+	services.SortCIMetrics(allCI)
+	return allCI
+}
+
+func outputCIResults(allCI []models.CIMetrics, period *services.Chronometer) {
+	fmt.Println("Report")
+	fmt.Printf("Analyzing data from %s to %s (%d days)\n\n",
+		period.StartTime().Format("2006-01-02"),
+		period.EndTime().Format("2006-01-02"),
+		days)
+
+	if format == "csv" {
+		csv := formatter.NewCICsv(allCI)
+		csv.Output(byBranch)
+	} else if format == "json" {
+		jsonFmt := formatter.NewCIJson(allCI)
+		jsonFmt.Output()
+	} else {
+		table := formatter.NewCITable(allCI)
+		table.Output(byBranch)
+	}
+}
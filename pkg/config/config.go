@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config represents the user configuration loaded from config.toml
+type Config struct {
+	Hosts        map[string]Host       `toml:"hosts"`
+	Providers    map[string]Provider   `toml:"providers"`
+	Repositories map[string]Repository `toml:"repositories"`
+	// Bots lists accounts treated as bots in addition to those whose login ends in [bot]
+	Bots []string `toml:"bots"`
+	// Teams maps team names to their members for breaking down metrics by team
+	Teams map[string]Team `toml:"teams"`
+}
+
+// Team represents the members of a single team
+type Team struct {
+	// Members are the logins of the team's members
+	Members []string `toml:"members"`
+	// Aliases maps members to the other names they appear under, such as their git author name
+	Aliases map[string][]string `toml:"aliases"`
+}
+
+// Host represents the settings for a single API host
+type Host struct {
+	Token string `toml:"token"`
+}
+
+// Provider represents the settings for a non-GitHub code hosting provider such as gitlab, gitea or forgejo
+type Provider struct {
+	// Host is the provider instance, e.g. "gitlab.example.com"
+	Host  string `toml:"host"`
+	Token string `toml:"token"`
+}
+
+// Repository represents the settings for a single repository, keyed by "owner/name"
+type Repository struct {
+	// CommitSource is "api" (the default) or "git" to read commits from a local clone
+	CommitSource string `toml:"commit_source"`
+	// Path is the local clone read when CommitSource is "git"; empty mirrors the repository into the cache
+	Path string `toml:"path"`
+}
+
+// tokenEnvVars lists the environment variables checked for a github.com token, in order of precedence
+var tokenEnvVars = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+
+// enterpriseTokenEnvVars lists the environment variables checked for a GitHub Enterprise Server token
+var enterpriseTokenEnvVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+
+// githubHost is the host name of public GitHub
+const githubHost = "github.com"
+
+// Path returns the location of the configuration file.
+// YOKIYOKI_CONFIG overrides the default of <user config dir>/yokiyoki/config.toml.
+func Path() (string, error) {
+	if path := os.Getenv("YOKIYOKI_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "yokiyoki", "config.toml"), nil
+}
+
+// Load reads the configuration file. A missing file yields an empty configuration.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return &Config{}, nil
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the configuration from the given path
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{}
+	if _, err := toml.DecodeFile(path, cfg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// providerTokenEnvVars maps providers to the environment variable holding their token
+var providerTokenEnvVars = map[string]string{
+	"gitlab":  "GITLAB_TOKEN",
+	"gitea":   "GITEA_TOKEN",
+	"forgejo": "FORGEJO_TOKEN",
+}
+
+// Provider returns the settings for the named provider, using defaultHost when no host is configured.
+// The provider's token environment variable takes precedence over the configuration file.
+func (c *Config) Provider(name, defaultHost string) Provider {
+	p := c.Providers[name]
+	if p.Host == "" {
+		p.Host = defaultHost
+	}
+	if env, ok := providerTokenEnvVars[name]; ok {
+		if token := os.Getenv(env); token != "" {
+			p.Token = token
+		}
+	}
+	return p
+}
+
+// ProviderFor returns the settings for the named provider on host. An empty host or the
+// provider's configured host yields Provider(name, defaultHost); other hosts take their
+// token from the [hosts] section.
+func (c *Config) ProviderFor(name, host, defaultHost string) Provider {
+	p := c.Provider(name, defaultHost)
+	if host == "" || host == p.Host {
+		return p
+	}
+	return Provider{Host: host, Token: c.Hosts[host].Token}
+}
+
+// Token returns the GitHub API token for the given host.
+// Environment variables take precedence over the configuration file; GH_ENTERPRISE_TOKEN
+// and GITHUB_ENTERPRISE_TOKEN apply to hosts other than github.com.
+func (c *Config) Token(host string) string {
+	envVars := tokenEnvVars
+	if host != githubHost {
+		envVars = enterpriseTokenEnvVars
+	}
+	for _, name := range envVars {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+
+	if h, ok := c.Hosts[host]; ok {
+		return h.Token
+	}
+	return ""
+}
+
+// GitClones returns the local clone path of every repository whose commit source is git.
+// Repositories without a path map to "".
+func (c *Config) GitClones() map[string]string {
+	clones := make(map[string]string)
+	for name, repo := range c.Repositories {
+		if repo.CommitSource == "git" {
+			clones[name] = repo.Path
+		}
+	}
+	return clones
+}
+
+// LoadTeams reads the [teams] section of a team definition file, which uses the format of the
+// configuration file. Unlike LoadFile, a missing file is an error.
+func LoadTeams(path string) (map[string]Team, error) {
+	cfg := &Config{}
+	if _, err := toml.DecodeFile(path, cfg); err != nil {
+		return nil, err
+	}
+	return cfg.Teams, nil
+}
+
+// Users returns the logins of the team's members followed by their aliases
+func (t Team) Users() []string {
+	users := append([]string{}, t.Members...)
+	for _, aliases := range t.Aliases {
+		users = append(users, aliases...)
+	}
+	return users
+}
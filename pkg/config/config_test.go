@@ -0,0 +1,124 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/config"
+)
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg, err := config.LoadFile(filepath.Join(t.TempDir(), "missing.toml"))
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.Hosts)
+}
+
+func TestConfig_Token(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	err := os.WriteFile(path, []byte("[hosts.\"github.com\"]\ntoken = \"from-file\"\n"), 0o600)
+	assert.NoError(t, err)
+
+	cfg, err := config.LoadFile(path)
+	assert.NoError(t, err)
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+	assert.Equal(t, "from-file", cfg.Token("github.com"))
+	assert.Equal(t, "", cfg.Token("example.com"))
+
+	t.Setenv("GH_TOKEN", "from-env")
+	assert.Equal(t, "from-env", cfg.Token("github.com"))
+	assert.Equal(t, "", cfg.Token("example.com"))
+
+	t.Setenv("GH_ENTERPRISE_TOKEN", "enterprise")
+	assert.Equal(t, "enterprise", cfg.Token("example.com"))
+}
+
+func TestConfig_GitClones(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+[repositories."o/local"]
+commit_source = "git"
+path = "/src/local"
+
+[repositories."o/mirror"]
+commit_source = "git"
+
+[repositories."o/api"]
+commit_source = "api"
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, err := config.LoadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"o/local": "/src/local", "o/mirror": ""}, cfg.GitClones())
+}
+
+func TestConfig_Provider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+[providers.gitlab]
+host = "gitlab.example.com"
+token = "from-file"
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, err := config.LoadFile(path)
+	assert.NoError(t, err)
+
+	t.Setenv("GITLAB_TOKEN", "")
+	assert.Equal(t, config.Provider{Host: "gitlab.example.com", Token: "from-file"}, cfg.Provider("gitlab", "gitlab.com"))
+	assert.Equal(t, config.Provider{Host: "gitea.com"}, cfg.Provider("gitea", "gitea.com"))
+
+	t.Setenv("GITLAB_TOKEN", "from-env")
+	assert.Equal(t, "from-env", cfg.Provider("gitlab", "gitlab.com").Token)
+}
+
+func TestConfig_ProviderFor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+[providers.gitlab]
+host = "gitlab.example.com"
+token = "provider-token"
+
+[hosts."gitlab.other.com"]
+token = "host-token"
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cfg, err := config.LoadFile(path)
+	assert.NoError(t, err)
+
+	t.Setenv("GITLAB_TOKEN", "")
+	assert.Equal(t, "provider-token", cfg.ProviderFor("gitlab", "", "gitlab.com").Token)
+	assert.Equal(t, "provider-token", cfg.ProviderFor("gitlab", "gitlab.example.com", "gitlab.com").Token)
+	assert.Equal(t, config.Provider{Host: "gitlab.other.com", Token: "host-token"}, cfg.ProviderFor("gitlab", "gitlab.other.com", "gitlab.com"))
+}
+
+func TestLoadFile_Bots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	err := os.WriteFile(path, []byte("bots = [\"renovate\", \"deploy-user\"]\n\n[hosts.\"github.com\"]\ntoken = \"t\"\n"), 0o600)
+	assert.NoError(t, err)
+
+	cfg, err := config.LoadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"renovate", "deploy-user"}, cfg.Bots)
+}
+
+func TestLoadTeams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "teams.toml")
+	err := os.WriteFile(path, []byte("[teams.backend]\nmembers = [\"alice\", \"bob\"]\n\n[teams.backend.aliases]\nalice = [\"Alice Smith\"]\n\n[teams.frontend]\nmembers = [\"carol\"]\n"), 0o600)
+	assert.NoError(t, err)
+
+	teams, err := config.LoadTeams(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob", "Alice Smith"}, teams["backend"].Users())
+	assert.Equal(t, []string{"carol"}, teams["frontend"].Users())
+
+	_, err = config.LoadTeams(filepath.Join(t.TempDir(), "missing.toml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"yokiyoki/pkg/models"
+)
+
+// CICsv handles CSV formatting of CI metrics
+type CICsv struct {
+	metrics []models.CIMetrics
+}
+
+// NewCICsv creates a new CICsv formatter
+func NewCICsv(metrics []models.CIMetrics) *CICsv {
+	return &CICsv{metrics: metrics}
+}
+
+// Output outputs CI metrics in CSV format
+func (c *CICsv) Output(byBranch bool) {
+	if len(c.metrics) == 0 {
+		return
+	}
+
+	headers := []string{"Repository", "Workflow"}
+	if byBranch {
+		headers = append(headers, "Branch")
+	}
+	headers = append(headers, "Runs", "SuccessRate", "FailureRate", "CancelRate",
+		"MedianDurationSeconds", "P90DurationSeconds", "FlakyRate", "AvgQueueTimeSeconds", "Incomplete")
+	fmt.Println(strings.Join(headers, ","))
+
+	for _, m := range c.metrics {
+		values := []string{m.Repository, escapeCsvField(m.Workflow)}
+		if byBranch {
+			values = append(values, escapeCsvField(m.Branch))
+		}
+		values = append(values,
+			fmt.Sprintf("%d", m.Runs),
+			m.SuccessRate,
+			m.FailureRate,
+			m.CancelRate,
+			formatSeconds(m.Duration, m.Duration.Median),
+			formatSeconds(m.Duration, m.Duration.P90),
+			m.FlakyRate,
+			formatSeconds(m.QueueTime, m.QueueTime.Mean),
+			fmt.Sprintf("%t", m.Incomplete),
+		)
+		fmt.Println(strings.Join(values, ","))
+	}
+}
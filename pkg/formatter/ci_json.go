@@ -0,0 +1,63 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"yokiyoki/pkg/models"
+)
+
+// CIJson handles JSON formatting of CI metrics
+type CIJson struct {
+	metrics []models.CIMetrics
+}
+
+// NewCIJson creates a new CIJson formatter
+func NewCIJson(metrics []models.CIMetrics) *CIJson {
+	return &CIJson{metrics: metrics}
+}
+
+// Output outputs CI metrics in JSON format
+func (j *CIJson) Output() {
+	if len(j.metrics) == 0 {
+		return
+	}
+
+	type ciRow struct {
+		Repository  string               `json:"repository"`
+		Workflow    string               `json:"workflow"`
+		Branch      string               `json:"branch,omitempty"`
+		Runs        int                  `json:"runs"`
+		SuccessRate string               `json:"success_rate"`
+		FailureRate string               `json:"failure_rate"`
+		CancelRate  string               `json:"cancel_rate"`
+		Duration    *durationSummaryJson `json:"duration"`
+		FlakyRate   string               `json:"flaky_rate"`
+		QueueTime   *durationSummaryJson `json:"queue_time"`
+		Incomplete  bool                 `json:"incomplete,omitempty"`
+	}
+
+	rows := make([]ciRow, 0, len(j.metrics))
+	for _, m := range j.metrics {
+		rows = append(rows, ciRow{
+			Repository:  m.Repository,
+			Workflow:    m.Workflow,
+			Branch:      m.Branch,
+			Runs:        m.Runs,
+			SuccessRate: m.SuccessRate,
+			FailureRate: m.FailureRate,
+			CancelRate:  m.CancelRate,
+			Duration:    toDurationSummaryJson(m.Duration),
+			FlakyRate:   m.FlakyRate,
+			QueueTime:   toDurationSummaryJson(m.QueueTime),
+			Incomplete:  m.Incomplete,
+		})
+	}
+
+	out, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		fmt.Printf("Error encoding JSON: %v\n", err)
+		return
+	}
+	fmt.Println(string(out))
+}
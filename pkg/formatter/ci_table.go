@@ -0,0 +1,130 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// CITable handles markdown table formatting of CI metrics
+type CITable struct {
+	metrics []models.CIMetrics
+}
+
+// NewCITable creates a new CITable formatter
+func NewCITable(metrics []models.CIMetrics) *CITable {
+	return &CITable{metrics: metrics}
+}
+
+// Output outputs CI metrics in markdown table format
+func (t *CITable) Output(byBranch bool) {
+	tableData := t.buildTableData(byBranch)
+	columns := t.createColumns(byBranch)
+	t.calculateColumnWidths(columns, tableData)
+	t.outputTable(tableData, columns)
+}
+
+func (t *CITable) buildTableData(byBranch bool) [][]string {
+	tableData := make([][]string, len(t.metrics))
+	for i, m := range t.metrics {
+		tableData[i] = t.toRow(m, byBranch)
+	}
+	return tableData
+}
+
+func (t *CITable) toRow(m models.CIMetrics, byBranch bool) []string {
+	repository := m.Repository
+	if m.Incomplete {
+		repository += incompleteMarker
+	}
+	row := []string{repository, m.Workflow}
+	if byBranch {
+		row = append(row, m.Branch)
+	}
+	return append(row,
+		fmt.Sprintf("%d", m.Runs),
+		t.formatValue(m.SuccessRate),
+		t.formatValue(m.FailureRate),
+		t.formatValue(m.CancelRate),
+		t.formatDuration(m.Duration, m.Duration.Median),
+		t.formatDuration(m.Duration, m.Duration.P90),
+		t.formatValue(m.FlakyRate),
+		t.formatDuration(m.QueueTime, m.QueueTime.Mean),
+	)
+}
+
+func (t *CITable) createColumns(byBranch bool) []MetricsTableColumn {
+	columns := []MetricsTableColumn{
+		{Header: "Repository", Align: "left"},
+		{Header: "Workflow", Align: "left"},
+	}
+	if byBranch {
+		columns = append(columns, MetricsTableColumn{Header: "Branch", Align: "left"})
+	}
+	return append(columns,
+		MetricsTableColumn{Header: "Runs", Align: "right"},
+		MetricsTableColumn{Header: "Success", Align: "right"},
+		MetricsTableColumn{Header: "Failure", Align: "right"},
+		MetricsTableColumn{Header: "Cancelled", Align: "right"},
+		MetricsTableColumn{Header: "Median Duration", Align: "left"},
+		MetricsTableColumn{Header: "P90 Duration", Align: "left"},
+		MetricsTableColumn{Header: "Flaky", Align: "right"},
+		MetricsTableColumn{Header: "Avg Queue", Align: "left"},
+	)
+}
+
+func (t *CITable) calculateColumnWidths(columns []MetricsTableColumn, tableData [][]string) {
+	for i, col := range columns {
+		columns[i].Width = len(col.Header)
+		for _, row := range tableData {
+			if i >= len(row) || len(row[i]) <= columns[i].Width {
+				continue
+			}
+			columns[i].Width = len(row[i])
+		}
+	}
+}
+
+func (t *CITable) outputTable(tableData [][]string, columns []MetricsTableColumn) {
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf(" %-*s |", col.Width, col.Header)
+	}
+	fmt.Println()
+
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf("%s|", strings.Repeat("-", col.Width+2))
+	}
+	fmt.Println()
+
+	for _, row := range tableData {
+		fmt.Print("|")
+		for i, col := range columns {
+			if col.Align == "right" {
+				fmt.Printf(" %*s |", col.Width, row[i])
+			} else {
+				fmt.Printf(" %-*s |", col.Width, row[i])
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func (t *CITable) formatValue(value string) string {
+	if value == "None" {
+		return "-"
+	}
+	return value
+}
+
+// formatDuration formats one statistic of the summary to the second, or "-" when there were no durations
+func (t *CITable) formatDuration(s models.DurationSummary, d time.Duration) string {
+	if s.Count == 0 {
+		return "-"
+	}
+	return FormatShortDuration(d)
+}
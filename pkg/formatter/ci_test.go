@@ -0,0 +1,61 @@
+package formatter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/formatter"
+	"yokiyoki/pkg/models"
+)
+
+func sampleCIMetrics() []models.CIMetrics {
+	return []models.CIMetrics{
+		{
+			Repository:  "owner/repo",
+			Workflow:    "CI",
+			Branch:      "main",
+			Runs:        12,
+			SuccessRate: "75%",
+			FailureRate: "17%",
+			CancelRate:  "8%",
+			Duration:    models.DurationSummary{Count: 9, Mean: 5 * time.Minute, Median: 250 * time.Second, P90: 542 * time.Second},
+			FlakyRate:   "8%",
+		},
+	}
+}
+
+func TestCITable_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewCITable(sampleCIMetrics()).Output(true)
+	})
+
+	assert.Contains(t, output, "| Repository | Workflow | Branch | Runs | Success | Failure | Cancelled | Median Duration | P90 Duration | Flaky | Avg Queue |")
+	assert.Contains(t, output, "| owner/repo | CI       | main   |   12 |     75% |     17% |        8% | 4m 10s          | 9m 02s       |    8% | -         |")
+
+	output = captureOutput(func() {
+		formatter.NewCITable(sampleCIMetrics()).Output(false)
+	})
+	assert.NotContains(t, output, "Branch")
+}
+
+func TestCICsv_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewCICsv(sampleCIMetrics()).Output(true)
+	})
+
+	assert.Equal(t, "Repository,Workflow,Branch,Runs,SuccessRate,FailureRate,CancelRate,MedianDurationSeconds,P90DurationSeconds,FlakyRate,AvgQueueTimeSeconds,Incomplete\n"+
+		"owner/repo,CI,main,12,75%,17%,8%,250,542,8%,,false\n", output)
+}
+
+func TestCIJson_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewCIJson(sampleCIMetrics()).Output()
+	})
+
+	assert.Contains(t, output, `"workflow": "CI"`)
+	assert.Contains(t, output, `"branch": "main"`)
+	assert.Contains(t, output, `"p90": 542`)
+	assert.Contains(t, output, `"queue_time": null`)
+	assert.NotContains(t, output, `"incomplete"`)
+}
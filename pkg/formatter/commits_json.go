@@ -25,23 +25,27 @@ func (j *CommitsJson) Output(detailedStats bool) {
 	}
 
 	type commitRow struct {
-		Repository string    `json:"repository"`
-		SHA        string    `json:"sha"`
-		Author     string    `json:"author"`
-		Date       time.Time `json:"date"`
-		Message    string    `json:"message"`
-		Additions  *int      `json:"additions,omitempty"`
-		Deletions  *int      `json:"deletions,omitempty"`
+		Repository  string    `json:"repository"`
+		SHA         string    `json:"sha"`
+		Author      string    `json:"author"`
+		AuthorName  string    `json:"author_name,omitempty"`
+		AuthorEmail string    `json:"author_email,omitempty"`
+		Date        time.Time `json:"date"`
+		Message     string    `json:"message"`
+		Additions   *int      `json:"additions,omitempty"`
+		Deletions   *int      `json:"deletions,omitempty"`
 	}
 
 	rows := make([]commitRow, 0, len(j.commits))
 	for _, c := range j.commits {
 		row := commitRow{
-			Repository: c.Repository,
-			SHA:        shortSHA(c.SHA),
-			Author:     c.Author,
-			Date:       c.Date,
-			Message:    c.Message,
+			Repository:  c.Repository,
+			SHA:         shortSHA(c.SHA),
+			Author:      c.Author,
+			AuthorName:  c.AuthorName,
+			AuthorEmail: c.AuthorEmail,
+			Date:        c.Date,
+			Message:     c.Message,
 		}
 		if detailedStats {
 			additions := c.Additions
@@ -0,0 +1,65 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"yokiyoki/pkg/models"
+)
+
+// ComparisonCsv handles CSV formatting of metrics compared between two periods
+type ComparisonCsv struct {
+	comparisons []models.MetricComparison
+}
+
+// NewComparisonCsv creates a new ComparisonCsv formatter
+func NewComparisonCsv(comparisons []models.MetricComparison) *ComparisonCsv {
+	return &ComparisonCsv{comparisons: comparisons}
+}
+
+// Output outputs the compared metrics in CSV format, one row per metric. Values are in their unit,
+// times in seconds, and are left empty when they could not be measured.
+func (c *ComparisonCsv) Output(byUser bool) {
+	if len(c.comparisons) == 0 {
+		return
+	}
+
+	headers := []string{"Repository"}
+	if byUser {
+		headers = append(headers, "User")
+	}
+	if comparesTeams(c.comparisons) {
+		headers = append(headers, "Team")
+	}
+	headers = append(headers, "Metric", "Unit", "Previous", "Current", "Delta", "ChangePercent", "Incomplete")
+	fmt.Println(strings.Join(headers, ","))
+
+	for _, m := range c.comparisons {
+		values := []string{m.Repository}
+		if byUser {
+			values = append(values, m.User)
+		}
+		if m.Team != "" {
+			values = append(values, m.Team)
+		}
+		delta, hasDelta := m.Delta()
+		change, hasChange := m.Change()
+		values = append(values,
+			m.Metric,
+			m.Unit,
+			c.formatOptional(deref(m.Previous)),
+			c.formatOptional(deref(m.Current)),
+			c.formatOptional(delta, hasDelta),
+			c.formatOptional(change, hasChange),
+			fmt.Sprintf("%t", m.Incomplete),
+		)
+		fmt.Println(strings.Join(values, ","))
+	}
+}
+
+func (c *ComparisonCsv) formatOptional(value float64, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return formatCount(value)
+}
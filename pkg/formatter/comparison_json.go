@@ -0,0 +1,82 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"yokiyoki/pkg/models"
+)
+
+// ComparisonJson handles JSON formatting of metrics compared between two periods
+type ComparisonJson struct {
+	comparisons []models.MetricComparison
+}
+
+// NewComparisonJson creates a new ComparisonJson formatter
+func NewComparisonJson(comparisons []models.MetricComparison) *ComparisonJson {
+	return &ComparisonJson{comparisons: comparisons}
+}
+
+// Output outputs the compared metrics in JSON format, one object per metric. Values are in their
+// unit, times in seconds, and are null when they could not be measured.
+func (j *ComparisonJson) Output(byUser bool) {
+	if len(j.comparisons) == 0 {
+		return
+	}
+
+	type comparisonRow struct {
+		Repository    string   `json:"repository"`
+		User          string   `json:"user,omitempty"`
+		Team          string   `json:"team,omitempty"`
+		Metric        string   `json:"metric"`
+		Unit          string   `json:"unit"`
+		Previous      *float64 `json:"previous"`
+		Current       *float64 `json:"current"`
+		Delta         *float64 `json:"delta"`
+		ChangePercent *float64 `json:"change_percent"`
+		Incomplete    bool     `json:"incomplete,omitempty"`
+	}
+
+	round := func(value float64, ok bool) *float64 {
+		if !ok {
+			return nil
+		}
+		rounded := math.Round(value*100) / 100
+		return &rounded
+	}
+
+	rows := make([]comparisonRow, 0, len(j.comparisons))
+	for _, m := range j.comparisons {
+		row := comparisonRow{
+			Repository:    m.Repository,
+			Team:          m.Team,
+			Metric:        m.Metric,
+			Unit:          m.Unit,
+			Previous:      round(deref(m.Previous)),
+			Current:       round(deref(m.Current)),
+			Delta:         round(m.Delta()),
+			ChangePercent: round(m.Change()),
+			Incomplete:    m.Incomplete,
+		}
+		if byUser {
+			row.User = m.User
+		}
+		rows = append(rows, row)
+	}
+
+	out, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		fmt.Printf("Error encoding JSON: %v\n", err)
+		return
+	}
+	fmt.Println(string(out))
+}
+
+// deref returns the value and whether there is one
+func deref(value *float64) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
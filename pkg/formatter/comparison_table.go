@@ -0,0 +1,189 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"yokiyoki/pkg/models"
+)
+
+// Markers of the direction a compared metric moved in
+const (
+	upMarker   = "▲"
+	downMarker = "▼"
+)
+
+// ComparisonTable handles markdown table formatting of metrics compared between two periods
+type ComparisonTable struct {
+	comparisons []models.MetricComparison
+}
+
+// comparesTeams reports whether the compared metrics are broken down by team
+func comparesTeams(comparisons []models.MetricComparison) bool {
+	return len(comparisons) > 0 && comparisons[0].Team != ""
+}
+
+// NewComparisonTable creates a new ComparisonTable formatter
+func NewComparisonTable(comparisons []models.MetricComparison) *ComparisonTable {
+	return &ComparisonTable{comparisons: comparisons}
+}
+
+// Output outputs the compared metrics in markdown table format, one row per metric
+func (t *ComparisonTable) Output(byUser bool) {
+	tableData := t.buildTableData(byUser)
+	columns := t.createColumns(byUser)
+	t.calculateColumnWidths(columns, tableData)
+	t.outputTable(tableData, columns)
+}
+
+func (t *ComparisonTable) buildTableData(byUser bool) [][]string {
+	tableData := make([][]string, len(t.comparisons))
+	for i, c := range t.comparisons {
+		tableData[i] = t.toRow(c, byUser)
+	}
+	return tableData
+}
+
+func (t *ComparisonTable) toRow(c models.MetricComparison, byUser bool) []string {
+	repository := c.Repository
+	if c.Incomplete {
+		repository += incompleteMarker
+	}
+	row := []string{repository}
+	if byUser {
+		row = append(row, c.User)
+	}
+	if c.Team != "" {
+		row = append(row, c.Team)
+	}
+	return append(row,
+		c.Metric,
+		t.formatValue(c.Unit, c.Previous),
+		t.formatValue(c.Unit, c.Current),
+		t.formatDelta(c),
+		t.formatChange(c),
+	)
+}
+
+func (t *ComparisonTable) createColumns(byUser bool) []MetricsTableColumn {
+	columns := []MetricsTableColumn{{Header: "Repository", Align: "left"}}
+	if byUser {
+		columns = append(columns, MetricsTableColumn{Header: "User", Align: "left"})
+	}
+	if comparesTeams(t.comparisons) {
+		columns = append(columns, MetricsTableColumn{Header: "Team", Align: "left"})
+	}
+	return append(columns,
+		MetricsTableColumn{Header: "Metric", Align: "left"},
+		MetricsTableColumn{Header: "Previous", Align: "right"},
+		MetricsTableColumn{Header: "Current", Align: "right"},
+		MetricsTableColumn{Header: "Delta", Align: "right"},
+		MetricsTableColumn{Header: "Change", Align: "right"},
+	)
+}
+
+// formatValue formats a value in its unit, or "-" when it could not be measured
+func (t *ComparisonTable) formatValue(unit string, value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	switch unit {
+	case models.UnitPercent:
+		return fmt.Sprintf("%.0f%%", *value)
+	case models.UnitSeconds:
+		return FormatDuration(time.Duration(*value * float64(time.Second)))
+	default:
+		return formatCount(*value)
+	}
+}
+
+// formatDelta formats the signed difference between the periods; rates differ in percentage points
+func (t *ComparisonTable) formatDelta(c models.MetricComparison) string {
+	delta, ok := c.Delta()
+	if !ok {
+		return "-"
+	}
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+	}
+	switch c.Unit {
+	case models.UnitPercent:
+		return fmt.Sprintf("%s%.0fpt", sign, math.Abs(delta))
+	case models.UnitSeconds:
+		return sign + FormatDuration(time.Duration(math.Abs(delta)*float64(time.Second)))
+	default:
+		return sign + formatCount(math.Abs(delta))
+	}
+}
+
+// formatChange formats the percentage change with a marker of its direction
+func (t *ComparisonTable) formatChange(c models.MetricComparison) string {
+	change, ok := c.Change()
+	if !ok {
+		return "-"
+	}
+	rounded := math.Round(change)
+	switch {
+	case rounded > 0:
+		return fmt.Sprintf("%s +%.0f%%", upMarker, rounded)
+	case rounded < 0:
+		return fmt.Sprintf("%s %.0f%%", downMarker, rounded)
+	default:
+		return "0%"
+	}
+}
+
+func (t *ComparisonTable) calculateColumnWidths(columns []MetricsTableColumn, tableData [][]string) {
+	for i, col := range columns {
+		columns[i].Width = utf8.RuneCountInString(col.Header)
+		for _, row := range tableData {
+			if i >= len(row) || utf8.RuneCountInString(row[i]) <= columns[i].Width {
+				continue
+			}
+			columns[i].Width = utf8.RuneCountInString(row[i])
+		}
+	}
+}
+
+func (t *ComparisonTable) outputTable(tableData [][]string, columns []MetricsTableColumn) {
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf(" %s |", t.pad(col.Header, col.Width, "left"))
+	}
+	fmt.Println()
+
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf("%s|", strings.Repeat("-", col.Width+2))
+	}
+	fmt.Println()
+
+	for _, row := range tableData {
+		fmt.Print("|")
+		for i, col := range columns {
+			fmt.Printf(" %s |", t.pad(row[i], col.Width, col.Align))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+
+	for _, c := range t.comparisons {
+		if c.Incomplete {
+			fmt.Printf("%s data incomplete: some requests failed, so these rows may undercount\n\n", strings.TrimSpace(incompleteMarker))
+			return
+		}
+	}
+}
+
+// pad pads a cell to width by characters rather than bytes, since the markers take several bytes
+func (t *ComparisonTable) pad(value string, width int, align string) string {
+	padding := strings.Repeat(" ", max(width-utf8.RuneCountInString(value), 0))
+	if align == "right" {
+		return padding + value
+	}
+	return value + padding
+}
@@ -0,0 +1,62 @@
+package formatter_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/formatter"
+	"yokiyoki/pkg/models"
+)
+
+func sampleComparisons() []models.MetricComparison {
+	value := func(v float64) *float64 { return &v }
+	return []models.MetricComparison{
+		{Repository: "owner/repo", User: "alice", Metric: "Commits", Unit: models.UnitCount, Current: value(12), Previous: value(8)},
+		{Repository: "owner/repo", User: "alice", Metric: "PR Merge Rate", Unit: models.UnitPercent, Current: value(75), Previous: value(100)},
+		{Repository: "owner/repo", User: "alice", Metric: "PR Merge Time", Unit: models.UnitSeconds, Current: value(7200), Previous: value(10800)},
+		{Repository: "owner/repo", User: "alice", Metric: "Issue Resolve Time", Unit: models.UnitSeconds, Current: value(3600)},
+	}
+}
+
+func TestComparisonTable_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewComparisonTable(sampleComparisons()).Output(true)
+	})
+
+	lines := strings.Split(output, "\n")
+	assert.Equal(t, "| Repository | User  | Metric             | Previous   | Current    | Delta       | Change |", lines[0])
+	assert.Equal(t, "| owner/repo | alice | Commits            |          8 |         12 |          +4 | ▲ +50% |", lines[2])
+	assert.Equal(t, "| owner/repo | alice | PR Merge Rate      |       100% |        75% |       -25pt | ▼ -25% |", lines[3])
+	assert.Equal(t, "| owner/repo | alice | PR Merge Time      | 0d 03h 00m | 0d 02h 00m | -0d 01h 00m | ▼ -33% |", lines[4])
+	assert.Equal(t, "| owner/repo | alice | Issue Resolve Time |          - | 0d 01h 00m |           - |      - |", lines[5])
+}
+
+func TestComparisonCsv_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewComparisonCsv(sampleComparisons()).Output(false)
+	})
+
+	assert.Equal(t, "Repository,Metric,Unit,Previous,Current,Delta,ChangePercent,Incomplete\n"+
+		"owner/repo,Commits,count,8,12,4,50,false\n"+
+		"owner/repo,PR Merge Rate,percent,100,75,-25,-25,false\n"+
+		"owner/repo,PR Merge Time,seconds,10800,7200,-3600,-33.33,false\n"+
+		"owner/repo,Issue Resolve Time,seconds,,3600,,,false\n", output)
+}
+
+func TestComparisonJson_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewComparisonJson(sampleComparisons()).Output(true)
+	})
+
+	var result []map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Len(t, result, 4)
+	assert.Equal(t, "alice", result[0]["user"])
+	assert.Equal(t, 4.0, result[0]["delta"])
+	assert.Equal(t, 50.0, result[0]["change_percent"])
+	assert.Equal(t, -33.33, result[2]["change_percent"])
+	assert.Nil(t, result[3]["previous"])
+	assert.Nil(t, result[3]["change_percent"])
+}
@@ -24,7 +24,8 @@ func (c *ConversationsCsv) Output() {
 		return
 	}
 
-	headers := []string{"Repository", "Type", "Number", "Title", "Author", "Date", "Body", "URL"}
+	headers := []string{"Repository", "Type", "Number", "Title", "Author", "Date", "Body", "URL",
+		"SubType", "Path", "Line", "InReplyTo", "DiffHunk"}
 	fmt.Println(strings.Join(headers, ","))
 
 	for _, comment := range c.comments {
@@ -42,6 +43,19 @@ func (c *ConversationsCsv) toCSV(comment models.Comment) string {
 		comment.CreatedAt.Format(time.RFC3339),
 		escapeCsvField(comment.Body),
 		comment.URL,
+		comment.SubType,
+		escapeCsvField(comment.Path),
+		optionalInt(int64(comment.Line)),
+		optionalInt(comment.InReplyTo),
+		escapeCsvField(comment.DiffHunk),
 	}
 	return strings.Join(values, ",")
 }
+
+// optionalInt formats n, leaving zero values empty
+func optionalInt(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", n)
+}
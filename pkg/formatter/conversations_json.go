@@ -27,12 +27,18 @@ func (j *ConversationsJson) Output() {
 	type commentRow struct {
 		Repository string    `json:"repository"`
 		Type       string    `json:"type"`
+		SubType    string    `json:"sub_type,omitempty"`
 		Number     int       `json:"number"`
 		Title      string    `json:"title"`
 		Author     string    `json:"author"`
 		CreatedAt  time.Time `json:"created_at"`
 		Body       string    `json:"body"`
 		URL        string    `json:"url"`
+		ID         int64     `json:"id,omitempty"`
+		Path       string    `json:"path,omitempty"`
+		Line       int       `json:"line,omitempty"`
+		DiffHunk   string    `json:"diff_hunk,omitempty"`
+		InReplyTo  int64     `json:"in_reply_to,omitempty"`
 	}
 
 	rows := make([]commentRow, 0, len(j.comments))
@@ -40,12 +46,18 @@ func (j *ConversationsJson) Output() {
 		rows = append(rows, commentRow{
 			Repository: c.Repository,
 			Type:       c.Type,
+			SubType:    c.SubType,
 			Number:     c.Number,
 			Title:      c.Title,
 			Author:     c.Author,
 			CreatedAt:  c.CreatedAt,
 			Body:       c.Body,
 			URL:        c.URL,
+			ID:         c.ID,
+			Path:       c.Path,
+			Line:       c.Line,
+			DiffHunk:   c.DiffHunk,
+			InReplyTo:  c.InReplyTo,
 		})
 	}
 
@@ -43,22 +43,41 @@ func (t *ConversationsTable) toRow(c models.Comment) []string {
 	return []string{
 		c.Repository,
 		c.Type,
+		c.SubType,
 		fmt.Sprintf("%d", c.Number),
 		c.Title,
 		c.Author,
 		c.CreatedAt.Format("2006-01-02 15:04"),
+		location(c),
 		truncateMessage(c.Body),
 	}
 }
 
+// location returns path:line of an inline review comment, marking replies
+func location(c models.Comment) string {
+	if c.Path == "" {
+		return ""
+	}
+	loc := c.Path
+	if c.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", c.Path, c.Line)
+	}
+	if c.InReplyTo != 0 {
+		loc = "↳ " + loc
+	}
+	return loc
+}
+
 func (t *ConversationsTable) createColumns() []ConversationsTableColumn {
 	return []ConversationsTableColumn{
 		{Header: "Repository"},
 		{Header: "Type"},
+		{Header: "Subtype"},
 		{Header: "#"},
 		{Header: "Title"},
 		{Header: "Author"},
 		{Header: "Date"},
+		{Header: "Location"},
 		{Header: "Body"},
 	}
 }
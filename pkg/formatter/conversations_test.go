@@ -37,6 +37,25 @@ func sampleComments() []models.Comment {
 	}
 }
 
+func sampleReviewComment() models.Comment {
+	return models.Comment{
+		Repository: "owner/repo",
+		Type:       "pr",
+		SubType:    models.CommentReviewComment,
+		Number:     1,
+		Title:      "Fix login bug",
+		Author:     "carol",
+		Body:       "Nit: rename",
+		URL:        "https://github.com/owner/repo/pull/1#discussion_r7",
+		CreatedAt:  time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+		ID:         8,
+		Path:       "auth/login.go",
+		Line:       42,
+		DiffHunk:   "@@ -40,3 +40,3 @@\n-old\n+new",
+		InReplyTo:  7,
+	}
+}
+
 func captureOutput(fn func()) string {
 	old := os.Stdout
 	r, w, _ := os.Pipe()
@@ -73,6 +92,16 @@ func TestConversationsTable_Output(t *testing.T) {
 	assert.Contains(t, output, "This PR fixes the login issue.")
 }
 
+func TestConversationsTable_Output_ReviewComment(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewConversationsTable([]models.Comment{sampleReviewComment()}).Output()
+	})
+
+	assert.Contains(t, output, "Subtype")
+	assert.Contains(t, output, "review_comment")
+	assert.Contains(t, output, "↳ auth/login.go:42")
+}
+
 func TestConversationsTable_Output_Empty(t *testing.T) {
 	output := captureOutput(func() {
 		table := formatter.NewConversationsTable([]models.Comment{})
@@ -100,6 +129,15 @@ func TestConversationsCsv_Output(t *testing.T) {
 	assert.Contains(t, output, "pr")
 }
 
+func TestConversationsCsv_Output_ReviewComment(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewConversationsCsv([]models.Comment{sampleReviewComment()}).Output()
+	})
+
+	assert.Contains(t, output, "URL,SubType,Path,Line,InReplyTo,DiffHunk")
+	assert.Contains(t, output, "review_comment,auth/login.go,42,7,")
+}
+
 func TestConversationsCsv_Output_Empty(t *testing.T) {
 	output := captureOutput(func() {
 		csv := formatter.NewConversationsCsv([]models.Comment{})
@@ -128,6 +166,17 @@ func TestConversationsJson_Output(t *testing.T) {
 	assert.Contains(t, output, "alice")
 }
 
+func TestConversationsJson_Output_ReviewComment(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewConversationsJson([]models.Comment{sampleReviewComment()}).Output()
+	})
+
+	assert.Contains(t, output, `"sub_type": "review_comment"`)
+	assert.Contains(t, output, `"path": "auth/login.go"`)
+	assert.Contains(t, output, `"line": 42`)
+	assert.Contains(t, output, `"in_reply_to": 7`)
+}
+
 func TestConversationsJson_Output_Empty(t *testing.T) {
 	output := captureOutput(func() {
 		j := formatter.NewConversationsJson([]models.Comment{})
@@ -0,0 +1,43 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"yokiyoki/pkg/models"
+)
+
+// DeploymentsCsv handles CSV formatting of DORA metrics
+type DeploymentsCsv struct {
+	metrics []models.DeploymentMetrics
+}
+
+// NewDeploymentsCsv creates a new DeploymentsCsv formatter
+func NewDeploymentsCsv(metrics []models.DeploymentMetrics) *DeploymentsCsv {
+	return &DeploymentsCsv{metrics: metrics}
+}
+
+// Output outputs DORA metrics in CSV format
+func (c *DeploymentsCsv) Output() {
+	if len(c.metrics) == 0 {
+		return
+	}
+
+	headers := []string{"Repository", "Environment", "Deployments", "DeploymentFrequency",
+		"AvgLeadTimeSeconds", "ChangeFailureRate", "AvgTimeToRestoreSeconds", "Incomplete"}
+	fmt.Println(strings.Join(headers, ","))
+
+	for _, m := range c.metrics {
+		values := []string{
+			m.Repository,
+			escapeCsvField(m.Environment),
+			fmt.Sprintf("%d", m.Deployments),
+			m.DeploymentFrequency,
+			formatSeconds(m.LeadTime, m.LeadTime.Mean),
+			m.ChangeFailureRate,
+			formatSeconds(m.TimeToRestore, m.TimeToRestore.Mean),
+			fmt.Sprintf("%t", m.Incomplete),
+		}
+		fmt.Println(strings.Join(values, ","))
+	}
+}
@@ -0,0 +1,57 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"yokiyoki/pkg/models"
+)
+
+// DeploymentsJson handles JSON formatting of DORA metrics
+type DeploymentsJson struct {
+	metrics []models.DeploymentMetrics
+}
+
+// NewDeploymentsJson creates a new DeploymentsJson formatter
+func NewDeploymentsJson(metrics []models.DeploymentMetrics) *DeploymentsJson {
+	return &DeploymentsJson{metrics: metrics}
+}
+
+// Output outputs DORA metrics in JSON format
+func (j *DeploymentsJson) Output() {
+	if len(j.metrics) == 0 {
+		return
+	}
+
+	type deploymentsRow struct {
+		Repository          string               `json:"repository"`
+		Environment         string               `json:"environment"`
+		Deployments         int                  `json:"deployments"`
+		DeploymentFrequency string               `json:"deployment_frequency"`
+		LeadTime            *durationSummaryJson `json:"lead_time"`
+		ChangeFailureRate   string               `json:"change_failure_rate"`
+		TimeToRestore       *durationSummaryJson `json:"time_to_restore"`
+		Incomplete          bool                 `json:"incomplete,omitempty"`
+	}
+
+	rows := make([]deploymentsRow, 0, len(j.metrics))
+	for _, m := range j.metrics {
+		rows = append(rows, deploymentsRow{
+			Repository:          m.Repository,
+			Environment:         m.Environment,
+			Deployments:         m.Deployments,
+			DeploymentFrequency: m.DeploymentFrequency,
+			LeadTime:            toDurationSummaryJson(m.LeadTime),
+			ChangeFailureRate:   m.ChangeFailureRate,
+			TimeToRestore:       toDurationSummaryJson(m.TimeToRestore),
+			Incomplete:          m.Incomplete,
+		})
+	}
+
+	out, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		fmt.Printf("Error encoding JSON: %v\n", err)
+		return
+	}
+	fmt.Println(string(out))
+}
@@ -0,0 +1,117 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// DeploymentsTable handles markdown table formatting of DORA metrics
+type DeploymentsTable struct {
+	metrics []models.DeploymentMetrics
+}
+
+// NewDeploymentsTable creates a new DeploymentsTable formatter
+func NewDeploymentsTable(metrics []models.DeploymentMetrics) *DeploymentsTable {
+	return &DeploymentsTable{metrics: metrics}
+}
+
+// Output outputs DORA metrics in markdown table format
+func (t *DeploymentsTable) Output() {
+	tableData := t.buildTableData()
+	columns := t.createColumns()
+	t.calculateColumnWidths(columns, tableData)
+	t.outputTable(tableData, columns)
+}
+
+func (t *DeploymentsTable) buildTableData() [][]string {
+	tableData := make([][]string, len(t.metrics))
+	for i, m := range t.metrics {
+		tableData[i] = t.toRow(m)
+	}
+	return tableData
+}
+
+func (t *DeploymentsTable) toRow(m models.DeploymentMetrics) []string {
+	repository := m.Repository
+	if m.Incomplete {
+		repository += incompleteMarker
+	}
+	return []string{
+		repository,
+		m.Environment,
+		fmt.Sprintf("%d", m.Deployments),
+		t.formatValue(m.DeploymentFrequency),
+		t.formatDuration(m.LeadTime, m.LeadTime.Mean),
+		t.formatValue(m.ChangeFailureRate),
+		t.formatDuration(m.TimeToRestore, m.TimeToRestore.Mean),
+	}
+}
+
+func (t *DeploymentsTable) createColumns() []MetricsTableColumn {
+	return []MetricsTableColumn{
+		{Header: "Repository", Align: "left"},
+		{Header: "Environment", Align: "left"},
+		{Header: "Deployments", Align: "right"},
+		{Header: "Frequency", Align: "left"},
+		{Header: "Lead Time", Align: "left"},
+		{Header: "Change Failure Rate", Align: "left"},
+		{Header: "Time to Restore", Align: "left"},
+	}
+}
+
+func (t *DeploymentsTable) calculateColumnWidths(columns []MetricsTableColumn, tableData [][]string) {
+	for i, col := range columns {
+		columns[i].Width = len(col.Header)
+		for _, row := range tableData {
+			if i >= len(row) || len(row[i]) <= columns[i].Width {
+				continue
+			}
+			columns[i].Width = len(row[i])
+		}
+	}
+}
+
+func (t *DeploymentsTable) outputTable(tableData [][]string, columns []MetricsTableColumn) {
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf(" %-*s |", col.Width, col.Header)
+	}
+	fmt.Println()
+
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf("%s|", strings.Repeat("-", col.Width+2))
+	}
+	fmt.Println()
+
+	for _, row := range tableData {
+		fmt.Print("|")
+		for i, col := range columns {
+			if col.Align == "right" {
+				fmt.Printf(" %*s |", col.Width, row[i])
+			} else {
+				fmt.Printf(" %-*s |", col.Width, row[i])
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func (t *DeploymentsTable) formatValue(value string) string {
+	if value == "None" {
+		return "-"
+	}
+	return value
+}
+
+// formatDuration formats one statistic of the summary, or "-" when there were no durations
+func (t *DeploymentsTable) formatDuration(s models.DurationSummary, d time.Duration) string {
+	if s.Count == 0 {
+		return "-"
+	}
+	return FormatDuration(d)
+}
@@ -0,0 +1,64 @@
+package formatter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/formatter"
+	"yokiyoki/pkg/models"
+)
+
+func sampleDeploymentMetrics() []models.DeploymentMetrics {
+	return []models.DeploymentMetrics{
+		{
+			Repository:          "owner/repo",
+			Environment:         "production",
+			Deployments:         12,
+			DeploymentFrequency: "0.40/day",
+			LeadTime:            models.DurationSummary{Count: 4, Mean: 26 * time.Hour, Median: 20 * time.Hour},
+			ChangeFailureRate:   "8%",
+			Incomplete:          true,
+		},
+	}
+}
+
+func TestDeploymentsTable_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewDeploymentsTable(sampleDeploymentMetrics()).Output()
+	})
+
+	assert.Contains(t, output, "| Repository     | Environment | Deployments | Frequency | Lead Time  | Change Failure Rate | Time to Restore |")
+	assert.Contains(t, output, "| owner/repo (!) | production  |          12 | 0.40/day  | 1d 02h 00m | 8%                  | -               |")
+}
+
+func TestDeploymentsCsv_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewDeploymentsCsv(sampleDeploymentMetrics()).Output()
+	})
+
+	assert.Equal(t, "Repository,Environment,Deployments,DeploymentFrequency,AvgLeadTimeSeconds,ChangeFailureRate,AvgTimeToRestoreSeconds,Incomplete\n"+
+		"owner/repo,production,12,0.40/day,93600,8%,,true\n", output)
+}
+
+func TestDeploymentsJson_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewDeploymentsJson(sampleDeploymentMetrics()).Output()
+	})
+
+	assert.Contains(t, output, `"environment": "production"`)
+	assert.Contains(t, output, `"deployment_frequency": "0.40/day"`)
+	assert.Contains(t, output, `"change_failure_rate": "8%"`)
+	assert.Contains(t, output, `"mean": 93600`)
+	assert.Contains(t, output, `"time_to_restore": null`)
+	assert.Contains(t, output, `"incomplete": true`)
+}
+
+func TestDeployments_Output_Empty(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewDeploymentsCsv(nil).Output()
+		formatter.NewDeploymentsJson(nil).Output()
+	})
+
+	assert.Empty(t, output)
+}
@@ -3,6 +3,8 @@ package formatter
 import (
 	"fmt"
 	"time"
+
+	"yokiyoki/pkg/models"
 )
 
 func FormatDuration(d time.Duration) string {
@@ -12,3 +14,56 @@ func FormatDuration(d time.Duration) string {
 
 	return fmt.Sprintf("%dd %02dh %02dm", days, hours, minutes)
 }
+
+// FormatShortDuration formats durations of minutes rather than days, such as CI runs, to the second
+func FormatShortDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	seconds := int(d.Seconds()) % 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%dh %02dm %02ds", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%dm %02ds", minutes, seconds)
+}
+
+// seconds converts a duration to whole seconds for machine-readable output
+func seconds(d time.Duration) int64 {
+	return int64(d.Round(time.Second) / time.Second)
+}
+
+// formatSeconds formats one statistic of the summary in seconds, or "" when there were no durations
+func formatSeconds(s models.DurationSummary, d time.Duration) string {
+	if s.Count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", seconds(d))
+}
+
+// durationSummaryJson is a DurationSummary in JSON output, with the statistics in seconds
+type durationSummaryJson struct {
+	Count  int   `json:"count"`
+	Mean   int64 `json:"mean"`
+	Min    int64 `json:"min"`
+	Median int64 `json:"median"`
+	P75    int64 `json:"p75"`
+	P90    int64 `json:"p90"`
+	Max    int64 `json:"max"`
+}
+
+// toDurationSummaryJson converts the summary for JSON output, returning nil when there were no durations
+func toDurationSummaryJson(s models.DurationSummary) *durationSummaryJson {
+	if s.Count == 0 {
+		return nil
+	}
+	return &durationSummaryJson{
+		Count:  s.Count,
+		Mean:   seconds(s.Mean),
+		Min:    seconds(s.Min),
+		Median: seconds(s.Median),
+		P75:    seconds(s.P75),
+		P90:    seconds(s.P90),
+		Max:    seconds(s.Max),
+	}
+}
@@ -58,3 +58,22 @@ func TestFormatDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatShortDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		want     string
+	}{
+		{name: "zero duration", duration: 0, want: "0m 00s"},
+		{name: "seconds", duration: 42*time.Second + 400*time.Millisecond, want: "0m 42s"},
+		{name: "minutes", duration: 7*time.Minute + 5*time.Second, want: "7m 05s"},
+		{name: "hours", duration: 26*time.Hour + 3*time.Second, want: "26h 00m 03s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatter.FormatShortDuration(tt.duration))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"yokiyoki/pkg/models"
+)
+
+// LabelsCsv handles CSV formatting of label metrics
+type LabelsCsv struct {
+	metrics []models.LabelMetrics
+}
+
+// NewLabelsCsv creates a new LabelsCsv formatter
+func NewLabelsCsv(metrics []models.LabelMetrics) *LabelsCsv {
+	return &LabelsCsv{metrics: metrics}
+}
+
+// Output outputs label metrics in CSV format
+func (c *LabelsCsv) Output() {
+	if len(c.metrics) == 0 {
+		return
+	}
+
+	headers := []string{"Repository", "Label", "Items", "AvgTimeInLabelSeconds", "Incomplete"}
+	fmt.Println(strings.Join(headers, ","))
+
+	for _, m := range c.metrics {
+		values := []string{
+			m.Repository,
+			escapeCsvField(m.Label),
+			fmt.Sprintf("%d", m.Items),
+			formatSeconds(m.TimeInLabel, m.TimeInLabel.Mean),
+			fmt.Sprintf("%t", m.Incomplete),
+		}
+		fmt.Println(strings.Join(values, ","))
+	}
+}
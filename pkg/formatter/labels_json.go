@@ -0,0 +1,51 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"yokiyoki/pkg/models"
+)
+
+// LabelsJson handles JSON formatting of label metrics
+type LabelsJson struct {
+	metrics []models.LabelMetrics
+}
+
+// NewLabelsJson creates a new LabelsJson formatter
+func NewLabelsJson(metrics []models.LabelMetrics) *LabelsJson {
+	return &LabelsJson{metrics: metrics}
+}
+
+// Output outputs label metrics in JSON format
+func (j *LabelsJson) Output() {
+	if len(j.metrics) == 0 {
+		return
+	}
+
+	type labelsRow struct {
+		Repository  string               `json:"repository"`
+		Label       string               `json:"label"`
+		Items       int                  `json:"items"`
+		TimeInLabel *durationSummaryJson `json:"time_in_label"`
+		Incomplete  bool                 `json:"incomplete,omitempty"`
+	}
+
+	rows := make([]labelsRow, 0, len(j.metrics))
+	for _, m := range j.metrics {
+		rows = append(rows, labelsRow{
+			Repository:  m.Repository,
+			Label:       m.Label,
+			Items:       m.Items,
+			TimeInLabel: toDurationSummaryJson(m.TimeInLabel),
+			Incomplete:  m.Incomplete,
+		})
+	}
+
+	out, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		fmt.Printf("Error encoding JSON: %v\n", err)
+		return
+	}
+	fmt.Println(string(out))
+}
@@ -0,0 +1,95 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"yokiyoki/pkg/models"
+)
+
+// LabelsTable handles markdown table formatting of label metrics
+type LabelsTable struct {
+	metrics []models.LabelMetrics
+}
+
+// NewLabelsTable creates a new LabelsTable formatter
+func NewLabelsTable(metrics []models.LabelMetrics) *LabelsTable {
+	return &LabelsTable{metrics: metrics}
+}
+
+// Output outputs label metrics in markdown table format
+func (t *LabelsTable) Output() {
+	tableData := t.buildTableData()
+	columns := t.createColumns()
+	t.calculateColumnWidths(columns, tableData)
+	t.outputTable(tableData, columns)
+}
+
+func (t *LabelsTable) buildTableData() [][]string {
+	tableData := make([][]string, len(t.metrics))
+	for i, m := range t.metrics {
+		tableData[i] = t.toRow(m)
+	}
+	return tableData
+}
+
+func (t *LabelsTable) toRow(m models.LabelMetrics) []string {
+	repository := m.Repository
+	if m.Incomplete {
+		repository += incompleteMarker
+	}
+	return []string{
+		repository,
+		m.Label,
+		fmt.Sprintf("%d", m.Items),
+		FormatDuration(m.TimeInLabel.Mean),
+	}
+}
+
+func (t *LabelsTable) createColumns() []MetricsTableColumn {
+	return []MetricsTableColumn{
+		{Header: "Repository", Align: "left"},
+		{Header: "Label", Align: "left"},
+		{Header: "Items", Align: "right"},
+		{Header: "Avg Time in Label", Align: "left"},
+	}
+}
+
+func (t *LabelsTable) calculateColumnWidths(columns []MetricsTableColumn, tableData [][]string) {
+	for i, col := range columns {
+		columns[i].Width = len(col.Header)
+		for _, row := range tableData {
+			if i >= len(row) || len(row[i]) <= columns[i].Width {
+				continue
+			}
+			columns[i].Width = len(row[i])
+		}
+	}
+}
+
+func (t *LabelsTable) outputTable(tableData [][]string, columns []MetricsTableColumn) {
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf(" %-*s |", col.Width, col.Header)
+	}
+	fmt.Println()
+
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf("%s|", strings.Repeat("-", col.Width+2))
+	}
+	fmt.Println()
+
+	for _, row := range tableData {
+		fmt.Print("|")
+		for i, col := range columns {
+			if col.Align == "right" {
+				fmt.Printf(" %*s |", col.Width, row[i])
+			} else {
+				fmt.Printf(" %-*s |", col.Width, row[i])
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
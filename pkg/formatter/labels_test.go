@@ -0,0 +1,49 @@
+package formatter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/formatter"
+	"yokiyoki/pkg/models"
+)
+
+func sampleLabelMetrics() []models.LabelMetrics {
+	return []models.LabelMetrics{
+		{
+			Repository:  "owner/repo",
+			Label:       "needs triage",
+			Items:       4,
+			TimeInLabel: models.DurationSummary{Count: 4, Mean: 26 * time.Hour, Median: 20 * time.Hour},
+		},
+	}
+}
+
+func TestLabelsTable_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewLabelsTable(sampleLabelMetrics()).Output()
+	})
+
+	assert.Contains(t, output, "| Repository | Label        | Items | Avg Time in Label |")
+	assert.Contains(t, output, "| owner/repo | needs triage |     4 | 1d 02h 00m        |")
+}
+
+func TestLabelsCsv_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewLabelsCsv(sampleLabelMetrics()).Output()
+	})
+
+	assert.Equal(t, "Repository,Label,Items,AvgTimeInLabelSeconds,Incomplete\n"+
+		"owner/repo,needs triage,4,93600,false\n", output)
+}
+
+func TestLabelsJson_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewLabelsJson(sampleLabelMetrics()).Output()
+	})
+
+	assert.Contains(t, output, `"label": "needs triage"`)
+	assert.Contains(t, output, `"mean": 93600`)
+	assert.NotContains(t, output, `"incomplete"`)
+}
@@ -17,69 +17,126 @@ func NewMetricsCsv(metrics []models.Metrics) *MetricsCsv {
 	return &MetricsCsv{metrics: metrics}
 }
 
-// Output outputs metrics in CSV format
-func (c *MetricsCsv) Output(byUser bool, detailedStats bool) {
+// Output outputs metrics in CSV format with times in seconds and the columns selected by opts.
+// Metrics broken down by team get a Team column, and metrics split into buckets get a row per
+// bucket with the bucket's label and dates.
+func (c *MetricsCsv) Output(opts MetricsOutputOptions) {
 	if len(c.metrics) == 0 {
 		return
 	}
 
-	headers := c.header(byUser)
+	bucketed := hasBuckets(c.metrics)
+	headers := c.header(opts, bucketed)
 	fmt.Println(strings.Join(headers, ","))
 
 	for _, m := range c.metrics {
-		fmt.Println(c.toCSV(m, byUser))
+		fmt.Println(c.toCSV(m, opts, bucketed))
 	}
 }
 
-func (c *MetricsCsv) header(includeUser bool) []string {
+func (c *MetricsCsv) header(opts MetricsOutputOptions, bucketed bool) []string {
 	headers := []string{"Repository"}
+	times := func(name string) []string {
+		if !opts.Percentiles {
+			return []string{"Avg" + name + "Seconds"}
+		}
+		return []string{"Avg" + name + "Seconds", "Median" + name + "Seconds", "P90" + name + "Seconds"}
+	}
 
-	if includeUser {
+	if opts.ByUser {
 		headers = append(headers, "User")
 	}
 
+	if hasTeams(c.metrics) {
+		headers = append(headers, "Team")
+	}
+
+	if bucketed {
+		headers = append(headers, "Bucket", "BucketStart", "BucketEnd")
+	}
+
 	headers = append(headers,
 		"Commits",
 		"LinesAdded",
 		"LinesDeleted",
 		"PRsCreated",
 		"PRsMerged",
-		"PRMergeRate",
-		"AvgPRMergeTime",
+		"PRMergeRate")
+	headers = append(headers, times("PRMergeTime")...)
+	headers = append(headers,
 		"IssuesCreated",
 		"IssuesClosed",
-		"IssueResolveRate",
-		"AvgIssueCloseTime",
-		"OpenIssues")
+		"IssueResolveRate")
+	headers = append(headers, times("IssueCloseTime")...)
+	headers = append(headers, "OpenIssues")
+
+	if opts.Reviews {
+		headers = append(headers, times("TimeToFirstReview")...)
+		headers = append(headers, times("ApprovalToMerge")...)
+		headers = append(headers, "ReviewsPerPR", "ReviewsGiven")
+	}
+
+	if opts.Timeline {
+		headers = append(headers, times("TimeToFirstResponse")...)
+		headers = append(headers, "ReopenRate")
+		headers = append(headers, times("TimeToAssignment")...)
+	}
 
-	return headers
+	return append(headers, "Incomplete")
 }
 
-func (c *MetricsCsv) toCSV(m models.Metrics, includeUser bool) string {
-	values := c.toSlice(m, includeUser)
+func (c *MetricsCsv) toCSV(m models.Metrics, opts MetricsOutputOptions, bucketed bool) string {
+	values := c.toSlice(m, opts, bucketed)
 	return strings.Join(values, ",")
 }
 
-func (c *MetricsCsv) toSlice(m models.Metrics, includeUser bool) []string {
+func (c *MetricsCsv) toSlice(m models.Metrics, opts MetricsOutputOptions, bucketed bool) []string {
 	values := []string{m.Repository}
+	times := func(s models.DurationSummary) []string {
+		if !opts.Percentiles {
+			return []string{formatSeconds(s, s.Mean)}
+		}
+		return []string{formatSeconds(s, s.Mean), formatSeconds(s, s.Median), formatSeconds(s, s.P90)}
+	}
 
-	if includeUser {
+	if opts.ByUser {
 		values = append(values, m.User)
 	}
 
+	if m.Team != "" {
+		values = append(values, m.Team)
+	}
+
+	if bucketed {
+		values = append(values, m.Bucket, m.BucketStart.Format("2006-01-02"), m.BucketEnd.Format("2006-01-02"))
+	}
+
 	values = append(values,
-		fmt.Sprintf("%d", m.Commits),
+		formatCount(m.Commits),
 		fmt.Sprintf("%d", m.LinesAdded),
 		fmt.Sprintf("%d", m.LinesDeleted),
 		fmt.Sprintf("%d", m.PRsCreated),
 		fmt.Sprintf("%d", m.PRsMerged),
-		m.PRMergeRate,
-		m.AvgPRMergeTime,
+		m.PRMergeRate)
+	values = append(values, times(m.PRMergeTime)...)
+	values = append(values,
 		fmt.Sprintf("%d", m.IssuesCreated),
 		fmt.Sprintf("%d", m.IssuesClosed),
-		m.IssueResolveRate,
-		m.AvgIssueCloseTime,
-		fmt.Sprintf("%d", m.OpenIssues))
+		m.IssueResolveRate)
+	values = append(values, times(m.IssueCloseTime)...)
+	values = append(values, fmt.Sprintf("%d", m.OpenIssues))
+
+	if opts.Reviews {
+		values = append(values, times(m.TimeToFirstReview)...)
+		values = append(values, times(m.ApprovalToMerge)...)
+		values = append(values, m.ReviewsPerPR, fmt.Sprintf("%d", m.ReviewsGiven))
+	}
+
+	if opts.Timeline {
+		values = append(values, times(m.TimeToFirstResponse)...)
+		values = append(values, m.ReopenRate)
+		values = append(values, times(m.TimeToAssignment)...)
+	}
 
-	return values
+	return append(values, fmt.Sprintf("%t", m.Incomplete))
 }
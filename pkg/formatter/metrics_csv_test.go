@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"yokiyoki/pkg/formatter"
@@ -28,40 +29,52 @@ func TestNewMetricsCsv(t *testing.T) {
 func TestMetricsCsv_Output(t *testing.T) {
 	metrics := []models.Metrics{
 		{
-			Repository:        "owner/repo",
-			User:              "testuser",
-			Commits:           10,
-			LinesAdded:        500,
-			LinesDeleted:      200,
-			PRsCreated:        5,
-			PRsMerged:         4,
-			PRMergeRate:       "80%",
-			AvgPRMergeTime:    "2d 12h 30m",
-			IssuesCreated:     3,
-			IssuesClosed:      2,
-			IssueResolveRate:  "67%",
-			AvgIssueCloseTime: "1d 05h 15m",
-			OpenIssues:        1,
+			Repository:       "owner/repo",
+			User:             "testuser",
+			Commits:          10,
+			LinesAdded:       500,
+			LinesDeleted:     200,
+			PRsCreated:       5,
+			PRsMerged:        4,
+			PRMergeRate:      "80%",
+			PRMergeTime:      models.DurationSummary{Count: 4, Mean: 60*time.Hour + 30*time.Minute},
+			IssuesCreated:    3,
+			IssuesClosed:     2,
+			IssueResolveRate: "67%",
+			IssueCloseTime:   models.DurationSummary{Count: 2, Mean: 29*time.Hour + 15*time.Minute},
+			OpenIssues:       1,
+
+			TimeToFirstReview: models.DurationSummary{Count: 2, Mean: 3 * time.Hour},
+			ApprovalToMerge:   models.DurationSummary{Count: 2, Mean: 90 * time.Minute},
+			ReviewsPerPR:      "1.5",
+			ReviewsGiven:      2,
 		},
 	}
 
 	tests := []struct {
 		name       string
 		byUser     bool
+		reviews    bool
 		wantHeader string
 		wantData   string
 	}{
 		{
 			name:       "without user",
 			byUser:     false,
-			wantHeader: "Repository,Commits,LinesAdded,LinesDeleted,PRsCreated,PRsMerged,PRMergeRate,AvgPRMergeTime,IssuesCreated,IssuesClosed,IssueResolveRate,AvgIssueCloseTime,OpenIssues",
-			wantData:   "owner/repo,10,500,200,5,4,80%,2d 12h 30m,3,2,67%,1d 05h 15m,1",
+			wantHeader: "Repository,Commits,LinesAdded,LinesDeleted,PRsCreated,PRsMerged,PRMergeRate,AvgPRMergeTimeSeconds,IssuesCreated,IssuesClosed,IssueResolveRate,AvgIssueCloseTimeSeconds,OpenIssues,Incomplete",
+			wantData:   "owner/repo,10,500,200,5,4,80%,217800,3,2,67%,105300,1,false",
 		},
 		{
 			name:       "with user",
 			byUser:     true,
-			wantHeader: "Repository,User,Commits,LinesAdded,LinesDeleted,PRsCreated,PRsMerged,PRMergeRate,AvgPRMergeTime,IssuesCreated,IssuesClosed,IssueResolveRate,AvgIssueCloseTime,OpenIssues",
-			wantData:   "owner/repo,testuser,10,500,200,5,4,80%,2d 12h 30m,3,2,67%,1d 05h 15m,1",
+			wantHeader: "Repository,User,Commits,LinesAdded,LinesDeleted,PRsCreated,PRsMerged,PRMergeRate,AvgPRMergeTimeSeconds,IssuesCreated,IssuesClosed,IssueResolveRate,AvgIssueCloseTimeSeconds,OpenIssues,Incomplete",
+			wantData:   "owner/repo,testuser,10,500,200,5,4,80%,217800,3,2,67%,105300,1,false",
+		},
+		{
+			name:       "with reviews",
+			reviews:    true,
+			wantHeader: "Repository,Commits,LinesAdded,LinesDeleted,PRsCreated,PRsMerged,PRMergeRate,AvgPRMergeTimeSeconds,IssuesCreated,IssuesClosed,IssueResolveRate,AvgIssueCloseTimeSeconds,OpenIssues,AvgTimeToFirstReviewSeconds,AvgApprovalToMergeSeconds,ReviewsPerPR,ReviewsGiven,Incomplete",
+			wantData:   "owner/repo,10,500,200,5,4,80%,217800,3,2,67%,105300,1,10800,5400,1.5,2,false",
 		},
 	}
 
@@ -72,7 +85,7 @@ func TestMetricsCsv_Output(t *testing.T) {
 			os.Stdout = w
 
 			csv := formatter.NewMetricsCsv(metrics)
-			csv.Output(tt.byUser, false)
+			csv.Output(formatter.MetricsOutputOptions{ByUser: tt.byUser, Reviews: tt.reviews})
 
 			w.Close()
 			os.Stdout = old
@@ -94,7 +107,7 @@ func TestMetricsCsv_Output_EmptyData(t *testing.T) {
 	os.Stdout = w
 
 	csv := formatter.NewMetricsCsv([]models.Metrics{})
-	csv.Output(false, false)
+	csv.Output(formatter.MetricsOutputOptions{})
 
 	w.Close()
 	os.Stdout = old
@@ -105,3 +118,58 @@ func TestMetricsCsv_Output_EmptyData(t *testing.T) {
 
 	assert.Empty(t, output)
 }
+
+func TestMetricsCsv_Output_FractionalCommits(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewMetricsCsv([]models.Metrics{{Repository: "owner/repo", User: "alice", Commits: 4.0 / 3}}).Output(formatter.MetricsOutputOptions{ByUser: true})
+	})
+
+	lines := strings.Split(output, "\n")
+	assert.True(t, strings.HasPrefix(lines[1], "owner/repo,alice,1.33,"))
+}
+
+func TestMetricsCsv_Output_Percentiles(t *testing.T) {
+	metrics := []models.Metrics{
+		{
+			Repository:  "owner/repo",
+			PRMergeTime: models.DurationSummary{Count: 3, Mean: 50 * time.Hour, Median: 2 * time.Hour, P90: 144 * time.Hour},
+		},
+	}
+
+	output := captureOutput(func() {
+		formatter.NewMetricsCsv(metrics).Output(formatter.MetricsOutputOptions{Percentiles: true})
+	})
+
+	lines := strings.Split(output, "\n")
+	assert.Contains(t, lines[0], ",AvgPRMergeTimeSeconds,MedianPRMergeTimeSeconds,P90PRMergeTimeSeconds,")
+	assert.Contains(t, lines[0], ",AvgIssueCloseTimeSeconds,MedianIssueCloseTimeSeconds,P90IssueCloseTimeSeconds,")
+	// Times without statistics are left empty
+	assert.Contains(t, lines[1], ",180000,7200,518400,0,0,,,,,0,false")
+}
+
+func TestMetricsCsv_Output_Buckets(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewMetricsCsv(bucketedMetrics()).Output(formatter.MetricsOutputOptions{ByUser: true})
+	})
+
+	lines := strings.Split(output, "\n")
+	assert.True(t, strings.HasPrefix(lines[0], "Repository,User,Bucket,BucketStart,BucketEnd,Commits,"))
+	assert.True(t, strings.HasPrefix(lines[1], "owner/repo,alice,2024-01,2024-01-15,2024-01-31,3,"))
+	assert.True(t, strings.HasPrefix(lines[2], "owner/repo,alice,2024-02,2024-02-01,2024-02-20,12,"))
+}
+
+func TestMetricsCsv_Output_Teams(t *testing.T) {
+	metrics := []models.Metrics{
+		{Repository: "owner/repo", Team: "backend", Commits: 5},
+		{Repository: "owner/repo", Team: models.OtherTeam, Commits: 1},
+	}
+
+	output := captureOutput(func() {
+		formatter.NewMetricsCsv(metrics).Output(formatter.MetricsOutputOptions{})
+	})
+
+	lines := strings.Split(output, "\n")
+	assert.True(t, strings.HasPrefix(lines[0], "Repository,Team,Commits,"))
+	assert.True(t, strings.HasPrefix(lines[1], "owner/repo,backend,5,"))
+	assert.True(t, strings.HasPrefix(lines[2], "owner/repo,other,1,"))
+}
@@ -3,6 +3,7 @@ package formatter
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 
 	"yokiyoki/pkg/models"
 )
@@ -17,49 +18,84 @@ func NewMetricsJson(metrics []models.Metrics) *MetricsJson {
 	return &MetricsJson{metrics: metrics}
 }
 
-// Output outputs metrics in JSON format
-func (j *MetricsJson) Output(byUser bool, detailedStats bool) {
+// Output outputs metrics in JSON format, summarizing each time metric in seconds. opts selects
+// the fields like the columns of MetricsTable; the summaries always hold the percentiles.
+// Metrics split into buckets get an object per bucket with the bucket's label and dates.
+func (j *MetricsJson) Output(opts MetricsOutputOptions) {
 	if len(j.metrics) == 0 {
 		return
 	}
 
 	type metricsRow struct {
-		Repository        string `json:"repository"`
-		User              string `json:"user,omitempty"`
-		Commits           int    `json:"commits"`
-		LinesAdded        int    `json:"lines_added"`
-		LinesDeleted      int    `json:"lines_deleted"`
-		PRsCreated        int    `json:"prs_created"`
-		PRsMerged         int    `json:"prs_merged"`
-		PRMergeRate       string `json:"pr_merge_rate"`
-		AvgPRMergeTime    string `json:"avg_pr_merge_time"`
-		IssuesCreated     int    `json:"issues_created"`
-		IssuesClosed      int    `json:"issues_closed"`
-		IssueResolveRate  string `json:"issue_resolve_rate"`
-		AvgIssueCloseTime string `json:"avg_issue_close_time"`
-		OpenIssues        int    `json:"open_issues"`
+		Repository       string               `json:"repository"`
+		User             string               `json:"user,omitempty"`
+		Team             string               `json:"team,omitempty"`
+		Bucket           string               `json:"bucket,omitempty"`
+		BucketStart      string               `json:"bucket_start,omitempty"`
+		BucketEnd        string               `json:"bucket_end,omitempty"`
+		Commits          float64              `json:"commits"`
+		LinesAdded       int                  `json:"lines_added"`
+		LinesDeleted     int                  `json:"lines_deleted"`
+		PRsCreated       int                  `json:"prs_created"`
+		PRsMerged        int                  `json:"prs_merged"`
+		PRMergeRate      string               `json:"pr_merge_rate"`
+		PRMergeTime      *durationSummaryJson `json:"pr_merge_time"`
+		IssuesCreated    int                  `json:"issues_created"`
+		IssuesClosed     int                  `json:"issues_closed"`
+		IssueResolveRate string               `json:"issue_resolve_rate"`
+		IssueCloseTime   *durationSummaryJson `json:"issue_close_time"`
+		OpenIssues       int                  `json:"open_issues"`
+
+		TimeToFirstReview *durationSummaryJson `json:"time_to_first_review,omitempty"`
+		ApprovalToMerge   *durationSummaryJson `json:"approval_to_merge,omitempty"`
+		ReviewsPerPR      string               `json:"reviews_per_pr,omitempty"`
+		ReviewsGiven      *int                 `json:"reviews_given,omitempty"`
+
+		TimeToFirstResponse *durationSummaryJson `json:"time_to_first_response,omitempty"`
+		ReopenRate          string               `json:"reopen_rate,omitempty"`
+		TimeToAssignment    *durationSummaryJson `json:"time_to_assignment,omitempty"`
+
+		Incomplete bool `json:"incomplete,omitempty"`
 	}
 
 	rows := make([]metricsRow, 0, len(j.metrics))
 	for _, m := range j.metrics {
 		row := metricsRow{
-			Repository:        m.Repository,
-			Commits:           m.Commits,
-			LinesAdded:        m.LinesAdded,
-			LinesDeleted:      m.LinesDeleted,
-			PRsCreated:        m.PRsCreated,
-			PRsMerged:         m.PRsMerged,
-			PRMergeRate:       m.PRMergeRate,
-			AvgPRMergeTime:    m.AvgPRMergeTime,
-			IssuesCreated:     m.IssuesCreated,
-			IssuesClosed:      m.IssuesClosed,
-			IssueResolveRate:  m.IssueResolveRate,
-			AvgIssueCloseTime: m.AvgIssueCloseTime,
-			OpenIssues:        m.OpenIssues,
+			Repository:       m.Repository,
+			Team:             m.Team,
+			Commits:          math.Round(m.Commits*100) / 100,
+			LinesAdded:       m.LinesAdded,
+			LinesDeleted:     m.LinesDeleted,
+			PRsCreated:       m.PRsCreated,
+			PRsMerged:        m.PRsMerged,
+			PRMergeRate:      m.PRMergeRate,
+			PRMergeTime:      toDurationSummaryJson(m.PRMergeTime),
+			IssuesCreated:    m.IssuesCreated,
+			IssuesClosed:     m.IssuesClosed,
+			IssueResolveRate: m.IssueResolveRate,
+			IssueCloseTime:   toDurationSummaryJson(m.IssueCloseTime),
+			OpenIssues:       m.OpenIssues,
+			Incomplete:       m.Incomplete,
 		}
-		if byUser {
+		if opts.ByUser {
 			row.User = m.User
 		}
+		if m.Bucket != "" {
+			row.Bucket = m.Bucket
+			row.BucketStart = m.BucketStart.Format("2006-01-02")
+			row.BucketEnd = m.BucketEnd.Format("2006-01-02")
+		}
+		if opts.Reviews {
+			row.TimeToFirstReview = toDurationSummaryJson(m.TimeToFirstReview)
+			row.ApprovalToMerge = toDurationSummaryJson(m.ApprovalToMerge)
+			row.ReviewsPerPR = m.ReviewsPerPR
+			row.ReviewsGiven = &m.ReviewsGiven
+		}
+		if opts.Timeline {
+			row.TimeToFirstResponse = toDurationSummaryJson(m.TimeToFirstResponse)
+			row.ReopenRate = m.ReopenRate
+			row.TimeToAssignment = toDurationSummaryJson(m.TimeToAssignment)
+		}
 		rows = append(rows, row)
 	}
 
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"yokiyoki/pkg/formatter"
@@ -28,20 +29,20 @@ func TestNewMetricsJson(t *testing.T) {
 func TestMetricsJson_Output(t *testing.T) {
 	metrics := []models.Metrics{
 		{
-			Repository:        "owner/repo",
-			User:              "testuser",
-			Commits:           10,
-			LinesAdded:        500,
-			LinesDeleted:      200,
-			PRsCreated:        5,
-			PRsMerged:         4,
-			PRMergeRate:       "80%",
-			AvgPRMergeTime:    "2d 12h 30m",
-			IssuesCreated:     3,
-			IssuesClosed:      2,
-			IssueResolveRate:  "67%",
-			AvgIssueCloseTime: "1d 05h 15m",
-			OpenIssues:        1,
+			Repository:       "owner/repo",
+			User:             "testuser",
+			Commits:          10,
+			LinesAdded:       500,
+			LinesDeleted:     200,
+			PRsCreated:       5,
+			PRsMerged:        4,
+			PRMergeRate:      "80%",
+			PRMergeTime:      models.DurationSummary{Count: 4, Mean: 60*time.Hour + 30*time.Minute},
+			IssuesCreated:    3,
+			IssuesClosed:     2,
+			IssueResolveRate: "67%",
+			IssueCloseTime:   models.DurationSummary{Count: 2, Mean: 29*time.Hour + 15*time.Minute},
+			OpenIssues:       1,
 		},
 	}
 
@@ -60,7 +61,7 @@ func TestMetricsJson_Output(t *testing.T) {
 			os.Stdout = w
 
 			j := formatter.NewMetricsJson(metrics)
-			j.Output(tt.byUser, false)
+			j.Output(formatter.MetricsOutputOptions{ByUser: tt.byUser})
 
 			w.Close()
 			os.Stdout = old
@@ -92,7 +93,7 @@ func TestMetricsJson_Output_EmptyData(t *testing.T) {
 	os.Stdout = w
 
 	j := formatter.NewMetricsJson([]models.Metrics{})
-	j.Output(false, false)
+	j.Output(formatter.MetricsOutputOptions{})
 
 	w.Close()
 	os.Stdout = old
@@ -103,3 +104,50 @@ func TestMetricsJson_Output_EmptyData(t *testing.T) {
 
 	assert.Empty(t, output)
 }
+
+func TestMetricsJson_Output_DurationSummaries(t *testing.T) {
+	metrics := []models.Metrics{
+		{
+			Repository: "owner/repo",
+			PRMergeTime: models.DurationSummary{
+				Count:  3,
+				Mean:   50 * time.Hour,
+				Min:    time.Hour,
+				Median: 2 * time.Hour,
+				P75:    144 * time.Hour,
+				P90:    144 * time.Hour,
+				Max:    144 * time.Hour,
+			},
+		},
+	}
+
+	output := captureOutput(func() {
+		formatter.NewMetricsJson(metrics).Output(formatter.MetricsOutputOptions{})
+	})
+
+	var result []map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, map[string]any{
+		"count":  float64(3),
+		"mean":   float64(180000),
+		"min":    float64(3600),
+		"median": float64(7200),
+		"p75":    float64(518400),
+		"p90":    float64(518400),
+		"max":    float64(518400),
+	}, result[0]["pr_merge_time"])
+	assert.Nil(t, result[0]["issue_close_time"])
+}
+
+func TestMetricsJson_Output_Buckets(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewMetricsJson(bucketedMetrics()).Output(formatter.MetricsOutputOptions{ByUser: true})
+	})
+
+	var result []map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Len(t, result, 2)
+	assert.Equal(t, "2024-02", result[1]["bucket"])
+	assert.Equal(t, "2024-02-01", result[1]["bucket_start"])
+	assert.Equal(t, "2024-02-20", result[1]["bucket_end"])
+}
@@ -2,16 +2,36 @@ package formatter
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	"yokiyoki/pkg/models"
 )
 
+// incompleteMarker is appended to the repository of rows whose data could not be fully fetched
+const incompleteMarker = " (!)"
+
 // MetricsTable handles markdown table formatting of metrics
 type MetricsTable struct {
 	metrics []models.Metrics
 }
 
+// MetricsOutputOptions selects the columns printed by the metrics formatters
+type MetricsOutputOptions struct {
+	// ByUser adds the User column
+	ByUser bool
+	// DetailedStats adds the lines added and deleted (always present in CSV and JSON)
+	DetailedStats bool
+	// Reviews adds the review metrics
+	Reviews bool
+	// Timeline adds the timeline metrics
+	Timeline bool
+	// Percentiles follows each average time with its median and p90 (JSON always has them)
+	Percentiles bool
+}
+
 // MetricsTableColumn represents a table column configuration
 type MetricsTableColumn struct {
 	Header string
@@ -19,79 +39,134 @@ type MetricsTableColumn struct {
 	Align  string // "left", "right"
 }
 
+// hasTeams reports whether the metrics are broken down by team
+func hasTeams(metrics []models.Metrics) bool {
+	return len(metrics) > 0 && metrics[0].Team != ""
+}
+
 // NewMetricsTable creates a new MetricsTable formatter
 func NewMetricsTable(metrics []models.Metrics) *MetricsTable {
 	return &MetricsTable{metrics: metrics}
 }
 
-// Output outputs metrics in markdown table format
-func (t *MetricsTable) Output(byUser bool, detailedStats bool) {
-	tableData := t.buildTableData(byUser, detailedStats)
-	columns := t.createColumns(byUser, detailedStats)
+// Output outputs metrics in markdown table format with the columns selected by opts
+func (t *MetricsTable) Output(opts MetricsOutputOptions) {
+	tableData := t.buildTableData(opts)
+	columns := t.createColumns(opts)
 	t.calculateColumnWidths(columns, tableData)
 	t.outputTable(tableData, columns)
 }
 
-func (t *MetricsTable) buildTableData(byUser bool, detailedStats bool) [][]string {
+func (t *MetricsTable) buildTableData(opts MetricsOutputOptions) [][]string {
 	tableData := make([][]string, len(t.metrics))
 	for i, m := range t.metrics {
-		row := t.toMarkdownRow(m, byUser, detailedStats)
+		row := t.toMarkdownRow(m, opts)
 		tableData[i] = row
 	}
 	return tableData
 }
 
-func (t *MetricsTable) toMarkdownRow(m models.Metrics, byUser bool, detailedStats bool) []string {
+func (t *MetricsTable) toMarkdownRow(m models.Metrics, opts MetricsOutputOptions) []string {
 	linesStr := t.formatLines(m)
 	prsStr := t.formatPRs(m)
 	issuesStr := t.formatIssues(m)
-	avgPRMergeTime := t.formatTime(m.AvgPRMergeTime)
-	avgIssueCloseTime := t.formatTime(m.AvgIssueCloseTime)
+	times := func(s models.DurationSummary) []string {
+		if !opts.Percentiles {
+			return []string{t.formatDuration(s, s.Mean)}
+		}
+		return []string{t.formatDuration(s, s.Mean), t.formatDuration(s, s.Median), t.formatDuration(s, s.P90)}
+	}
 
-	row := []string{m.Repository}
+	repository := m.Repository
+	if m.Incomplete {
+		repository += incompleteMarker
+	}
+	row := []string{repository}
 
-	if byUser {
+	if opts.ByUser {
 		row = append(row, m.User)
 	}
 
-	row = append(row,
-		fmt.Sprintf("%d", m.Commits),
-		prsStr,
-		avgPRMergeTime,
-		issuesStr,
-		avgIssueCloseTime,
-		fmt.Sprintf("%d", m.OpenIssues),
-	)
+	if m.Team != "" {
+		row = append(row, m.Team)
+	}
+
+	row = append(row, formatCount(m.Commits), prsStr)
+	row = append(row, times(m.PRMergeTime)...)
+	row = append(row, issuesStr)
+	row = append(row, times(m.IssueCloseTime)...)
+	row = append(row, fmt.Sprintf("%d", m.OpenIssues))
 
-	if detailedStats {
+	if opts.DetailedStats {
 		row = append(row, linesStr)
 	}
 
+	if opts.Reviews {
+		row = append(row, times(m.TimeToFirstReview)...)
+		row = append(row, times(m.ApprovalToMerge)...)
+		row = append(row, t.formatTime(m.ReviewsPerPR), fmt.Sprintf("%d", m.ReviewsGiven))
+	}
+
+	if opts.Timeline {
+		row = append(row, times(m.TimeToFirstResponse)...)
+		row = append(row, t.formatTime(m.ReopenRate))
+		row = append(row, times(m.TimeToAssignment)...)
+	}
+
 	return row
 }
 
-func (t *MetricsTable) createColumns(byUser bool, detailedStats bool) []MetricsTableColumn {
+func (t *MetricsTable) createColumns(opts MetricsOutputOptions) []MetricsTableColumn {
 	columns := []MetricsTableColumn{
 		{Header: "Repository", Align: "left"},
 	}
+	times := func(header string) []MetricsTableColumn {
+		if !opts.Percentiles {
+			return []MetricsTableColumn{{Header: header, Align: "left"}}
+		}
+		return []MetricsTableColumn{
+			{Header: header, Align: "left"},
+			{Header: header + " p50", Align: "left"},
+			{Header: header + " p90", Align: "left"},
+		}
+	}
 
-	if byUser {
+	if opts.ByUser {
 		columns = append(columns, MetricsTableColumn{Header: "User", Align: "left"})
 	}
 
+	if hasTeams(t.metrics) {
+		columns = append(columns, MetricsTableColumn{Header: "Team", Align: "left"})
+	}
+
 	columns = append(columns,
 		MetricsTableColumn{Header: "Commits", Align: "right"},
 		MetricsTableColumn{Header: "PR Merge Rate", Align: "left"},
-		MetricsTableColumn{Header: "PR Merge Time", Align: "left"},
-		MetricsTableColumn{Header: "Issue Resolve Rate", Align: "left"},
-		MetricsTableColumn{Header: "Issue Resolve Time", Align: "left"},
-		MetricsTableColumn{Header: "Active Issues", Align: "right"},
 	)
+	columns = append(columns, times("PR Merge Time")...)
+	columns = append(columns, MetricsTableColumn{Header: "Issue Resolve Rate", Align: "left"})
+	columns = append(columns, times("Issue Resolve Time")...)
+	columns = append(columns, MetricsTableColumn{Header: "Active Issues", Align: "right"})
 
-	if detailedStats {
+	if opts.DetailedStats {
 		columns = append(columns, MetricsTableColumn{Header: "Lines +/-", Align: "left"})
 	}
 
+	if opts.Reviews {
+		columns = append(columns, times("First Review")...)
+		columns = append(columns, times("Approval to Merge")...)
+		columns = append(columns,
+			MetricsTableColumn{Header: "Reviews/PR", Align: "right"},
+			MetricsTableColumn{Header: "Reviews Given", Align: "right"},
+		)
+	}
+
+	if opts.Timeline {
+		columns = append(columns, times("First Response")...)
+		columns = append(columns, MetricsTableColumn{Header: "Reopen Rate", Align: "left"})
+		columns = append(columns, times("Time to Assign")...)
+	}
+
 	return columns
 }
 
@@ -112,6 +187,17 @@ func (t *MetricsTable) outputTable(tableData [][]string, columns []MetricsTableC
 	t.printSeparator(columns)
 	t.printRows(tableData, columns)
 	fmt.Println()
+	t.printIncompleteNote()
+}
+
+// printIncompleteNote explains the marker on rows whose data could not be fully fetched
+func (t *MetricsTable) printIncompleteNote() {
+	for _, m := range t.metrics {
+		if m.Incomplete {
+			fmt.Printf("%s data incomplete: some requests failed, so these rows may undercount\n\n", strings.TrimSpace(incompleteMarker))
+			return
+		}
+	}
 }
 
 func (t *MetricsTable) printHeader(columns []MetricsTableColumn) {
@@ -194,3 +280,16 @@ func (t *MetricsTable) formatTime(timeStr string) string {
 	}
 	return timeStr
 }
+
+// formatDuration formats one statistic of the summary, or "-" when there were no durations
+func (t *MetricsTable) formatDuration(s models.DurationSummary, d time.Duration) string {
+	if s.Count == 0 {
+		return "-"
+	}
+	return FormatDuration(d)
+}
+
+// formatCount formats a count that is fractional when credit is split, with up to two decimals
+func formatCount(count float64) string {
+	return strconv.FormatFloat(math.Round(count*100)/100, 'f', -1, 64)
+}
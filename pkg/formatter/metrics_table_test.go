@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"yokiyoki/pkg/formatter"
@@ -28,20 +29,20 @@ func TestNewMetricsTable(t *testing.T) {
 func TestMetricsTable_Output(t *testing.T) {
 	metrics := []models.Metrics{
 		{
-			Repository:        "owner/repo",
-			User:              "testuser",
-			Commits:           10,
-			LinesAdded:        500,
-			LinesDeleted:      200,
-			PRsCreated:        5,
-			PRsMerged:         4,
-			PRMergeRate:       "80%",
-			AvgPRMergeTime:    "2d 12h 30m",
-			IssuesCreated:     3,
-			IssuesClosed:      2,
-			IssueResolveRate:  "67%",
-			AvgIssueCloseTime: "1d 05h 15m",
-			OpenIssues:        1,
+			Repository:       "owner/repo",
+			User:             "testuser",
+			Commits:          10,
+			LinesAdded:       500,
+			LinesDeleted:     200,
+			PRsCreated:       5,
+			PRsMerged:        4,
+			PRMergeRate:      "80%",
+			PRMergeTime:      models.DurationSummary{Count: 4, Mean: 60*time.Hour + 30*time.Minute},
+			IssuesCreated:    3,
+			IssuesClosed:     2,
+			IssueResolveRate: "67%",
+			IssueCloseTime:   models.DurationSummary{Count: 2, Mean: 29*time.Hour + 15*time.Minute},
+			OpenIssues:       1,
 		},
 	}
 
@@ -99,7 +100,7 @@ func TestMetricsTable_Output(t *testing.T) {
 			os.Stdout = w
 
 			table := formatter.NewMetricsTable(metrics)
-			table.Output(tt.byUser, tt.detailedStats)
+			table.Output(formatter.MetricsOutputOptions{ByUser: tt.byUser, DetailedStats: tt.detailedStats})
 
 			w.Close()
 			os.Stdout = old
@@ -123,3 +124,63 @@ func TestMetricsTable_Output(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsTable_Output_Incomplete(t *testing.T) {
+	metrics := []models.Metrics{
+		{Repository: "owner/ok", PRMergeRate: "None", IssueResolveRate: "None"},
+		{Repository: "owner/partial", PRMergeRate: "None", IssueResolveRate: "None", Incomplete: true},
+	}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	formatter.NewMetricsTable(metrics).Output(formatter.MetricsOutputOptions{})
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	assert.Contains(t, output, "owner/partial (!)")
+	assert.NotContains(t, output, "owner/ok (!)")
+	assert.Contains(t, output, "(!) data incomplete")
+}
+
+func TestMetricsTable_Output_Percentiles(t *testing.T) {
+	metrics := []models.Metrics{
+		{
+			Repository:  "owner/repo",
+			PRMergeRate: "None",
+			PRMergeTime: models.DurationSummary{Count: 3, Mean: 50 * time.Hour, Median: 2 * time.Hour, P90: 144 * time.Hour},
+		},
+	}
+
+	output := captureOutput(func() {
+		formatter.NewMetricsTable(metrics).Output(formatter.MetricsOutputOptions{Percentiles: true})
+	})
+
+	lines := strings.Split(output, "\n")
+	assert.Contains(t, lines[0], "| PR Merge Time | PR Merge Time p50 | PR Merge Time p90 |")
+	assert.Contains(t, lines[2], "| 2d 02h 00m    | 0d 02h 00m        | 6d 00h 00m        |")
+	// No issues were closed, so the issue times have no statistics
+	assert.Contains(t, lines[2], "| -                  | -                      | -                      |")
+}
+
+func TestMetricsTable_Output_Teams(t *testing.T) {
+	metrics := []models.Metrics{
+		{Repository: "owner/repo", Team: "backend", Commits: 5, PRMergeRate: "None", IssueResolveRate: "None"},
+		{Repository: "owner/repo", Team: models.OtherTeam, Commits: 1, PRMergeRate: "None", IssueResolveRate: "None"},
+	}
+
+	output := captureOutput(func() {
+		formatter.NewMetricsTable(metrics).Output(formatter.MetricsOutputOptions{})
+	})
+
+	lines := strings.Split(output, "\n")
+	assert.True(t, strings.HasPrefix(lines[0], "| Repository | Team    | Commits |"))
+	assert.True(t, strings.HasPrefix(lines[2], "| owner/repo | backend |       5 |"))
+	assert.True(t, strings.HasPrefix(lines[3], "| owner/repo | other   |       1 |"))
+}
@@ -0,0 +1,247 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// MetricsTrendTable handles markdown formatting of metrics split into buckets, as one grid per
+// metric with a row per repository (and user) and a column per bucket
+type MetricsTrendTable struct {
+	metrics []models.Metrics
+}
+
+// trendMetric is a metric printed as one grid of the trend table
+type trendMetric struct {
+	title string
+	align string
+	value func(m models.Metrics) string
+}
+
+// trendRow is a repository, or a user or team of it, with its metrics by bucket
+type trendRow struct {
+	repository string
+	user       string
+	team       string
+	incomplete bool
+	buckets    map[string]models.Metrics
+}
+
+// NewMetricsTrendTable creates a new MetricsTrendTable formatter
+func NewMetricsTrendTable(metrics []models.Metrics) *MetricsTrendTable {
+	return &MetricsTrendTable{metrics: metrics}
+}
+
+// hasBuckets reports whether the metrics are split into buckets
+func hasBuckets(metrics []models.Metrics) bool {
+	return len(metrics) > 0 && metrics[0].Bucket != ""
+}
+
+// Output outputs the metrics as time-series grids. opts selects the metrics like the columns of MetricsTable.
+func (t *MetricsTrendTable) Output(opts MetricsOutputOptions) {
+	buckets := t.buckets()
+	rows := t.rows(opts.ByUser)
+
+	for _, metric := range t.trendMetrics(opts) {
+		fmt.Println(metric.title)
+		fmt.Println()
+		columns := t.createColumns(opts.ByUser, buckets, metric)
+		tableData := t.buildTableData(rows, opts.ByUser, buckets, metric)
+		t.calculateColumnWidths(columns, tableData)
+		t.outputTable(tableData, columns)
+	}
+
+	for _, row := range rows {
+		if row.incomplete {
+			fmt.Printf("%s data incomplete: some requests failed, so these rows may undercount\n\n", strings.TrimSpace(incompleteMarker))
+			return
+		}
+	}
+}
+
+// buckets returns the bucket labels in order; they sort chronologically
+func (t *MetricsTrendTable) buckets() []string {
+	seen := make(map[string]bool)
+	var buckets []string
+	for _, m := range t.metrics {
+		if !seen[m.Bucket] {
+			seen[m.Bucket] = true
+			buckets = append(buckets, m.Bucket)
+		}
+	}
+	sort.Strings(buckets)
+	return buckets
+}
+
+// rows groups the metrics by repository, and by user with byUser or by team, in order of appearance
+func (t *MetricsTrendTable) rows(byUser bool) []*trendRow {
+	var rows []*trendRow
+	index := make(map[string]*trendRow)
+	for _, m := range t.metrics {
+		key := m.Repository
+		if byUser {
+			key += "\x00" + m.User
+		}
+		key += "\x00" + m.Team
+		row, ok := index[key]
+		if !ok {
+			row = &trendRow{repository: m.Repository, user: m.User, team: m.Team, buckets: make(map[string]models.Metrics)}
+			index[key] = row
+			rows = append(rows, row)
+		}
+		row.buckets[m.Bucket] = m
+		row.incomplete = row.incomplete || m.Incomplete
+	}
+	return rows
+}
+
+func (t *MetricsTrendTable) trendMetrics(opts MetricsOutputOptions) []trendMetric {
+	count := func(title string, value func(m models.Metrics) int) trendMetric {
+		return trendMetric{title: title, align: "right", value: func(m models.Metrics) string { return fmt.Sprintf("%d", value(m)) }}
+	}
+	rate := func(title string, value func(m models.Metrics) string) trendMetric {
+		return trendMetric{title: title, align: "right", value: func(m models.Metrics) string { return t.formatRate(value(m)) }}
+	}
+	times := func(title string, summary func(m models.Metrics) models.DurationSummary) []trendMetric {
+		stat := func(title string, pick func(s models.DurationSummary) time.Duration) trendMetric {
+			return trendMetric{title: title, align: "left", value: func(m models.Metrics) string {
+				s := summary(m)
+				if s.Count == 0 {
+					return "-"
+				}
+				return FormatDuration(pick(s))
+			}}
+		}
+		metrics := []trendMetric{stat(title, func(s models.DurationSummary) time.Duration { return s.Mean })}
+		if opts.Percentiles {
+			metrics = append(metrics,
+				stat(title+" p50", func(s models.DurationSummary) time.Duration { return s.Median }),
+				stat(title+" p90", func(s models.DurationSummary) time.Duration { return s.P90 }),
+			)
+		}
+		return metrics
+	}
+
+	metrics := []trendMetric{
+		{title: "Commits", align: "right", value: func(m models.Metrics) string { return formatCount(m.Commits) }},
+		count("PRs Created", func(m models.Metrics) int { return m.PRsCreated }),
+		count("PRs Merged", func(m models.Metrics) int { return m.PRsMerged }),
+	}
+	metrics = append(metrics, times("PR Merge Time", func(m models.Metrics) models.DurationSummary { return m.PRMergeTime })...)
+	metrics = append(metrics,
+		count("Issues Created", func(m models.Metrics) int { return m.IssuesCreated }),
+		count("Issues Closed", func(m models.Metrics) int { return m.IssuesClosed }),
+	)
+	metrics = append(metrics, times("Issue Resolve Time", func(m models.Metrics) models.DurationSummary { return m.IssueCloseTime })...)
+
+	if opts.DetailedStats {
+		metrics = append(metrics, trendMetric{title: "Lines +/-", align: "right", value: func(m models.Metrics) string {
+			return fmt.Sprintf("+%d/-%d", m.LinesAdded, m.LinesDeleted)
+		}})
+	}
+
+	if opts.Reviews {
+		metrics = append(metrics, times("First Review", func(m models.Metrics) models.DurationSummary { return m.TimeToFirstReview })...)
+		metrics = append(metrics, times("Approval to Merge", func(m models.Metrics) models.DurationSummary { return m.ApprovalToMerge })...)
+		metrics = append(metrics, count("Reviews Given", func(m models.Metrics) int { return m.ReviewsGiven }))
+	}
+
+	if opts.Timeline {
+		metrics = append(metrics, times("First Response", func(m models.Metrics) models.DurationSummary { return m.TimeToFirstResponse })...)
+		metrics = append(metrics, rate("Reopen Rate", func(m models.Metrics) string { return m.ReopenRate }))
+		metrics = append(metrics, times("Time to Assign", func(m models.Metrics) models.DurationSummary { return m.TimeToAssignment })...)
+	}
+
+	return metrics
+}
+
+func (t *MetricsTrendTable) createColumns(byUser bool, buckets []string, metric trendMetric) []MetricsTableColumn {
+	columns := []MetricsTableColumn{{Header: "Repository", Align: "left"}}
+	if byUser {
+		columns = append(columns, MetricsTableColumn{Header: "User", Align: "left"})
+	}
+	if hasTeams(t.metrics) {
+		columns = append(columns, MetricsTableColumn{Header: "Team", Align: "left"})
+	}
+	for _, bucket := range buckets {
+		columns = append(columns, MetricsTableColumn{Header: bucket, Align: metric.align})
+	}
+	return columns
+}
+
+func (t *MetricsTrendTable) buildTableData(rows []*trendRow, byUser bool, buckets []string, metric trendMetric) [][]string {
+	tableData := make([][]string, len(rows))
+	for i, row := range rows {
+		repository := row.repository
+		if row.incomplete {
+			repository += incompleteMarker
+		}
+		cells := []string{repository}
+		if byUser {
+			cells = append(cells, row.user)
+		}
+		if row.team != "" {
+			cells = append(cells, row.team)
+		}
+		for _, bucket := range buckets {
+			m, ok := row.buckets[bucket]
+			if !ok {
+				cells = append(cells, "-")
+				continue
+			}
+			cells = append(cells, metric.value(m))
+		}
+		tableData[i] = cells
+	}
+	return tableData
+}
+
+func (t *MetricsTrendTable) calculateColumnWidths(columns []MetricsTableColumn, tableData [][]string) {
+	for i, col := range columns {
+		columns[i].Width = len(col.Header)
+		for _, row := range tableData {
+			if i >= len(row) || len(row[i]) <= columns[i].Width {
+				continue
+			}
+			columns[i].Width = len(row[i])
+		}
+	}
+}
+
+func (t *MetricsTrendTable) outputTable(tableData [][]string, columns []MetricsTableColumn) {
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf(" %-*s |", col.Width, col.Header)
+	}
+	fmt.Println()
+
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf("%s|", strings.Repeat("-", col.Width+2))
+	}
+	fmt.Println()
+
+	for _, row := range tableData {
+		fmt.Print("|")
+		for i, col := range columns {
+			if col.Align == "right" {
+				fmt.Printf(" %*s |", col.Width, row[i])
+			} else {
+				fmt.Printf(" %-*s |", col.Width, row[i])
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func (t *MetricsTrendTable) formatRate(value string) string {
+	if value == "None" {
+		return "-"
+	}
+	return value
+}
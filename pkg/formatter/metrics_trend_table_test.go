@@ -0,0 +1,62 @@
+package formatter_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/formatter"
+	"yokiyoki/pkg/models"
+)
+
+func bucketedMetrics() []models.Metrics {
+	jst := time.FixedZone("JST", 9*60*60)
+	return []models.Metrics{
+		{
+			Repository:  "owner/repo",
+			User:        "alice",
+			Bucket:      "2024-01",
+			BucketStart: time.Date(2024, 1, 15, 0, 0, 0, 0, jst),
+			BucketEnd:   time.Date(2024, 1, 31, 23, 59, 59, 0, jst),
+			Commits:     3,
+			PRMergeTime: models.DurationSummary{Count: 1, Mean: 26 * time.Hour},
+		},
+		{
+			Repository:  "owner/repo",
+			User:        "alice",
+			Bucket:      "2024-02",
+			BucketStart: time.Date(2024, 2, 1, 0, 0, 0, 0, jst),
+			BucketEnd:   time.Date(2024, 2, 20, 23, 59, 59, 0, jst),
+			Commits:     12,
+		},
+	}
+}
+
+func TestMetricsTrendTable_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewMetricsTrendTable(bucketedMetrics()).Output(formatter.MetricsOutputOptions{ByUser: true})
+	})
+
+	assert.Contains(t, output, "Commits\n\n"+
+		"| Repository | User  | 2024-01 | 2024-02 |\n"+
+		"|------------|-------|---------|---------|\n"+
+		"| owner/repo | alice |       3 |      12 |\n")
+	assert.Contains(t, output, "PR Merge Time\n\n"+
+		"| Repository | User  | 2024-01    | 2024-02 |\n"+
+		"|------------|-------|------------|---------|\n"+
+		"| owner/repo | alice | 1d 02h 00m | -       |\n")
+	assert.NotContains(t, output, "First Review")
+	assert.NotContains(t, output, "p90")
+}
+
+func TestMetricsTrendTable_Output_Percentiles(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewMetricsTrendTable(bucketedMetrics()).Output(formatter.MetricsOutputOptions{Reviews: true, Percentiles: true})
+	})
+
+	for _, title := range []string{"PR Merge Time p50", "PR Merge Time p90", "First Review p90", "Reviews Given"} {
+		assert.True(t, strings.Contains(output, "\n"+title+"\n"), title)
+	}
+	assert.NotContains(t, output, "| User")
+}
@@ -0,0 +1,43 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"yokiyoki/pkg/models"
+)
+
+// ReleasesCsv handles CSV formatting of release metrics
+type ReleasesCsv struct {
+	metrics []models.ReleaseMetrics
+}
+
+// NewReleasesCsv creates a new ReleasesCsv formatter
+func NewReleasesCsv(metrics []models.ReleaseMetrics) *ReleasesCsv {
+	return &ReleasesCsv{metrics: metrics}
+}
+
+// Output outputs release metrics in CSV format
+func (c *ReleasesCsv) Output() {
+	if len(c.metrics) == 0 {
+		return
+	}
+
+	headers := []string{"Repository", "Releases", "LatestRelease", "MedianIntervalSeconds",
+		"CommitsPerRelease", "PRsPerRelease", "AvgMergeToReleaseSeconds", "Incomplete"}
+	fmt.Println(strings.Join(headers, ","))
+
+	for _, m := range c.metrics {
+		values := []string{
+			m.Repository,
+			fmt.Sprintf("%d", m.Releases),
+			escapeCsvField(m.LatestRelease),
+			formatSeconds(m.Interval, m.Interval.Median),
+			m.CommitsPerRelease,
+			m.PRsPerRelease,
+			formatSeconds(m.MergeToRelease, m.MergeToRelease.Mean),
+			fmt.Sprintf("%t", m.Incomplete),
+		}
+		fmt.Println(strings.Join(values, ","))
+	}
+}
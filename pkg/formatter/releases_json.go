@@ -0,0 +1,57 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"yokiyoki/pkg/models"
+)
+
+// ReleasesJson handles JSON formatting of release metrics
+type ReleasesJson struct {
+	metrics []models.ReleaseMetrics
+}
+
+// NewReleasesJson creates a new ReleasesJson formatter
+func NewReleasesJson(metrics []models.ReleaseMetrics) *ReleasesJson {
+	return &ReleasesJson{metrics: metrics}
+}
+
+// Output outputs release metrics in JSON format
+func (j *ReleasesJson) Output() {
+	if len(j.metrics) == 0 {
+		return
+	}
+
+	type releasesRow struct {
+		Repository        string               `json:"repository"`
+		Releases          int                  `json:"releases"`
+		LatestRelease     string               `json:"latest_release,omitempty"`
+		Interval          *durationSummaryJson `json:"interval"`
+		CommitsPerRelease string               `json:"commits_per_release"`
+		PRsPerRelease     string               `json:"prs_per_release"`
+		MergeToRelease    *durationSummaryJson `json:"merge_to_release"`
+		Incomplete        bool                 `json:"incomplete,omitempty"`
+	}
+
+	rows := make([]releasesRow, 0, len(j.metrics))
+	for _, m := range j.metrics {
+		rows = append(rows, releasesRow{
+			Repository:        m.Repository,
+			Releases:          m.Releases,
+			LatestRelease:     m.LatestRelease,
+			Interval:          toDurationSummaryJson(m.Interval),
+			CommitsPerRelease: m.CommitsPerRelease,
+			PRsPerRelease:     m.PRsPerRelease,
+			MergeToRelease:    toDurationSummaryJson(m.MergeToRelease),
+			Incomplete:        m.Incomplete,
+		})
+	}
+
+	out, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		fmt.Printf("Error encoding JSON: %v\n", err)
+		return
+	}
+	fmt.Println(string(out))
+}
@@ -0,0 +1,124 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// ReleasesTable handles markdown table formatting of release metrics
+type ReleasesTable struct {
+	metrics []models.ReleaseMetrics
+}
+
+// NewReleasesTable creates a new ReleasesTable formatter
+func NewReleasesTable(metrics []models.ReleaseMetrics) *ReleasesTable {
+	return &ReleasesTable{metrics: metrics}
+}
+
+// Output outputs release metrics in markdown table format
+func (t *ReleasesTable) Output() {
+	tableData := t.buildTableData()
+	columns := t.createColumns()
+	t.calculateColumnWidths(columns, tableData)
+	t.outputTable(tableData, columns)
+}
+
+func (t *ReleasesTable) buildTableData() [][]string {
+	tableData := make([][]string, len(t.metrics))
+	for i, m := range t.metrics {
+		tableData[i] = t.toRow(m)
+	}
+	return tableData
+}
+
+func (t *ReleasesTable) toRow(m models.ReleaseMetrics) []string {
+	repository := m.Repository
+	if m.Incomplete {
+		repository += incompleteMarker
+	}
+	return []string{
+		repository,
+		fmt.Sprintf("%d", m.Releases),
+		t.formatLatest(m.LatestRelease),
+		t.formatDuration(m.Interval, m.Interval.Median),
+		t.formatValue(m.CommitsPerRelease),
+		t.formatValue(m.PRsPerRelease),
+		t.formatDuration(m.MergeToRelease, m.MergeToRelease.Mean),
+	}
+}
+
+func (t *ReleasesTable) createColumns() []MetricsTableColumn {
+	return []MetricsTableColumn{
+		{Header: "Repository", Align: "left"},
+		{Header: "Releases", Align: "right"},
+		{Header: "Latest", Align: "left"},
+		{Header: "Median Interval", Align: "left"},
+		{Header: "Commits/Release", Align: "right"},
+		{Header: "PRs/Release", Align: "right"},
+		{Header: "Merge to Release", Align: "left"},
+	}
+}
+
+func (t *ReleasesTable) calculateColumnWidths(columns []MetricsTableColumn, tableData [][]string) {
+	for i, col := range columns {
+		columns[i].Width = len(col.Header)
+		for _, row := range tableData {
+			if i >= len(row) || len(row[i]) <= columns[i].Width {
+				continue
+			}
+			columns[i].Width = len(row[i])
+		}
+	}
+}
+
+func (t *ReleasesTable) outputTable(tableData [][]string, columns []MetricsTableColumn) {
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf(" %-*s |", col.Width, col.Header)
+	}
+	fmt.Println()
+
+	fmt.Print("|")
+	for _, col := range columns {
+		fmt.Printf("%s|", strings.Repeat("-", col.Width+2))
+	}
+	fmt.Println()
+
+	for _, row := range tableData {
+		fmt.Print("|")
+		for i, col := range columns {
+			if col.Align == "right" {
+				fmt.Printf(" %*s |", col.Width, row[i])
+			} else {
+				fmt.Printf(" %-*s |", col.Width, row[i])
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func (t *ReleasesTable) formatValue(value string) string {
+	if value == "None" {
+		return "-"
+	}
+	return value
+}
+
+// formatDuration formats one statistic of the summary, or "-" when there were no durations
+func (t *ReleasesTable) formatDuration(s models.DurationSummary, d time.Duration) string {
+	if s.Count == 0 {
+		return "-"
+	}
+	return FormatDuration(d)
+}
+
+func (t *ReleasesTable) formatLatest(tag string) string {
+	if tag == "" {
+		return "-"
+	}
+	return tag
+}
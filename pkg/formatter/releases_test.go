@@ -0,0 +1,53 @@
+package formatter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/formatter"
+	"yokiyoki/pkg/models"
+)
+
+func sampleReleaseMetrics() []models.ReleaseMetrics {
+	return []models.ReleaseMetrics{
+		{
+			Repository:        "owner/repo",
+			Releases:          3,
+			LatestRelease:     "v1.2.0",
+			Interval:          models.DurationSummary{Count: 2, Mean: 9 * 24 * time.Hour, Median: 7 * 24 * time.Hour},
+			CommitsPerRelease: "12.3",
+			PRsPerRelease:     "4.0",
+		},
+	}
+}
+
+func TestReleasesTable_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewReleasesTable(sampleReleaseMetrics()).Output()
+	})
+
+	assert.Contains(t, output, "| Repository | Releases | Latest | Median Interval | Commits/Release | PRs/Release | Merge to Release |")
+	assert.Contains(t, output, "| owner/repo |        3 | v1.2.0 | 7d 00h 00m      |            12.3 |         4.0 | -                |")
+}
+
+func TestReleasesCsv_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewReleasesCsv(sampleReleaseMetrics()).Output()
+	})
+
+	assert.Equal(t, "Repository,Releases,LatestRelease,MedianIntervalSeconds,CommitsPerRelease,PRsPerRelease,AvgMergeToReleaseSeconds,Incomplete\n"+
+		"owner/repo,3,v1.2.0,604800,12.3,4.0,,false\n", output)
+}
+
+func TestReleasesJson_Output(t *testing.T) {
+	output := captureOutput(func() {
+		formatter.NewReleasesJson(sampleReleaseMetrics()).Output()
+	})
+
+	assert.Contains(t, output, `"latest_release": "v1.2.0"`)
+	assert.Contains(t, output, `"median": 604800`)
+	assert.Contains(t, output, `"merge_to_release": null`)
+	assert.Contains(t, output, `"commits_per_release": "12.3"`)
+	assert.NotContains(t, output, `"incomplete"`)
+}
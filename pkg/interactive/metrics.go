@@ -7,6 +7,7 @@ import (
 
 	"yokiyoki/pkg/locale"
 	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
 	"yokiyoki/pkg/services"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n"
@@ -82,12 +83,16 @@ func (m *Metrics) GetMode() string {
 			m.t("ModeMetrics"),
 			m.t("ModeCommits"),
 			m.t("ModeConversations"),
+			m.t("ModeReleases"),
+			m.t("ModeCI"),
 			m.t("ChoiceDefault1"),
 		},
 		Options: []services.PromptOption{
 			{Key: "1", Label: "metrics", Value: "metrics"},
 			{Key: "2", Label: "commits", Value: "commits"},
 			{Key: "3", Label: "conversations", Value: "conversations"},
+			{Key: "4", Label: "releases", Value: "releases"},
+			{Key: "5", Label: "ci", Value: "ci"},
 		},
 		DefaultKey: "1",
 	}
@@ -112,7 +117,7 @@ func (m *Metrics) GetRepositories() []models.Repository {
 		Formatter: func(result any) string {
 			repo := result.(models.Repository)
 			return m.tWithData("RepoAdded", map[string]interface{}{
-				"Name": fmt.Sprintf("%s/%s", repo.Owner, repo.Name),
+				"Name": repo.DisplayName(repo.Host != "" || repo.Provider != ""),
 			})
 		},
 	}
@@ -127,8 +132,9 @@ func (m *Metrics) GetRepositories() []models.Repository {
 	return repos
 }
 
-// GetRepositoriesFromArgs parses repository strings from command line arguments
-func (m *Metrics) GetRepositoriesFromArgs(repos []string) ([]models.Repository, error) {
+// GetRepositoriesFromArgs parses repository strings from command line arguments.
+// Names with glob characters, e.g. owner/service-*, expand to the owner's matching repositories.
+func (m *Metrics) GetRepositoriesFromArgs(repos []string, filter repository.DiscoverFilter) ([]models.Repository, error) {
 	var result []models.Repository
 
 	for _, repoStr := range repos {
@@ -136,10 +142,56 @@ func (m *Metrics) GetRepositoriesFromArgs(repos []string) ([]models.Repository,
 		if err != nil {
 			return nil, fmt.Errorf("invalid repository format '%s': %v", repoStr, err)
 		}
-		result = append(result, repo)
+		if !repository.IsPattern(repo.Name) {
+			result = append(result, repo)
+			continue
+		}
+		if repository.IsPattern(repo.Owner) {
+			return nil, fmt.Errorf("invalid repository format '%s': patterns are only supported in repository names", repoStr)
+		}
+
+		patternFilter := filter
+		patternFilter.Name = repo.Name
+		owner := repo
+		owner.Name = ""
+		discovered, err := repository.Discover(owner, repository.OwnerAny, patternFilter)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, discovered...)
+	}
+
+	return models.UniqueRepositories(result), nil
+}
+
+// DiscoverRepositories lists the matching repositories of the given organizations and users.
+// Owners accept the same provider and host prefixes as repositories, e.g. ghe.example.com/myorg.
+func (m *Metrics) DiscoverRepositories(orgs, users []string, filter repository.DiscoverFilter) ([]models.Repository, error) {
+	var result []models.Repository
+
+	discover := func(specs []string, kind string) error {
+		for _, spec := range specs {
+			owner, err := m.parseOwner(spec)
+			if err != nil {
+				return fmt.Errorf("invalid owner format '%s': %v", spec, err)
+			}
+			repos, err := repository.Discover(owner, kind, filter)
+			if err != nil {
+				return err
+			}
+			result = append(result, repos...)
+		}
+		return nil
+	}
+
+	if err := discover(orgs, repository.OwnerOrg); err != nil {
+		return nil, err
+	}
+	if err := discover(users, repository.OwnerUser); err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return models.UniqueRepositories(result), nil
 }
 
 // GetDays prompts user for the number of days to analyze
@@ -319,21 +371,72 @@ func (m *Metrics) GetDetailedStats() bool {
 	return m.prompt.PromptSingleChoice(config).(bool)
 }
 
-func (m *Metrics) parseRepository(input string) (models.Repository, error) {
-	parts := strings.Split(input, "/")
-	if len(parts) != 2 {
-		return models.Repository{}, fmt.Errorf("invalid format. Please use: owner/repo-name")
+// GetReviews prompts user whether to fetch pull request reviews for review metrics
+func (m *Metrics) GetReviews() bool {
+	config := services.SingleChoiceConfig{
+		Messages: []string{
+			m.t("ReviewsPrompt"),
+			"1) Yes",
+			"2) No",
+			m.t("ChoiceDefault2"),
+		},
+		Options: []services.PromptOption{
+			{Key: "1", Label: "yes", Value: true},
+			{Key: "y", Label: "yes", Value: true},
+			{Key: "2", Label: "no", Value: false},
+		},
+		DefaultKey: "2",
 	}
+	return m.prompt.PromptSingleChoice(config).(bool)
+}
 
-	owner := strings.TrimSpace(parts[0])
-	name := strings.TrimSpace(parts[1])
+// GetDeployments prompts user whether to fetch deployments for DORA metrics
+func (m *Metrics) GetDeployments() bool {
+	config := services.SingleChoiceConfig{
+		Messages: []string{
+			m.t("DeploymentsPrompt"),
+			"1) Yes",
+			"2) No",
+			m.t("ChoiceDefault2"),
+		},
+		Options: []services.PromptOption{
+			{Key: "1", Label: "yes", Value: true},
+			{Key: "y", Label: "yes", Value: true},
+			{Key: "2", Label: "no", Value: false},
+		},
+		DefaultKey: "2",
+	}
+	return m.prompt.PromptSingleChoice(config).(bool)
+}
 
-	if owner == "" || name == "" {
-		return models.Repository{}, fmt.Errorf("owner and repository name cannot be empty")
+// GetTimeline prompts user whether to fetch issue and pull request timelines for timeline metrics
+func (m *Metrics) GetTimeline() bool {
+	config := services.SingleChoiceConfig{
+		Messages: []string{
+			m.t("TimelinePrompt"),
+			"1) Yes",
+			"2) No",
+			m.t("ChoiceDefault2"),
+		},
+		Options: []services.PromptOption{
+			{Key: "1", Label: "yes", Value: true},
+			{Key: "y", Label: "yes", Value: true},
+			{Key: "2", Label: "no", Value: false},
+		},
+		DefaultKey: "2",
+	}
+	return m.prompt.PromptSingleChoice(config).(bool)
+}
+
+// parseRepository parses a repository spec; "." is the repository of the current directory's git remote
+func (m *Metrics) parseRepository(input string) (models.Repository, error) {
+	if strings.TrimSpace(input) == "." {
+		return repository.LocalRepository(".")
 	}
+	return models.ParseRepository(input)
+}
 
-	return models.Repository{
-		Owner: owner,
-		Name:  name,
-	}, nil
+// parseOwner parses an organization or user, optionally prefixed with a provider or host
+func (m *Metrics) parseOwner(input string) (models.Repository, error) {
+	return models.ParseOwner(input)
 }
@@ -0,0 +1,31 @@
+package models
+
+import "strings"
+
+// BotSuffix marks the logins of bot accounts, e.g. dependabot[bot]
+const BotSuffix = "[bot]"
+
+// AutomationUser is the user that bot activity is reported under when bots are grouped
+const AutomationUser = "automation"
+
+// Bots detects bot accounts from the [bot] suffix of their login and a configured list of names
+type Bots struct {
+	names map[string]bool
+}
+
+// NewBots creates a Bots that also treats the given names as bots, ignoring case and spaces
+func NewBots(names []string) Bots {
+	b := Bots{names: make(map[string]bool, len(names))}
+	for _, name := range names {
+		b.names[normalizeUserName(name)] = true
+	}
+	return b
+}
+
+// IsBot reports whether user is a bot account
+func (b Bots) IsBot(user string) bool {
+	if strings.HasSuffix(strings.ToLower(user), BotSuffix) {
+		return true
+	}
+	return b.names[normalizeUserName(user)]
+}
@@ -0,0 +1,19 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/models"
+)
+
+func TestBots_IsBot(t *testing.T) {
+	bots := models.NewBots([]string{"Renovate", "ci user"})
+
+	assert.True(t, bots.IsBot("dependabot[bot]"))
+	assert.True(t, bots.IsBot("github-actions[BOT]"))
+	assert.True(t, bots.IsBot("renovate"))
+	assert.True(t, bots.IsBot("CI User"))
+	assert.False(t, bots.IsBot("kotaoue"))
+	assert.False(t, models.Bots{}.IsBot("renovate"))
+}
@@ -2,14 +2,38 @@ package models
 
 import "time"
 
+// Conversation kinds used in Comment.Type
+const (
+	KindPullRequest = "pr"
+	KindIssue       = "issue"
+)
+
+// Comment sub-types, distinguishing the entries of a conversation
+const (
+	CommentDescription   = "description"
+	CommentIssueComment  = "comment"
+	CommentReview        = "review"
+	CommentReviewComment = "review_comment"
+)
+
 // Comment represents a comment (or initial body) in a PR or issue conversation.
 type Comment struct {
 	Repository string    `json:"repository"`
 	Type       string    `json:"type"` // "pr" or "issue"
+	SubType    string    `json:"sub_type,omitempty"`
 	Number     int       `json:"number"`
 	Title      string    `json:"title"`
 	Author     string    `json:"author"`
 	Body       string    `json:"body"`
 	URL        string    `json:"url"`
 	CreatedAt  time.Time `json:"created_at"`
+
+	// ID identifies review comments, so replies can refer to them
+	ID int64 `json:"id,omitempty"`
+	// Path, Line and DiffHunk locate an inline review comment in the diff
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	DiffHunk string `json:"diff_hunk,omitempty"`
+	// InReplyTo is the ID of the review comment this one answers
+	InReplyTo int64 `json:"in_reply_to,omitempty"`
 }
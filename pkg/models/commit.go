@@ -1,14 +1,79 @@
 package models
 
-import "time"
+import (
+	"net/mail"
+	"strings"
+	"time"
+)
 
 type Commit struct {
-	Repository string    `json:"repository"`
-	SHA        string    `json:"sha"`
-	Message    string    `json:"message"`
-	Author     string    `json:"author"`
-	Date       time.Time `json:"date"`
-	URL        string    `json:"url"`
-	Additions  int       `json:"additions"`
-	Deletions  int       `json:"deletions"`
+	Repository string `json:"repository"`
+	SHA        string `json:"sha"`
+	Message    string `json:"message"`
+	// Author is the author's login when it could be resolved, and the git author name otherwise
+	Author string `json:"author"`
+	// AuthorName and AuthorEmail are the author as recorded in git
+	AuthorName  string `json:"author_name,omitempty"`
+	AuthorEmail string `json:"author_email,omitempty"`
+	// CoAuthors are the authors credited with Co-authored-by trailers in the message
+	CoAuthors []CoAuthor   `json:"co_authors,omitempty"`
+	Date      time.Time    `json:"date"`
+	URL       string       `json:"url"`
+	Additions int          `json:"additions"`
+	Deletions int          `json:"deletions"`
+	Files     []FileChange `json:"files,omitempty"`
+}
+
+// FileChange represents the lines changed in a single file by a commit
+type FileChange struct {
+	Path      string `json:"path"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// CoAuthor represents an author credited with a Co-authored-by trailer
+type CoAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Login is the co-author's login when it could be resolved from the email
+	Login string `json:"login,omitempty"`
+}
+
+// User returns the co-author's login when known, and the name from the trailer otherwise
+func (c CoAuthor) User() string {
+	if c.Login != "" {
+		return c.Login
+	}
+	return c.Name
+}
+
+const coAuthorTrailer = "co-authored-by:"
+
+// ParseCoAuthors returns the co-authors of the Co-authored-by trailers in a commit message,
+// in order and without duplicate emails
+func ParseCoAuthors(message string) []CoAuthor {
+	var coAuthors []CoAuthor
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < len(coAuthorTrailer) || !strings.EqualFold(line[:len(coAuthorTrailer)], coAuthorTrailer) {
+			continue
+		}
+
+		address, err := mail.ParseAddress(strings.TrimSpace(line[len(coAuthorTrailer):]))
+		if err != nil {
+			continue
+		}
+		email := strings.ToLower(address.Address)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		name := address.Name
+		if name == "" {
+			name = address.Address
+		}
+		coAuthors = append(coAuthors, CoAuthor{Name: name, Email: address.Address})
+	}
+	return coAuthors
 }
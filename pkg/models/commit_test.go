@@ -0,0 +1,45 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/models"
+)
+
+func TestParseCoAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []models.CoAuthor
+	}{
+		{
+			name:    "no trailers",
+			message: "Fix typo",
+		},
+		{
+			name: "trailers",
+			message: "Pair on parser\n\nCo-authored-by: Alice Smith <alice@example.com>\n" +
+				"co-authored-by: bob <12345+bob@users.noreply.github.com>",
+			want: []models.CoAuthor{
+				{Name: "Alice Smith", Email: "alice@example.com"},
+				{Name: "bob", Email: "12345+bob@users.noreply.github.com"},
+			},
+		},
+		{
+			name:    "duplicate emails",
+			message: "Fix\n\nCo-authored-by: Alice <alice@example.com>\nCo-authored-by: alice <ALICE@example.com>",
+			want:    []models.CoAuthor{{Name: "Alice", Email: "alice@example.com"}},
+		},
+		{
+			name:    "malformed trailer",
+			message: "Fix\n\nCo-authored-by: someone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, models.ParseCoAuthors(tt.message))
+		})
+	}
+}
@@ -2,11 +2,64 @@ package models
 
 import "time"
 
+// Deployment status states, following GitHub's naming
+const (
+	DeploymentSuccess  = "success"
+	DeploymentFailure  = "failure"
+	DeploymentError    = "error"
+	DeploymentInactive = "inactive"
+)
+
 type Deployment struct {
 	ID          int       `json:"id"`
 	Environment string    `json:"environment"`
-	State       string    `json:"state"`
+	State       string    `json:"state"` // state of the latest status
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	SHA         string    `json:"sha"`
+	// Statuses are the status updates of the deployment, oldest first
+	Statuses []DeploymentStatus `json:"statuses,omitempty"`
+}
+
+// DeploymentStatus is a state a deployment reached, such as success or failure
+type DeploymentStatus struct {
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Failed reports whether the deployment failed without ever succeeding
+func (d Deployment) Failed() bool {
+	failed := false
+	for _, status := range d.Statuses {
+		switch status.State {
+		case DeploymentSuccess:
+			return false
+		case DeploymentFailure, DeploymentError:
+			failed = true
+		}
+	}
+	return failed
+}
+
+// SucceededAt returns when the deployment first succeeded, or nil when it never did
+func (d Deployment) SucceededAt() *time.Time {
+	for _, status := range d.Statuses {
+		if status.State == DeploymentSuccess {
+			return &status.CreatedAt
+		}
+	}
+	return nil
+}
+
+// FailedAt returns when the deployment first failed, or nil when it did not fail
+func (d Deployment) FailedAt() *time.Time {
+	if !d.Failed() {
+		return nil
+	}
+	for _, status := range d.Statuses {
+		if status.State == DeploymentFailure || status.State == DeploymentError {
+			return &status.CreatedAt
+		}
+	}
+	return nil
 }
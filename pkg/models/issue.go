@@ -12,4 +12,6 @@ type Issue struct {
 	CreatedAt time.Time  `json:"created_at"`
 	ClosedAt  *time.Time `json:"closed_at"`
 	Labels    []string   `json:"labels"`
+	// Timeline is only filled in when timelines are requested
+	Timeline []TimelineEvent `json:"timeline,omitempty"`
 }
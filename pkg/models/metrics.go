@@ -1,19 +1,162 @@
 package models
 
+import "time"
+
 // Metrics represents GitHub repository metrics
 type Metrics struct {
-	Repository        string
-	User              string // "" for repository-wide metrics
-	Commits           int
-	LinesAdded        int
-	LinesDeleted      int
-	PRsCreated        int
-	PRsMerged         int
-	PRMergeRate       string
-	AvgPRMergeTime    string
-	IssuesCreated     int
-	IssuesClosed      int
-	IssueResolveRate  string
-	AvgIssueCloseTime string
-	OpenIssues        int
+	Repository string
+	User       string // "" for repository-wide metrics
+	Team       string // "" unless metrics are broken down by team
+	// Bucket labels the interval the row covers, e.g. "2024-W05", when the period is split into
+	// buckets; it is "" otherwise. BucketStart and BucketEnd are cut to the period.
+	Bucket      string
+	BucketStart time.Time
+	BucketEnd   time.Time
+	// Commits is fractional when co-authored commits are split between their authors
+	Commits          float64
+	LinesAdded       int
+	LinesDeleted     int
+	PRsCreated       int
+	PRsMerged        int
+	PRMergeRate      string
+	PRMergeTime      DurationSummary
+	IssuesCreated    int
+	IssuesClosed     int
+	IssueResolveRate string
+	IssueCloseTime   DurationSummary
+	OpenIssues       int
+	// Review metrics, filled in when reviews are fetched
+	TimeToFirstReview DurationSummary
+	ApprovalToMerge   DurationSummary
+	ReviewsPerPR      string
+	// ReviewsReceived is the number of reviews of the PRsCreated behind ReviewsPerPR
+	ReviewsReceived int
+	ReviewsGiven    int
+	// Timeline metrics of issues and pull requests, filled in when timelines are fetched
+	TimeToFirstResponse DurationSummary
+	ReopenRate          string
+	// ItemsClosed and ItemsReopened are the issues and pull requests behind ReopenRate
+	ItemsClosed      int
+	ItemsReopened    int
+	TimeToAssignment DurationSummary
+	// Incomplete is set when some of the data behind the row could not be fetched
+	Incomplete bool
+}
+
+// DurationSummary summarizes the durations behind a time metric, such as how long each pull request
+// took to merge. Count is zero when there were none, and the other fields are then zero too.
+type DurationSummary struct {
+	Count  int
+	Mean   time.Duration
+	Min    time.Duration
+	Median time.Duration
+	P75    time.Duration
+	P90    time.Duration
+	Max    time.Duration
+}
+
+// Units of compared metrics
+const (
+	UnitCount   = "count"
+	UnitPercent = "percent"
+	UnitSeconds = "seconds"
+)
+
+// MetricComparison is one metric of a repository, or a user or team of it, in the period and in the period
+// it is compared against
+type MetricComparison struct {
+	Repository string
+	User       string // "" for repository-wide metrics
+	Team       string // "" unless metrics are broken down by team
+	Metric     string
+	// Unit is UnitCount, UnitPercent or UnitSeconds
+	Unit string
+	// Current and Previous are nil when the metric could not be measured, e.g. no pull request was merged
+	Current  *float64
+	Previous *float64
+	// Incomplete is set when some of the data behind either period could not be fetched
+	Incomplete bool
+}
+
+// Delta returns the current value minus the previous one, reporting false unless both were measured
+func (c MetricComparison) Delta() (float64, bool) {
+	if c.Current == nil || c.Previous == nil {
+		return 0, false
+	}
+	return *c.Current - *c.Previous, true
+}
+
+// Change returns the delta as a percentage of the previous value, reporting false when it is not
+// defined because a value is missing or the previous value is zero
+func (c MetricComparison) Change() (float64, bool) {
+	delta, ok := c.Delta()
+	if !ok || *c.Previous == 0 {
+		return 0, false
+	}
+	return delta / *c.Previous * 100, true
+}
+
+// DeploymentMetrics represents the DORA metrics of one environment of a repository
+type DeploymentMetrics struct {
+	Repository  string
+	Environment string
+	// Deployments counts the successful deployments in the period
+	Deployments         int
+	DeploymentFrequency string
+	// LeadTime summarizes the time from a commit to the successful deployment shipping it
+	LeadTime          DurationSummary
+	ChangeFailureRate string
+	// TimeToRestore summarizes the time from a failed deployment to the next successful one
+	TimeToRestore DurationSummary
+	// Incomplete is set when some of the data behind the row could not be fetched
+	Incomplete bool
+}
+
+// ReleaseMetrics represents the release cadence of a repository
+type ReleaseMetrics struct {
+	Repository string
+	// Releases counts the releases published in the period
+	Releases      int
+	LatestRelease string
+	// Interval summarizes the time between consecutive releases
+	Interval          DurationSummary
+	CommitsPerRelease string
+	PRsPerRelease     string
+	// MergeToRelease summarizes the time from a pull request merge to the release shipping it
+	MergeToRelease DurationSummary
+	// Incomplete is set when some of the data behind the row could not be fetched
+	Incomplete bool
+}
+
+// CIMetrics represents the health of one CI workflow of a repository, optionally on a single branch
+type CIMetrics struct {
+	Repository string
+	Workflow   string
+	// Branch is empty unless runs are grouped by branch
+	Branch string
+	// Runs counts the runs created in the period
+	Runs        int
+	SuccessRate string
+	FailureRate string
+	CancelRate  string
+	// Duration summarizes the time from the start of each completed run to its end
+	Duration DurationSummary
+	// FlakyRate is the share of completed runs that only succeeded after a re-run
+	FlakyRate string
+	// QueueTime summarizes how long jobs waited for a runner
+	QueueTime DurationSummary
+	// Incomplete is set when some of the data behind the row could not be fetched
+	Incomplete bool
+}
+
+// LabelMetrics represents how long issues and pull requests of a repository carried one label
+type LabelMetrics struct {
+	Repository string
+	Label      string
+	// Items counts the issues and pull requests that carried the label in the period
+	Items int
+	// TimeInLabel summarizes how long each item carried the label
+	TimeInLabel DurationSummary
+	// Incomplete is set when some of the data behind the row could not be fetched
+	Incomplete bool
 }
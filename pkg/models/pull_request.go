@@ -14,4 +14,11 @@ type PullRequest struct {
 	URL       string     `json:"url"`
 	Additions int        `json:"additions"`
 	Deletions int        `json:"deletions"`
+	// MergeCommitSHA is the commit the merge left on the base branch, when merged and known
+	MergeCommitSHA string `json:"merge_commit_sha,omitempty"`
+	// Reviews and ReviewRequests are only filled in when reviews are requested
+	Reviews        []Review        `json:"reviews,omitempty"`
+	ReviewRequests []ReviewRequest `json:"review_requests,omitempty"`
+	// Timeline is only filled in when timelines are requested
+	Timeline []TimelineEvent `json:"timeline,omitempty"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Release represents a published release of a repository
+type Release struct {
+	TagName    string `json:"tag_name"`
+	Name       string `json:"name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	// SHA is the commit the tag points to, when the provider reports it
+	SHA         string    `json:"sha,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	PublishedAt time.Time `json:"published_at"`
+	URL         string    `json:"url"`
+}
+
+// Tag represents a git tag and the commit it points to
+type Tag struct {
+	Name string `json:"name"`
+	SHA  string `json:"sha"`
+}
@@ -1,6 +1,108 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Code hosting providers a repository can live on
+const (
+	ProviderGitHub  = "github"
+	ProviderGitLab  = "gitlab"
+	ProviderGitea   = "gitea"
+	ProviderForgejo = "forgejo"
+)
+
+// GitHubHost is the host name of public GitHub
+const GitHubHost = "github.com"
+
 type Repository struct {
+	// Provider is the code hosting provider; "" means GitHub
+	Provider string
+	// Host is the server, e.g. a GitHub Enterprise Server; "" means the provider's default host
+	Host string
+	// Owner is the user or organization, or the (sub)group path on GitLab
 	Owner string
 	Name  string
 }
+
+// ProviderName returns the provider of the repository, defaulting to GitHub
+func (r Repository) ProviderName() string {
+	if r.Provider == "" {
+		return ProviderGitHub
+	}
+	return r.Provider
+}
+
+// FullName returns "owner/name"
+func (r Repository) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// DisplayName returns the name shown in reports. With withHost, the host is
+// prepended, or the provider prefix when the repository uses its provider's default host.
+func (r Repository) DisplayName(withHost bool) string {
+	if !withHost {
+		return r.FullName()
+	}
+	switch {
+	case r.Host != "":
+		return r.Host + "/" + r.FullName()
+	case r.ProviderName() == ProviderGitHub:
+		return GitHubHost + "/" + r.FullName()
+	default:
+		return r.Provider + ":" + r.FullName()
+	}
+}
+
+// WebHost returns the host serving the repository's web pages, defaulting to its provider's default host
+func (r Repository) WebHost() string {
+	if r.Host != "" {
+		return r.Host
+	}
+	hostsMu.RLock()
+	defer hostsMu.RUnlock()
+	return defaultHosts[r.ProviderName()]
+}
+
+// CommitURL returns the web page of a commit in the URL format of the repository's provider
+func (r Repository) CommitURL(sha string) string {
+	if r.ProviderName() == ProviderGitLab {
+		return fmt.Sprintf("https://%s/%s/%s/-/commit/%s", r.WebHost(), r.Owner, r.Name, sha)
+	}
+	return fmt.Sprintf("https://%s/%s/%s/commit/%s", r.WebHost(), r.Owner, r.Name, sha)
+}
+
+// Key identifies the repository regardless of how it was specified: the provider, the host
+// with its default filled in, and the case-insensitive owner and name
+func (r Repository) Key() string {
+	return strings.ToLower(r.ProviderName() + ":" + r.WebHost() + "/" + r.FullName())
+}
+
+// UniqueRepositories drops repositories listed more than once, keeping the first occurrence
+func UniqueRepositories(repos []Repository) []Repository {
+	seen := make(map[string]bool, len(repos))
+	var result []Repository
+	for _, repo := range repos {
+		if seen[repo.Key()] {
+			continue
+		}
+		seen[repo.Key()] = true
+		result = append(result, repo)
+	}
+	return result
+}
+
+// MixedHosts reports whether the repositories live on more than one provider or host
+func MixedHosts(repos []Repository) bool {
+	type origin struct{ provider, host string }
+	seen := make(map[origin]bool)
+	for _, r := range repos {
+		host := r.Host
+		if host == GitHubHost && r.ProviderName() == ProviderGitHub {
+			host = ""
+		}
+		seen[origin{r.ProviderName(), host}] = true
+	}
+	return len(seen) > 1
+}
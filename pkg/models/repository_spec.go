@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	hostsMu sync.RWMutex
+	// providerHosts maps well-known and configured hosts to the provider serving them
+	providerHosts = map[string]string{
+		GitHubHost:     ProviderGitHub,
+		"gitlab.com":   ProviderGitLab,
+		"gitea.com":    ProviderGitea,
+		"codeberg.org": ProviderForgejo,
+	}
+	// defaultHosts maps providers to the host used when a repository has none
+	defaultHosts = map[string]string{
+		ProviderGitHub:  GitHubHost,
+		ProviderGitLab:  "gitlab.com",
+		ProviderGitea:   "gitea.com",
+		ProviderForgejo: "codeberg.org",
+	}
+	// configuredHosts holds the other hosts set up in the config, e.g. GitHub Enterprise Servers
+	configuredHosts = map[string]bool{}
+)
+
+// SetDefaultHost makes host the default instance of provider, so URLs on it are
+// recognized as that provider and parsed without an explicit host
+func SetDefaultHost(provider, host string) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+	providerHosts[host] = provider
+	defaultHosts[provider] = host
+}
+
+// AddHost makes host recognized as the host prefix of specs like host/owner/name
+func AddHost(host string) {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+	configuredHosts[host] = true
+}
+
+// ParseRepository parses a repository spec. Accepted forms are:
+//
+//	owner/name, host/owner/name and gitlab:group/subgroup/project
+//	https://host/owner/name(.git), ssh://git@host/owner/name.git and git@host:owner/name.git
+//
+// Trailing paths such as owner/name/pull/12 or GitLab's /-/merge_requests/1 are ignored.
+// Hosts of known providers select the provider unless a provider prefix is given. Only known
+// or configured hosts are taken from host/owner/name; other hosts need a URL.
+func ParseRepository(spec string) (Repository, error) {
+	provider, host, parts := splitSpec(spec, 3)
+	if provider == ProviderGitLab {
+		// GitLab paths nest groups, so only the /-/ separator marks where the project ends
+		for i, part := range parts {
+			if part == "-" {
+				parts = parts[:i]
+				break
+			}
+		}
+	} else if len(parts) > 2 {
+		parts = parts[:2]
+	}
+
+	if len(parts) < 2 {
+		return Repository{}, fmt.Errorf("invalid format. Please use: [host/]owner/repo-name, a repository URL, gitlab:group/project, gitea:owner/repo-name or forgejo:owner/repo-name")
+	}
+
+	owner := strings.Join(parts[:len(parts)-1], "/")
+	name := strings.TrimSuffix(parts[len(parts)-1], ".git")
+	if owner == "" || name == "" {
+		return Repository{}, fmt.Errorf("owner and repository name cannot be empty")
+	}
+
+	return Repository{Provider: provider, Host: host, Owner: owner, Name: name}, nil
+}
+
+// ParseOwner parses an organization, user or GitLab group, optionally given as a URL
+// or prefixed with a provider or host. The result has no Name.
+func ParseOwner(spec string) (Repository, error) {
+	provider, host, parts := splitSpec(spec, 2)
+	if len(parts) == 0 || (provider != ProviderGitLab && len(parts) != 1) {
+		return Repository{}, fmt.Errorf("invalid format. Please use: [host/]owner")
+	}
+	return Repository{Provider: provider, Host: host, Owner: strings.Join(parts, "/")}, nil
+}
+
+// splitSpec splits a spec into its provider, host and path segments. Without a provider prefix,
+// URL or SSH host, a leading known or configured host is taken as the host when at least minParts
+// segments remain, so owners and groups may contain dots. The host is "" when it is the provider's default.
+func splitSpec(spec string, minParts int) (provider, host string, parts []string) {
+	spec = strings.TrimSpace(spec)
+	for _, p := range []string{ProviderGitLab, ProviderGitea, ProviderForgejo} {
+		if rest, ok := strings.CutPrefix(spec, p+":"); ok {
+			provider = p
+			spec = rest
+			break
+		}
+	}
+
+	path := spec
+	if u, err := url.Parse(spec); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if h, p, ok := scpLike(spec); ok {
+		host, path = h, p
+	} else {
+		path, _, _ = strings.Cut(path, "?")
+		path, _, _ = strings.Cut(path, "#")
+	}
+
+	for _, part := range strings.Split(path, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	hostsMu.RLock()
+	defer hostsMu.RUnlock()
+	if host == "" && provider == "" && len(parts) >= minParts && (providerHosts[parts[0]] != "" || configuredHosts[parts[0]]) {
+		host = parts[0]
+		parts = parts[1:]
+	}
+	if provider == "" {
+		provider = providerHosts[host]
+	}
+	if provider == ProviderGitHub {
+		provider = ""
+	}
+	if host == defaultHosts[Repository{Provider: provider}.ProviderName()] {
+		host = ""
+	}
+	return provider, host, parts
+}
+
+// scpLike splits an SSH remote in scp syntax, e.g. git@github.com:owner/name.git
+func scpLike(spec string) (host, path string, ok bool) {
+	before, after, found := strings.Cut(spec, ":")
+	if !found || strings.Contains(before, "/") {
+		return "", "", false
+	}
+	_, host, hasUser := strings.Cut(before, "@")
+	if !hasUser || host == "" {
+		return "", "", false
+	}
+	return host, after, true
+}
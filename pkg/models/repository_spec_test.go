@@ -0,0 +1,123 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/models"
+)
+
+func TestParseRepository(t *testing.T) {
+	models.AddHost("ghe.example.com")
+
+	tests := []struct {
+		name string
+		spec string
+		want models.Repository
+	}{
+		{
+			name: "owner and name",
+			spec: "kotaoue/chiken",
+			want: models.Repository{Owner: "kotaoue", Name: "chiken"},
+		},
+		{
+			name: "name with dots",
+			spec: "kotaoue/kota.oue.me",
+			want: models.Repository{Owner: "kotaoue", Name: "kota.oue.me"},
+		},
+		{
+			name: "trailing path",
+			spec: "kotaoue/chiken/pull/12",
+			want: models.Repository{Owner: "kotaoue", Name: "chiken"},
+		},
+		{
+			name: "https URL",
+			spec: "https://github.com/kotaoue/chiken.git",
+			want: models.Repository{Owner: "kotaoue", Name: "chiken"},
+		},
+		{
+			name: "URL with trailing path and fragment",
+			spec: "https://github.com/kotaoue/chiken/issues/3#issuecomment-1",
+			want: models.Repository{Owner: "kotaoue", Name: "chiken"},
+		},
+		{
+			name: "scp-like SSH remote",
+			spec: "git@github.com:kotaoue/chiken.git",
+			want: models.Repository{Owner: "kotaoue", Name: "chiken"},
+		},
+		{
+			name: "SSH URL on an enterprise host",
+			spec: "ssh://git@ghe.example.com:2222/org/repo.git",
+			want: models.Repository{Host: "ghe.example.com", Owner: "org", Name: "repo"},
+		},
+		{
+			name: "host prefix",
+			spec: "ghe.example.com/org/repo/tree/main",
+			want: models.Repository{Host: "ghe.example.com", Owner: "org", Name: "repo"},
+		},
+		{
+			name: "owner with dots",
+			spec: "foo.bar/proj",
+			want: models.Repository{Owner: "foo.bar", Name: "proj"},
+		},
+		{
+			name: "known host prefix",
+			spec: "gitlab.com/group/project",
+			want: models.Repository{Provider: models.ProviderGitLab, Owner: "group", Name: "project"},
+		},
+		{
+			name: "GitLab prefix with dotted group",
+			spec: "gitlab:my.group/sub/project",
+			want: models.Repository{Provider: models.ProviderGitLab, Owner: "my.group/sub", Name: "project"},
+		},
+		{
+			name: "GitLab URL with nested groups",
+			spec: "https://gitlab.com/group/sub/project/-/merge_requests/1",
+			want: models.Repository{Provider: models.ProviderGitLab, Owner: "group/sub", Name: "project"},
+		},
+		{
+			name: "GitLab prefix on a self-managed host",
+			spec: "gitlab:https://gitlab.example.com/group/project.git",
+			want: models.Repository{Provider: models.ProviderGitLab, Host: "gitlab.example.com", Owner: "group", Name: "project"},
+		},
+		{
+			name: "Codeberg URL",
+			spec: "https://codeberg.org/forgejo/forgejo",
+			want: models.Repository{Provider: models.ProviderForgejo, Owner: "forgejo", Name: "forgejo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := models.ParseRepository(tt.spec)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRepository_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "kotaoue", "https://github.com/kotaoue", "kotaoue/.git"} {
+		_, err := models.ParseRepository(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseOwner(t *testing.T) {
+	models.AddHost("ghe.example.com")
+
+	got, err := models.ParseOwner("ghe.example.com/platform")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Repository{Host: "ghe.example.com", Owner: "platform"}, got)
+
+	got, err = models.ParseOwner("https://github.com/kotaoue/")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Repository{Owner: "kotaoue"}, got)
+
+	got, err = models.ParseOwner("gitlab:my.group/sub")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Repository{Provider: models.ProviderGitLab, Owner: "my.group/sub"}, got)
+
+	_, err = models.ParseOwner("kotaoue/chiken")
+	assert.Error(t, err)
+}
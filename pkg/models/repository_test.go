@@ -0,0 +1,110 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/models"
+)
+
+func TestRepository_DisplayName(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     models.Repository
+		withHost bool
+		want     string
+	}{
+		{
+			name: "without host",
+			repo: models.Repository{Host: "ghe.example.com", Owner: "org", Name: "repo"},
+			want: "org/repo",
+		},
+		{
+			name:     "github default host",
+			repo:     models.Repository{Owner: "org", Name: "repo"},
+			withHost: true,
+			want:     "github.com/org/repo",
+		},
+		{
+			name:     "enterprise host",
+			repo:     models.Repository{Host: "ghe.example.com", Owner: "org", Name: "repo"},
+			withHost: true,
+			want:     "ghe.example.com/org/repo",
+		},
+		{
+			name:     "provider default host",
+			repo:     models.Repository{Provider: models.ProviderGitLab, Owner: "group/sub", Name: "repo"},
+			withHost: true,
+			want:     "gitlab:group/sub/repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.repo.DisplayName(tt.withHost))
+		})
+	}
+}
+
+func TestRepository_CommitURL(t *testing.T) {
+	tests := []struct {
+		name string
+		repo models.Repository
+		want string
+	}{
+		{
+			name: "github",
+			repo: models.Repository{Owner: "org", Name: "repo"},
+			want: "https://github.com/org/repo/commit/abc",
+		},
+		{
+			name: "enterprise host",
+			repo: models.Repository{Host: "ghe.example.com", Owner: "org", Name: "repo"},
+			want: "https://ghe.example.com/org/repo/commit/abc",
+		},
+		{
+			name: "gitlab nested group",
+			repo: models.Repository{Provider: models.ProviderGitLab, Owner: "group/sub", Name: "repo"},
+			want: "https://gitlab.com/group/sub/repo/-/commit/abc",
+		},
+		{
+			name: "self-hosted gitlab",
+			repo: models.Repository{Provider: models.ProviderGitLab, Host: "git.example.com", Owner: "group", Name: "repo"},
+			want: "https://git.example.com/group/repo/-/commit/abc",
+		},
+		{
+			name: "forgejo",
+			repo: models.Repository{Provider: models.ProviderForgejo, Owner: "org", Name: "repo"},
+			want: "https://codeberg.org/org/repo/commit/abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.repo.CommitURL("abc"))
+		})
+	}
+}
+
+func TestUniqueRepositories(t *testing.T) {
+	repos := []models.Repository{
+		{Owner: "Org", Name: "Repo"},
+		{Host: "github.com", Owner: "org", Name: "repo"},
+		{Host: "ghe.example.com", Owner: "org", Name: "repo"},
+		{Provider: models.ProviderGitLab, Owner: "org", Name: "repo"},
+		{Provider: models.ProviderGitLab, Host: "gitlab.com", Owner: "org", Name: "repo"},
+	}
+
+	assert.Equal(t, []models.Repository{repos[0], repos[2], repos[3]}, models.UniqueRepositories(repos))
+}
+
+func TestMixedHosts(t *testing.T) {
+	github := models.Repository{Owner: "org", Name: "a"}
+	explicit := models.Repository{Host: "github.com", Owner: "org", Name: "b"}
+	enterprise := models.Repository{Host: "ghe.example.com", Owner: "org", Name: "c"}
+	gitlab := models.Repository{Provider: models.ProviderGitLab, Owner: "org", Name: "d"}
+
+	assert.False(t, models.MixedHosts([]models.Repository{github, explicit}))
+	assert.True(t, models.MixedHosts([]models.Repository{github, enterprise}))
+	assert.True(t, models.MixedHosts([]models.Repository{github, gitlab}))
+}
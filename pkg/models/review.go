@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Review states, following GitHub's naming
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+	ReviewDismissed        = "DISMISSED"
+)
+
+// Review represents a submitted pull request review
+type Review struct {
+	Reviewer    string    `json:"reviewer"`
+	State       string    `json:"state"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	// Body is the review summary, often empty
+	Body string `json:"body,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// ReviewRequest represents a request for a user or team to review a pull request
+type ReviewRequest struct {
+	Reviewer    string    `json:"reviewer"`
+	RequestedAt time.Time `json:"requested_at"`
+}
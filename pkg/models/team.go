@@ -0,0 +1,45 @@
+package models
+
+import "sort"
+
+// OtherTeam is the team that users not assigned to any team are reported under
+const OtherTeam = "other"
+
+// Teams maps users to the team they belong to, ignoring case and spaces like NewUserName
+type Teams struct {
+	teams map[string]string
+}
+
+// NewTeams creates Teams from the logins and aliases of each team's members. A user listed in
+// several teams belongs to the first of them in alphabetical order.
+func NewTeams(members map[string][]string) Teams {
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := Teams{teams: make(map[string]string)}
+	for _, name := range names {
+		for _, user := range members[name] {
+			key := normalizeUserName(user)
+			if _, ok := t.teams[key]; !ok {
+				t.teams[key] = name
+			}
+		}
+	}
+	return t
+}
+
+// Team returns the team of user, or OtherTeam when user is in none
+func (t Teams) Team(user string) string {
+	if team, ok := t.teams[normalizeUserName(user)]; ok {
+		return team
+	}
+	return OtherTeam
+}
+
+// Empty reports whether no team has any member
+func (t Teams) Empty() bool {
+	return len(t.teams) == 0
+}
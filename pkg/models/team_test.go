@@ -0,0 +1,25 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/models"
+)
+
+func TestTeams_Team(t *testing.T) {
+	teams := models.NewTeams(map[string][]string{
+		"backend":  {"alice", "Alice Smith", "bob"},
+		"frontend": {"carol", "bob"},
+	})
+
+	assert.Equal(t, "backend", teams.Team("alice"))
+	assert.Equal(t, "backend", teams.Team("alicesmith"))
+	assert.Equal(t, "backend", teams.Team("Alice Smith"))
+	assert.Equal(t, "backend", teams.Team("bob"))
+	assert.Equal(t, "frontend", teams.Team("Carol"))
+	assert.Equal(t, models.OtherTeam, teams.Team("dave"))
+	assert.False(t, teams.Empty())
+	assert.True(t, models.Teams{}.Empty())
+	assert.Equal(t, models.OtherTeam, models.Teams{}.Team("alice"))
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Timeline event types, following GitHub's naming
+const (
+	EventCommented       = "commented"
+	EventReviewed        = "reviewed"
+	EventLabeled         = "labeled"
+	EventUnlabeled       = "unlabeled"
+	EventAssigned        = "assigned"
+	EventUnassigned      = "unassigned"
+	EventClosed          = "closed"
+	EventReopened        = "reopened"
+	EventCrossReferenced = "cross-referenced"
+)
+
+// TimelineEvent represents an event on the timeline of an issue or pull request
+type TimelineEvent struct {
+	Event string `json:"event"`
+	// Actor is the user who caused the event, e.g. the commenter or the user who closed the issue
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	// Label is set for labeled and unlabeled events
+	Label string `json:"label,omitempty"`
+	// Assignee is set for assigned and unassigned events
+	Assignee string `json:"assignee,omitempty"`
+	// Source is the referencing issue or pull request of cross-referenced events, e.g. "owner/repo#12"
+	Source string `json:"source,omitempty"`
+}
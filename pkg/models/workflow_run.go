@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Workflow run statuses and conclusions, following GitHub Actions' naming
+const (
+	RunCompleted = "completed"
+
+	ConclusionSuccess   = "success"
+	ConclusionFailure   = "failure"
+	ConclusionCancelled = "cancelled"
+	ConclusionTimedOut  = "timed_out"
+)
+
+// WorkflowRun represents a run of a CI workflow
+type WorkflowRun struct {
+	ID         int64  `json:"id"`
+	Workflow   string `json:"workflow"`
+	Branch     string `json:"branch"`
+	Event      string `json:"event"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	// Attempt is the number of the latest attempt; it is above 1 when the run was re-run
+	Attempt   int       `json:"attempt"`
+	CreatedAt time.Time `json:"created_at"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	URL       string    `json:"url"`
+	// Jobs are the jobs of the latest attempt
+	Jobs []WorkflowJob `json:"jobs,omitempty"`
+}
+
+// WorkflowJob represents a job of a workflow run
+type WorkflowJob struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Conclusion  string    `json:"conclusion"`
+	CreatedAt   time.Time `json:"created_at"`
+	StartedAt   time.Time `json:"started_at"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// Completed reports whether the run has finished
+func (r WorkflowRun) Completed() bool {
+	return r.Status == RunCompleted
+}
+
+// Duration returns how long a completed run took from its start to its last update
+func (r WorkflowRun) Duration() time.Duration {
+	if !r.Completed() || r.StartedAt.IsZero() || r.UpdatedAt.Before(r.StartedAt) {
+		return 0
+	}
+	return r.UpdatedAt.Sub(r.StartedAt)
+}
+
+// Queued returns how long the job waited for a runner, or false when it never started
+func (j WorkflowJob) Queued() (time.Duration, bool) {
+	if j.StartedAt.IsZero() || j.CreatedAt.IsZero() || j.StartedAt.Before(j.CreatedAt) {
+		return 0, false
+	}
+	return j.StartedAt.Sub(j.CreatedAt), true
+}
@@ -0,0 +1,35 @@
+package parallel
+
+import "sync"
+
+// Map applies fn to every item using at most workers goroutines and returns the
+// results in the same order as items. A workers value below 1 runs serially.
+func Map[T, R any](items []T, workers int, fn func(T) R) []R {
+	results := make([]R, len(items))
+	if workers <= 1 || len(items) <= 1 {
+		for i, item := range items {
+			results[i] = fn(item)
+		}
+		return results
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = fn(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,42 @@
+package parallel_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/parallel"
+)
+
+func TestMap_PreservesOrder(t *testing.T) {
+	items := []int{5, 1, 4, 2, 3}
+	results := parallel.Map(items, 3, func(n int) int {
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n * 10
+	})
+	assert.Equal(t, []int{50, 10, 40, 20, 30}, results)
+}
+
+func TestMap_BoundsWorkers(t *testing.T) {
+	var running, peak int32
+	items := make([]int, 20)
+	parallel.Map(items, 4, func(int) struct{} {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return struct{}{}
+	})
+	assert.LessOrEqual(t, peak, int32(4))
+}
+
+func TestMap_Serial(t *testing.T) {
+	results := parallel.Map([]string{"a", "b"}, 0, func(s string) string { return s + s })
+	assert.Equal(t, []string{"aa", "bb"}, results)
+}
@@ -0,0 +1,494 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"yokiyoki/pkg/cache"
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/parallel"
+)
+
+// syncOverlap is subtracted from the last sync time when fetching incrementally,
+// since the API filters on calendar days
+const syncOverlap = 24 * time.Hour
+
+// CacheMode controls whether CachedFetcher touches the network
+type CacheMode struct {
+	// Refresh ignores cached data and refetches everything
+	Refresh bool
+	// Offline serves cached data only and never calls the wrapped fetcher
+	Offline bool
+}
+
+// syncEntry is a cached resource list together with the range it covers
+type syncEntry[T any] struct {
+	// Since is the earliest creation date covered; zero means everything
+	Since time.Time `json:"since"`
+	// Until is the latest creation date covered; zero means open-ended
+	Until time.Time `json:"until"`
+	// Cursor is the time the last sync started; the next incremental sync starts from here
+	Cursor time.Time `json:"cursor"`
+	Items  []T       `json:"items"`
+}
+
+// covers reports whether the entry holds every item created between since and until
+func (e syncEntry[T]) covers(since, until time.Time) bool {
+	if e.Since.After(since) {
+		return false
+	}
+	return e.Until.IsZero() || (!until.IsZero() && !e.Until.Before(until))
+}
+
+// commitStats is the cached line statistics of a commit, looked up by its immutable SHA
+type commitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// CachedFetcher wraps a Fetcher with a persistent on-disk cache.
+// Subsequent runs only fetch the pull requests and issues that changed since the previous sync.
+type CachedFetcher struct {
+	inner Fetcher
+	store *cache.Store
+	mode  CacheMode
+}
+
+// NewCachedFetcher creates a CachedFetcher around inner, persisting into store
+func NewCachedFetcher(inner Fetcher, store *cache.Store, mode CacheMode) *CachedFetcher {
+	return &CachedFetcher{inner: inner, store: store, mode: mode}
+}
+
+// Commits returns the commits since the given date, refetching their list on every online run.
+// Line statistics are cached by SHA, so only those of new commits are fetched.
+func (f *CachedFetcher) Commits(repo models.Repository, since time.Time, detailedStats bool) ([]models.Commit, error) {
+	key := cacheKey(repo, "commits")
+	var entry syncEntry[models.Commit]
+	found, err := f.load(key, &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	var uncovered error
+	switch {
+	case f.mode.Offline:
+		if !found {
+			return nil, offlineError(repo, "commits")
+		}
+		if !entry.covers(since, time.Time{}) {
+			uncovered = uncoveredError(repo, "commits", entry.Since)
+		}
+	default:
+		// Commits are listed by commit date, which can be long before they are pushed or merged,
+		// so the requested range is always refetched; cached items older than it are kept
+		cursor := time.Now()
+		items, err := f.inner.Commits(repo, since, false)
+		if err != nil {
+			return nil, err
+		}
+		if found && entry.covers(since, time.Time{}) {
+			for _, commit := range entry.Items {
+				if commit.Date.Before(truncateDay(since)) {
+					items = append(items, commit)
+				}
+			}
+			entry.Items = items
+		} else {
+			entry = syncEntry[models.Commit]{Since: since, Items: items}
+		}
+		entry.Cursor = cursor
+		f.save(key, entry)
+	}
+
+	var commits []models.Commit
+	for _, commit := range entry.Items {
+		if !commit.Date.Before(truncateDay(since)) {
+			commits = append(commits, commit)
+		}
+	}
+
+	if detailedStats {
+		return commits, errors.Join(uncovered, f.applyCommitStats(repo, commits))
+	}
+	return commits, uncovered
+}
+
+// applyCommitStats fills in line statistics, fetching only SHAs not seen before.
+// Failed lookups are not cached, so they are retried on the next run.
+func (f *CachedFetcher) applyCommitStats(repo models.Repository, commits []models.Commit) error {
+	key := cacheKey(repo, "commit-stats")
+	stats := make(map[string]commitStats)
+	if _, err := f.load(key, &stats); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	var missing []string
+	for _, commit := range commits {
+		if _, ok := stats[commit.SHA]; !ok {
+			missing = append(missing, commit.SHA)
+		}
+	}
+
+	var failed []error
+	if len(missing) > 0 && !f.mode.Offline {
+		type result struct {
+			stats commitStats
+			err   error
+		}
+		fetched := parallel.Map(missing, Concurrency, func(sha string) result {
+			additions, deletions, err := f.inner.CommitStats(repo, sha)
+			return result{stats: commitStats{Additions: additions, Deletions: deletions}, err: err}
+		})
+		for i, r := range fetched {
+			if r.err != nil {
+				failed = append(failed, r.err)
+				continue
+			}
+			stats[missing[i]] = r.stats
+		}
+		f.save(key, stats)
+	}
+
+	for i := range commits {
+		s := stats[commits[i].SHA]
+		commits[i].Additions = s.Additions
+		commits[i].Deletions = s.Deletions
+	}
+
+	if f.mode.Offline && len(missing) > 0 {
+		return fmt.Errorf("%w: no cached line stats for %d of %d commits in %s/%s (offline mode)",
+			ErrIncomplete, len(missing), len(commits), repo.Owner, repo.Name)
+	}
+	return statsError(repo, failed, len(commits))
+}
+
+// PullRequests returns cached pull requests, fetching only those updated since the last sync
+func (f *CachedFetcher) PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	var incremental func(time.Time) ([]models.PullRequest, error)
+	if u, ok := f.inner.(updatedFetcher); ok {
+		incremental = func(updated time.Time) ([]models.PullRequest, error) {
+			return u.PullRequestsUpdatedSince(repo, updated)
+		}
+	}
+
+	return syncList(f, repo, "pulls", since, until,
+		func() ([]models.PullRequest, error) { return f.inner.PullRequests(repo, since, until) },
+		incremental,
+		func(pr models.PullRequest) int { return pr.Number },
+		func(pr models.PullRequest) time.Time { return pr.CreatedAt })
+}
+
+// PullRequestsUpdatedSince lists updated pull requests through the wrapped fetcher. Offline, every
+// cached pull request is served, as their update times are not kept.
+func (f *CachedFetcher) PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error) {
+	return updatedSince(f, repo, "pulls", func(u updatedFetcher) ([]models.PullRequest, error) {
+		return u.PullRequestsUpdatedSince(repo, updated)
+	})
+}
+
+// IssuesUpdatedSince lists updated issues through the wrapped fetcher. Offline, every cached
+// issue is served, as their update times are not kept.
+func (f *CachedFetcher) IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error) {
+	return updatedSince(f, repo, "issues", func(u updatedFetcher) ([]models.Issue, error) {
+		return u.IssuesUpdatedSince(repo, updated)
+	})
+}
+
+// updatedSince serves the items of resource updated since a point in time. Offline, the cache holds
+// no items created before its range, so the result is incomplete unless the range is unbounded.
+func updatedSince[T any](f *CachedFetcher, repo models.Repository, resource string, fetch func(u updatedFetcher) ([]T, error)) ([]T, error) {
+	if !f.mode.Offline {
+		u, ok := f.inner.(updatedFetcher)
+		if !ok {
+			return nil, errNoIncrementalSync
+		}
+		return fetch(u)
+	}
+
+	var entry syncEntry[T]
+	found, err := f.load(cacheKey(repo, resource), &entry)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, offlineError(repo, resource)
+	}
+	if !entry.Since.IsZero() {
+		return entry.Items, uncoveredError(repo, resource, entry.Since)
+	}
+	return entry.Items, nil
+}
+
+// Issues returns cached issues, fetching only those updated since the last sync
+func (f *CachedFetcher) Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	var incremental func(time.Time) ([]models.Issue, error)
+	if u, ok := f.inner.(updatedFetcher); ok {
+		incremental = func(updated time.Time) ([]models.Issue, error) {
+			return u.IssuesUpdatedSince(repo, updated)
+		}
+	}
+
+	return syncList(f, repo, "issues", since, until,
+		func() ([]models.Issue, error) { return f.inner.Issues(repo, since, until) },
+		incremental,
+		func(issue models.Issue) int { return issue.Number },
+		func(issue models.Issue) time.Time { return issue.CreatedAt })
+}
+
+// Comments returns the comments of a pull request or issue, refreshing them unless offline
+func (f *CachedFetcher) Comments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	return refreshed(f, repo, fmt.Sprintf("comments/%s/%d", kind, number), fmt.Sprintf("comments on #%d", number),
+		func() ([]models.Comment, error) { return f.inner.Comments(repo, kind, number) })
+}
+
+// Reviews returns the reviews of a pull request, refreshing them unless offline
+func (f *CachedFetcher) Reviews(repo models.Repository, number int) ([]models.Review, error) {
+	return refreshed(f, repo, fmt.Sprintf("reviews/%d", number), fmt.Sprintf("reviews of #%d", number),
+		func() ([]models.Review, error) { return f.inner.Reviews(repo, number) })
+}
+
+// ReviewRequests returns the review requests of a pull request, refreshing them unless offline
+func (f *CachedFetcher) ReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error) {
+	return refreshed(f, repo, fmt.Sprintf("review-requests/%d", number), fmt.Sprintf("review requests of #%d", number),
+		func() ([]models.ReviewRequest, error) { return f.inner.ReviewRequests(repo, number) })
+}
+
+// Timeline returns the timeline events of a pull request or issue, refreshing them unless offline
+func (f *CachedFetcher) Timeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error) {
+	return refreshed(f, repo, fmt.Sprintf("timeline/%s/%d", kind, number), fmt.Sprintf("timeline of #%d", number),
+		func() ([]models.TimelineEvent, error) { return f.inner.Timeline(repo, kind, number) })
+}
+
+// Deployments returns cached deployments. Statuses change after creation, so deployments
+// are refetched on every online run and served from the cache offline.
+func (f *CachedFetcher) Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	return syncList(f, repo, "deployments", since, until,
+		func() ([]models.Deployment, error) { return f.inner.Deployments(repo, since, until) },
+		nil,
+		func(d models.Deployment) int { return d.ID },
+		func(d models.Deployment) time.Time { return d.CreatedAt })
+}
+
+// Releases returns the releases of a repository, refreshing them unless offline
+func (f *CachedFetcher) Releases(repo models.Repository) ([]models.Release, error) {
+	return refreshed(f, repo, "releases", "releases",
+		func() ([]models.Release, error) { return f.inner.Releases(repo) })
+}
+
+// Tags returns the tags of a repository, refreshing them unless offline
+func (f *CachedFetcher) Tags(repo models.Repository) ([]models.Tag, error) {
+	return refreshed(f, repo, "tags", "tags",
+		func() ([]models.Tag, error) { return f.inner.Tags(repo) })
+}
+
+// WorkflowRuns returns cached workflow runs. Runs in progress change after creation, so runs
+// are refetched on every online run and served from the cache offline.
+func (f *CachedFetcher) WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	return syncList(f, repo, "workflow-runs", since, until,
+		func() ([]models.WorkflowRun, error) { return f.inner.WorkflowRuns(repo, since, until) },
+		nil,
+		func(r models.WorkflowRun) int { return int(r.ID) },
+		func(r models.WorkflowRun) time.Time { return r.CreatedAt })
+}
+
+// ReviewComments returns the inline review comments of a pull request, refreshing them unless offline
+func (f *CachedFetcher) ReviewComments(repo models.Repository, number int) ([]models.Comment, error) {
+	return refreshed(f, repo, fmt.Sprintf("review-comments/%d", number), fmt.Sprintf("review comments on #%d", number),
+		func() ([]models.Comment, error) { return f.inner.ReviewComments(repo, number) })
+}
+
+// refreshed fetches a resource and caches it, serving the cached copy in offline mode.
+// These resources have no update filter, so they are refetched on every online run.
+func refreshed[T any](f *CachedFetcher, repo models.Repository, resource, description string, fetch func() ([]T, error)) ([]T, error) {
+	key := cacheKey(repo, resource)
+	if f.mode.Offline {
+		var items []T
+		found, err := f.load(key, &items)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, offlineError(repo, description)
+		}
+		return items, nil
+	}
+
+	items, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	f.save(key, items)
+	return items, nil
+}
+
+// CommitStats returns the line statistics of a commit, consulting the cache first
+func (f *CachedFetcher) CommitStats(repo models.Repository, sha string) (int, int, error) {
+	commits := []models.Commit{{SHA: sha}}
+	err := f.applyCommitStats(repo, commits)
+	return commits[0].Additions, commits[0].Deletions, err
+}
+
+// CompareCommits returns the commits between two commits. The commits between two SHAs never
+// change, so a comparison is fetched once and then served from the cache, also offline.
+func (f *CachedFetcher) CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	key := cacheKey(repo, "compare/"+base+"/"+head)
+	var commits []models.Commit
+	found, err := f.load(key, &commits)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return commits, nil
+	}
+	if f.mode.Offline {
+		return nil, offlineError(repo, "comparison of "+base+"..."+head)
+	}
+
+	commits, err = f.inner.CompareCommits(repo, base, head)
+	if err != nil {
+		return nil, err
+	}
+	f.save(key, commits)
+	return commits, nil
+}
+
+// syncList serves a list resource from the cache, syncing it first unless offline.
+// When the cached range covers the request, only items updated since the last sync are fetched.
+func syncList[T any](
+	f *CachedFetcher,
+	repo models.Repository,
+	resource string,
+	since, until time.Time,
+	full func() ([]T, error),
+	incremental func(updated time.Time) ([]T, error),
+	number func(T) int,
+	createdAt func(T) time.Time,
+) ([]T, error) {
+	key := cacheKey(repo, resource)
+	var entry syncEntry[T]
+	found, err := f.load(key, &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	// Incomplete results are returned with their error but not saved, so the next run fetches them again
+	synced := false
+	var incomplete error
+	if f.mode.Offline {
+		if !found {
+			return nil, offlineError(repo, resource)
+		}
+		if !entry.covers(since, until) {
+			incomplete = uncoveredError(repo, resource, entry.Since)
+		}
+		synced = true
+	} else if found && incremental != nil && entry.covers(since, until) {
+		cursor := time.Now()
+		fresh, err := incremental(entry.Cursor.Add(-syncOverlap))
+		if err == nil || errors.Is(err, ErrIncomplete) {
+			entry.Items = mergeBy(entry.Items, fresh, func(item T) int { return number(item) })
+			entry.Cursor = cursor
+			if err == nil {
+				f.save(key, entry)
+			}
+			incomplete = err
+			synced = true
+		} else if !errors.Is(err, errNoIncrementalSync) {
+			return nil, err
+		}
+	}
+
+	if !synced {
+		cursor := time.Now()
+		items, err := full()
+		if err != nil && !errors.Is(err, ErrIncomplete) {
+			return nil, err
+		}
+		entry = syncEntry[T]{Since: since, Cursor: cursor, Items: items}
+		// A range ending before the sync is closed; new items would fall outside it
+		if !until.IsZero() && until.Before(truncateDay(cursor)) {
+			entry.Until = until
+		}
+		if err == nil {
+			f.save(key, entry)
+		}
+		incomplete = err
+	}
+
+	var result []T
+	for _, item := range entry.Items {
+		if inDateRange(createdAt(item), since, until) {
+			result = append(result, item)
+		}
+	}
+	return result, incomplete
+}
+
+func (f *CachedFetcher) load(key string, v any) (bool, error) {
+	if f.mode.Refresh {
+		return false, nil
+	}
+	return f.store.Load(key, v)
+}
+
+func (f *CachedFetcher) save(key string, v any) {
+	if err := f.store.Save(key, v); err != nil {
+		fmt.Printf("Warning: could not update cache: %v\n", err)
+	}
+}
+
+// mergeBy merges fresh items into cached ones, replacing entries with the same identity
+func mergeBy[T any, K comparable](cached, fresh []T, id func(T) K) []T {
+	index := make(map[K]int, len(cached))
+	merged := append([]T(nil), cached...)
+	for i, item := range merged {
+		index[id(item)] = i
+	}
+	for _, item := range fresh {
+		if i, ok := index[id(item)]; ok {
+			merged[i] = item
+			continue
+		}
+		index[id(item)] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// inDateRange reports whether t falls within the calendar days from since to until.
+// Zero bounds are open.
+func inDateRange(t, since, until time.Time) bool {
+	if !since.IsZero() && t.Before(truncateDay(since)) {
+		return false
+	}
+	if !until.IsZero() && !t.Before(truncateDay(until).AddDate(0, 0, 1)) {
+		return false
+	}
+	return true
+}
+
+// cacheKey returns the store key of a repository resource. Repositories on
+// providers other than GitHub are namespaced by provider, and those on
+// non-default hosts by host.
+func cacheKey(repo models.Repository, resource string) string {
+	key := fmt.Sprintf("%s/%s/%s", repo.Owner, repo.Name, resource)
+	if repo.Host != "" && repo.Host != DefaultHost {
+		key = repo.Host + "/" + key
+	}
+	if repo.ProviderName() != models.ProviderGitHub {
+		key = repo.Provider + "/" + key
+	}
+	return key
+}
+
+func offlineError(repo models.Repository, resource string) error {
+	return fmt.Errorf("no cached %s for %s/%s (offline mode)", resource, repo.Owner, repo.Name)
+}
+
+// uncoveredError reports that the cached items only cover part of the requested range, so the
+// items served offline may be missing some
+func uncoveredError(repo models.Repository, resource string, cachedSince time.Time) error {
+	return fmt.Errorf("%w: cached %s for %s/%s only cover the range from %s (offline mode)",
+		ErrIncomplete, resource, repo.Owner, repo.Name, cachedSince.Format("2006-01-02"))
+}
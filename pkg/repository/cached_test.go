@@ -0,0 +1,262 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/cache"
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubFetcher serves fixed data and records how it was called
+type stubFetcher struct {
+	commits      []models.Commit
+	prs          []models.PullRequest
+	updatedPRs   []models.PullRequest
+	prErr        error
+	commitSince  []time.Time
+	prCalls      int
+	updatedCalls int
+	statsCalls   int
+	compareCalls int
+}
+
+func (s *stubFetcher) Commits(repo models.Repository, since time.Time, detailedStats bool) ([]models.Commit, error) {
+	s.commitSince = append(s.commitSince, since)
+	return s.commits, nil
+}
+
+func (s *stubFetcher) PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	s.prCalls++
+	return s.prs, s.prErr
+}
+
+func (s *stubFetcher) CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	s.compareCalls++
+	return s.commits, nil
+}
+
+func (s *stubFetcher) Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	return nil, nil
+}
+
+func (s *stubFetcher) Comments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	return []models.Comment{{Author: "alice", Body: "hi"}}, nil
+}
+
+func (s *stubFetcher) CommitStats(repo models.Repository, sha string) (int, int, error) {
+	s.statsCalls++
+	return 10, 2, nil
+}
+
+func (s *stubFetcher) Reviews(repo models.Repository, number int) ([]models.Review, error) {
+	return []models.Review{{Reviewer: "bob", State: models.ReviewApproved}}, nil
+}
+
+func (s *stubFetcher) ReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error) {
+	return nil, nil
+}
+
+func (s *stubFetcher) ReviewComments(repo models.Repository, number int) ([]models.Comment, error) {
+	return nil, nil
+}
+
+func (s *stubFetcher) Releases(repo models.Repository) ([]models.Release, error) {
+	return nil, nil
+}
+
+func (s *stubFetcher) Tags(repo models.Repository) ([]models.Tag, error) {
+	return nil, nil
+}
+
+func (s *stubFetcher) Timeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error) {
+	return nil, nil
+}
+
+func (s *stubFetcher) WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	return nil, nil
+}
+
+func (s *stubFetcher) Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	return nil, nil
+}
+
+func (s *stubFetcher) PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error) {
+	s.updatedCalls++
+	return s.updatedPRs, nil
+}
+
+func (s *stubFetcher) IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error) {
+	return nil, nil
+}
+
+func TestCachedFetcher_CommitsRefetchedWithCachedStats(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+	repo := models.Repository{Owner: "o", Name: "r"}
+	since := time.Now().AddDate(0, 0, -30)
+
+	inner := &stubFetcher{commits: []models.Commit{{SHA: "a", Date: time.Now().AddDate(0, 0, -2)}}}
+	fetcher := repository.NewCachedFetcher(inner, store, repository.CacheMode{})
+
+	commits, err := fetcher.Commits(repo, since, true)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.Equal(t, 10, commits[0].Additions)
+	assert.Equal(t, 1, inner.statsCalls)
+
+	// Second run refetches the whole range, picking up a commit authored before the last sync but
+	// pushed after it, and reuses the stats of known SHAs
+	inner.commits = []models.Commit{{SHA: "a", Date: time.Now().AddDate(0, 0, -2)}, {SHA: "b", Date: time.Now().AddDate(0, 0, -5)}}
+	commits, err = fetcher.Commits(repo, since, true)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, 2, inner.statsCalls)
+	assert.Equal(t, since, inner.commitSince[1])
+}
+
+func TestCachedFetcher_PullRequestsIncremental(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+	repo := models.Repository{Owner: "o", Name: "r"}
+	since := time.Now().AddDate(0, 0, -30)
+	created := time.Now().AddDate(0, 0, -5)
+	merged := time.Now()
+
+	inner := &stubFetcher{prs: []models.PullRequest{
+		{Number: 1, State: "open", CreatedAt: created},
+		{Number: 2, State: "open", CreatedAt: created},
+	}}
+	fetcher := repository.NewCachedFetcher(inner, store, repository.CacheMode{})
+
+	_, err := fetcher.PullRequests(repo, since, time.Time{})
+	assert.NoError(t, err)
+
+	inner.updatedPRs = []models.PullRequest{{Number: 2, State: "closed", CreatedAt: created, MergedAt: &merged}}
+	prs, err := fetcher.PullRequests(repo, since, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.prCalls)
+	assert.Equal(t, 1, inner.updatedCalls)
+	assert.Len(t, prs, 2)
+	assert.Equal(t, "closed", prs[1].State)
+
+	// A request outside the cached range falls back to a full fetch
+	_, err = fetcher.PullRequests(repo, since.AddDate(-1, 0, 0), time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.prCalls)
+}
+
+func TestCachedFetcher_IncompleteListNotCached(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+	repo := models.Repository{Owner: "o", Name: "r"}
+	since := time.Now().AddDate(0, 0, -30)
+
+	inner := &stubFetcher{
+		prs:   []models.PullRequest{{Number: 1, State: "open", CreatedAt: time.Now().AddDate(0, 0, -5)}},
+		prErr: fmt.Errorf("%w: could not fetch diffs", repository.ErrIncomplete),
+	}
+	fetcher := repository.NewCachedFetcher(inner, store, repository.CacheMode{})
+
+	prs, err := fetcher.PullRequests(repo, since, time.Time{})
+	assert.ErrorIs(t, err, repository.ErrIncomplete)
+	assert.Len(t, prs, 1)
+
+	// The partial list was not saved, so it is fetched in full again
+	inner.prErr = nil
+	_, err = fetcher.PullRequests(repo, since, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.prCalls)
+	assert.Zero(t, inner.updatedCalls)
+}
+
+func TestCachedFetcher_Offline(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+	repo := models.Repository{Owner: "o", Name: "r"}
+	inner := &stubFetcher{prs: []models.PullRequest{{Number: 1, CreatedAt: time.Now()}}}
+
+	offline := repository.NewCachedFetcher(inner, store, repository.CacheMode{Offline: true})
+	_, err := offline.PullRequests(repo, time.Now().AddDate(0, 0, -7), time.Time{})
+	assert.Error(t, err)
+
+	online := repository.NewCachedFetcher(inner, store, repository.CacheMode{})
+	_, err = online.Comments(repo, models.KindIssue, 1)
+	assert.NoError(t, err)
+	_, err = online.Reviews(repo, 1)
+	assert.NoError(t, err)
+	_, err = online.PullRequests(repo, time.Now().AddDate(0, 0, -7), time.Time{})
+	assert.NoError(t, err)
+
+	prs, err := offline.PullRequests(repo, time.Now().AddDate(0, 0, -7), time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, prs, 1)
+	comments, err := offline.Comments(repo, models.KindIssue, 1)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	reviews, err := offline.Reviews(repo, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Review{{Reviewer: "bob", State: models.ReviewApproved}}, reviews)
+	_, err = offline.ReviewRequests(repo, 1)
+	assert.Error(t, err)
+	assert.Equal(t, 1, inner.prCalls)
+}
+
+func TestCachedFetcher_CompareCommitsCached(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+	repo := models.Repository{Owner: "o", Name: "r"}
+	inner := &stubFetcher{commits: []models.Commit{{SHA: "bbb"}}}
+
+	online := repository.NewCachedFetcher(inner, store, repository.CacheMode{})
+	_, err := online.CompareCommits(repo, "aaa", "bbb")
+	assert.NoError(t, err)
+	_, err = online.CompareCommits(repo, "aaa", "bbb")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.compareCalls)
+
+	offline := repository.NewCachedFetcher(inner, store, repository.CacheMode{Offline: true})
+	commits, err := offline.CompareCommits(repo, "aaa", "bbb")
+	assert.NoError(t, err)
+	assert.Equal(t, "bbb", commits[0].SHA)
+	_, err = offline.CompareCommits(repo, "bbb", "ccc")
+	assert.Error(t, err)
+}
+
+func TestCachedFetcher_Refresh(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+	repo := models.Repository{Owner: "o", Name: "r"}
+	inner := &stubFetcher{}
+
+	fetcher := repository.NewCachedFetcher(inner, store, repository.CacheMode{Refresh: true})
+	_, _ = fetcher.PullRequests(repo, time.Now().AddDate(0, 0, -7), time.Time{})
+	_, _ = fetcher.PullRequests(repo, time.Now().AddDate(0, 0, -7), time.Time{})
+	assert.Equal(t, 2, inner.prCalls)
+	assert.Equal(t, 0, inner.updatedCalls)
+}
+
+func TestCachedFetcher_OfflineOutsideCachedRange(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+	repo := models.Repository{Owner: "o", Name: "r"}
+	inner := &stubFetcher{
+		commits: []models.Commit{{SHA: "a", Date: time.Now()}},
+		prs:     []models.PullRequest{{Number: 1, CreatedAt: time.Now()}},
+	}
+
+	online := repository.NewCachedFetcher(inner, store, repository.CacheMode{})
+	_, err := online.Commits(repo, time.Now().AddDate(0, 0, -7), false)
+	assert.NoError(t, err)
+	_, err = online.PullRequests(repo, time.Now().AddDate(0, 0, -7), time.Time{})
+	assert.NoError(t, err)
+
+	// A longer period than the cached one is served, but marked incomplete
+	offline := repository.NewCachedFetcher(inner, store, repository.CacheMode{Offline: true})
+	commits, err := offline.Commits(repo, time.Now().AddDate(0, 0, -30), false)
+	assert.ErrorIs(t, err, repository.ErrIncomplete)
+	assert.Len(t, commits, 1)
+	prs, err := offline.PullRequests(repo, time.Now().AddDate(0, 0, -30), time.Time{})
+	assert.ErrorIs(t, err, repository.ErrIncomplete)
+	assert.Len(t, prs, 1)
+
+	_, err = offline.Commits(repo, time.Now().AddDate(0, 0, -7), false)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// decodePages streams items out of one or more consecutive JSON arrays, as emitted by
+// `gh api --paginate` (one array per page, back to back). Each item is decoded into T
+// and handed to onItem without buffering the whole response. onPage, if non-nil, is
+// called after every page with the 1-based page number and the number of items on it.
+func decodePages[T any](r io.Reader, onItem func(T) error, onPage func(page, items int)) error {
+	dec := json.NewDecoder(r)
+	for page := 1; ; page++ {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return fmt.Errorf("page %d: expected JSON array, got %v", page, tok)
+		}
+
+		count := 0
+		for dec.More() {
+			var item T
+			if err := dec.Decode(&item); err != nil {
+				return fmt.Errorf("page %d, item %d: %w", page, count+1, err)
+			}
+			if err := onItem(item); err != nil {
+				return err
+			}
+			count++
+		}
+
+		// Consume the closing bracket of the page
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+
+		if onPage != nil {
+			onPage(page, count)
+		}
+	}
+}
+
+// decodeFieldPages streams the items of the array held in field of one or more consecutive JSON
+// objects, as returned by endpoints that wrap each page, e.g. {"total_count": 2, "jobs": [...]}
+func decodeFieldPages[T any](r io.Reader, field string, onItem func(T) error, onPage func(page, items int)) error {
+	dec := json.NewDecoder(r)
+	for page := 1; ; page++ {
+		var object map[string]json.RawMessage
+		err := dec.Decode(&object)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("page %d: %w", page, err)
+		}
+
+		raw, ok := object[field]
+		if !ok {
+			return fmt.Errorf("page %d: expected JSON object with %q", page, field)
+		}
+		var items []T
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return fmt.Errorf("page %d: %w", page, err)
+		}
+		for _, item := range items {
+			if err := onItem(item); err != nil {
+				return err
+			}
+		}
+
+		if onPage != nil {
+			onPage(page, len(items))
+		}
+	}
+}
+
+// pageProgress reports per-page fetch progress for a resource type
+type pageProgress struct {
+	resourceType string
+	items        int
+	printed      bool
+}
+
+func newPageProgress(resourceType string) *pageProgress {
+	return &pageProgress{resourceType: resourceType}
+}
+
+// page records a fetched page. Progress is shown once a response spans several pages.
+func (p *pageProgress) page(page, items int) {
+	p.items += items
+	if page < 2 {
+		return
+	}
+	fmt.Printf("\rFetching %s: page %d (%d items)", p.resourceType, page, p.items)
+	p.printed = true
+}
+
+// done terminates the progress line if one was printed
+func (p *pageProgress) done() {
+	if p.printed {
+		fmt.Printf(" - completed\n")
+	}
+}
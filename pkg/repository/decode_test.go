@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodePages_ConsecutiveArrays(t *testing.T) {
+	input := `[{"number":1,"title":"a"},{"number":2,"title":"b"}]` + "\n" + `[{"number":3,"title":"c"}][]`
+
+	var numbers []int
+	var pages [][2]int
+	err := decodePages(strings.NewReader(input), func(item apiIssue) error {
+		numbers = append(numbers, item.Number)
+		return nil
+	}, func(page, items int) {
+		pages = append(pages, [2]int{page, items})
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, numbers)
+	assert.Equal(t, [][2]int{{1, 2}, {2, 1}, {3, 0}}, pages)
+}
+
+func TestDecodePages_UnexpectedShape(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "object instead of array", input: `{"message":"Not Found"}`},
+		{name: "string number", input: `[{"number":"1"}]`},
+		{name: "truncated page", input: `[{"number":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decodePages(strings.NewReader(tt.input), func(item apiIssue) error { return nil }, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGHFetcher_Issues_MultiplePages(t *testing.T) {
+	original := Executor
+	defer func() {
+		Executor = original
+		SetTestMode(false)
+	}()
+	SetTestMode(true)
+
+	Executor = func(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(
+			`[{"number":1,"title":"Bug","state":"open","created_at":"2024-01-01T00:00:00Z","closed_at":null,"labels":[{"name":"bug"}]}]` +
+				`[{"number":2,"title":"PR","state":"open","pull_request":{}},{"number":3,"title":"Task","state":"closed","closed_at":"2024-01-02T00:00:00Z"}]`,
+		)), nil
+	}
+
+	issues, err := NewGHFetcher().Issues(models.Repository{Owner: "o", Name: "r"}, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 2)
+	assert.Equal(t, []string{"bug"}, issues[0].Labels)
+	assert.Nil(t, issues[0].ClosedAt)
+	assert.NotNil(t, issues[1].ClosedAt)
+}
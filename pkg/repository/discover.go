@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// Owner kinds accepted by Discover. OwnerAny looks the kind up first.
+const (
+	OwnerAny  = ""
+	OwnerOrg  = "org"
+	OwnerUser = "user"
+)
+
+// DiscoverFilter selects which repositories of an owner Discover returns. Zero fields match everything.
+type DiscoverFilter struct {
+	// Name is a glob matched against the repository name, e.g. "service-*"
+	Name string
+	// Topic must be one of the repository topics
+	Topic string
+	// Language is the primary language, compared case-insensitively
+	Language string
+	// Visibility is public, private or internal
+	Visibility string
+	// ExcludeArchived skips archived repositories
+	ExcludeArchived bool
+	// ExcludeForks skips forks
+	ExcludeForks bool
+	// PushedSince skips repositories without pushes on or after this time
+	PushedSince time.Time
+}
+
+// RepositoryInfo describes a repository listed during discovery
+type RepositoryInfo struct {
+	Repository models.Repository
+	Topics     []string
+	Language   string
+	Visibility string
+	Archived   bool
+	Fork       bool
+	PushedAt   time.Time
+}
+
+// Lister is implemented by fetchers that can list the repositories of an organization or user.
+// owner is a Repository without a Name; kind is OwnerOrg, OwnerUser or OwnerAny.
+type Lister interface {
+	ListRepositories(owner models.Repository, kind string) ([]RepositoryInfo, error)
+}
+
+// Discover lists the repositories of owner through DefaultFetcher and returns those
+// matching filter, sorted by name
+func Discover(owner models.Repository, kind string, filter DiscoverFilter) ([]models.Repository, error) {
+	if filter.Name != "" {
+		if _, err := path.Match(filter.Name, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %w", filter.Name, err)
+		}
+	}
+
+	infos, err := listRepositories(DefaultFetcher, owner, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []models.Repository
+	for _, info := range infos {
+		if filter.Matches(info) {
+			repos = append(repos, info.Repository)
+		}
+	}
+	sort.SliceStable(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+
+	fmt.Printf("Discovered %d of %d repositories for %s\n", len(repos), len(infos), owner.Owner)
+	return repos, nil
+}
+
+// Matches reports whether a listed repository passes the filter
+func (f DiscoverFilter) Matches(info RepositoryInfo) bool {
+	if f.Name != "" {
+		if ok, _ := path.Match(f.Name, info.Repository.Name); !ok {
+			return false
+		}
+	}
+	if f.Topic != "" && !slices.Contains(info.Topics, strings.ToLower(f.Topic)) {
+		return false
+	}
+	if f.Language != "" && !strings.EqualFold(f.Language, info.Language) {
+		return false
+	}
+	if f.Visibility != "" && !strings.EqualFold(f.Visibility, info.Visibility) {
+		return false
+	}
+	if (f.ExcludeArchived && info.Archived) || (f.ExcludeForks && info.Fork) {
+		return false
+	}
+	if !f.PushedSince.IsZero() && info.PushedAt.Before(f.PushedSince) {
+		return false
+	}
+	return true
+}
+
+// IsPattern reports whether a repository name contains glob characters
+func IsPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// listRepositories lists through f, or fails when f cannot discover repositories
+func listRepositories(f Fetcher, owner models.Repository, kind string) ([]RepositoryInfo, error) {
+	lister, ok := f.(Lister)
+	if !ok {
+		return nil, fmt.Errorf("repository discovery is not supported for %s", owner.ProviderName())
+	}
+	return lister.ListRepositories(owner, kind)
+}
+
+// ownerReposEndpoint returns the GitHub endpoint listing the repositories of an organization or user
+func ownerReposEndpoint(login, kind string) string {
+	if kind == OwnerOrg {
+		return fmt.Sprintf("/orgs/%s/repos?type=all", login)
+	}
+	return fmt.Sprintf("/users/%s/repos?type=owner", login)
+}
+
+// ownerKind maps the type of a GitHub account onto OwnerOrg or OwnerUser
+func ownerKind(accountType string) string {
+	if accountType == "Organization" {
+		return OwnerOrg
+	}
+	return OwnerUser
+}
+
+type apiAccount struct {
+	Type string `json:"type"`
+}
+
+type apiRepository struct {
+	Name       string    `json:"name"`
+	Owner      apiUser   `json:"owner"`
+	Topics     []string  `json:"topics"`
+	Language   string    `json:"language"`
+	Visibility string    `json:"visibility"`
+	Private    bool      `json:"private"`
+	Archived   bool      `json:"archived"`
+	Fork       bool      `json:"fork"`
+	PushedAt   timestamp `json:"pushed_at"`
+}
+
+func (r apiRepository) toInfo(owner models.Repository) RepositoryInfo {
+	visibility := r.Visibility
+	if visibility == "" {
+		// Older GitHub Enterprise Server versions only report private
+		visibility = "public"
+		if r.Private {
+			visibility = "private"
+		}
+	}
+
+	repo := owner
+	repo.Owner = r.Owner.Login
+	repo.Name = r.Name
+	return RepositoryInfo{
+		Repository: repo,
+		Topics:     r.Topics,
+		Language:   r.Language,
+		Visibility: visibility,
+		Archived:   r.Archived,
+		Fork:       r.Fork,
+		PushedAt:   r.PushedAt.Time,
+	}
+}
+
+// ListRepositories lists the repositories of an organization or user through the REST API
+func (f *APIFetcher) ListRepositories(owner models.Repository, kind string) ([]RepositoryInfo, error) {
+	if kind == OwnerAny {
+		var account apiAccount
+		if err := f.client.Get("/users/"+owner.Owner, &account); err != nil {
+			return nil, fmt.Errorf("could not look up %s: %w", owner.Owner, err)
+		}
+		kind = ownerKind(account.Type)
+	}
+
+	var infos []RepositoryInfo
+	err := listAll(f.client, ownerReposEndpoint(owner.Owner, kind), "repositories", func(raw apiRepository) {
+		infos = append(infos, raw.toInfo(owner))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list repositories of %s: %w", owner.Owner, err)
+	}
+	return infos, nil
+}
+
+// ListRepositories lists the repositories of an organization or user using GitHub CLI
+func (f *GHFetcher) ListRepositories(owner models.Repository, kind string) ([]RepositoryInfo, error) {
+	if kind == OwnerAny {
+		output, err := ghOutput(append([]string{"api", "/users/" + owner.Owner}, ghHostArgs(owner)...)...)
+		if err != nil {
+			return nil, fmt.Errorf("could not look up %s: %w", owner.Owner, err)
+		}
+		var account apiAccount
+		if err := json.Unmarshal(output, &account); err != nil {
+			return nil, fmt.Errorf("could not parse account %s: %w", owner.Owner, err)
+		}
+		kind = ownerKind(account.Type)
+	}
+
+	var infos []RepositoryInfo
+	err := DefaultRetryPolicy.retry(func() error {
+		infos = infos[:0]
+		return fetchPages(ownerReposEndpoint(owner.Owner, kind), owner, "repositories", func(raw apiRepository) {
+			infos = append(infos, raw.toInfo(owner))
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list repositories of %s: %w", owner.Owner, err)
+	}
+	return infos, nil
+}
+
+// ListRepositories lists repositories through the fetcher of the owner's provider and host
+func (f *ProviderFetcher) ListRepositories(owner models.Repository, kind string) ([]RepositoryInfo, error) {
+	fetcher, err := f.fetcher(owner)
+	if err != nil {
+		return nil, err
+	}
+	return listRepositories(fetcher, owner, kind)
+}
+
+// ListRepositories delegates to the wrapped fetcher; repository lists are not cached
+func (f *CachedFetcher) ListRepositories(owner models.Repository, kind string) ([]RepositoryInfo, error) {
+	if f.mode.Offline {
+		return nil, fmt.Errorf("cannot discover repositories of %s in offline mode", owner.Owner)
+	}
+	return listRepositories(f.inner, owner, kind)
+}
+
+// ListRepositories delegates to the wrapped fetcher
+func (f *GitFetcher) ListRepositories(owner models.Repository, kind string) ([]RepositoryInfo, error) {
+	return listRepositories(f.inner, owner, kind)
+}
@@ -0,0 +1,105 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/repository/repositorytest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+
+	server.Handle("/users/acme", map[string]any{"login": "acme", "type": "Organization"})
+	server.Handle("/orgs/acme/repos?type=all", []map[string]any{
+		{
+			"name":       "service-b",
+			"owner":      map[string]any{"login": "acme"},
+			"topics":     []string{"backend"},
+			"language":   "Go",
+			"visibility": "private",
+			"pushed_at":  "2024-03-01T00:00:00Z",
+		},
+		{
+			"name":       "service-a",
+			"owner":      map[string]any{"login": "acme"},
+			"language":   "Go",
+			"visibility": "public",
+			"pushed_at":  "2024-02-01T00:00:00Z",
+		},
+		{
+			"name":      "service-old",
+			"owner":     map[string]any{"login": "acme"},
+			"archived":  true,
+			"pushed_at": "2020-01-01T00:00:00Z",
+		},
+		{
+			"name":      "website",
+			"owner":     map[string]any{"login": "acme"},
+			"fork":      true,
+			"pushed_at": "2024-03-01T00:00:00Z",
+		},
+	})
+
+	original := repository.DefaultFetcher
+	defer func() { repository.DefaultFetcher = original }()
+	repository.DefaultFetcher = repository.NewAPIFetcher(repository.NewClient(server.URL, "token"))
+
+	owner := models.Repository{Owner: "acme"}
+
+	tests := []struct {
+		name   string
+		filter repository.DiscoverFilter
+		want   []string
+	}{
+		{
+			name:   "name pattern",
+			filter: repository.DiscoverFilter{Name: "service-*"},
+			want:   []string{"service-a", "service-b", "service-old"},
+		},
+		{
+			name:   "exclude archived and forks",
+			filter: repository.DiscoverFilter{ExcludeArchived: true, ExcludeForks: true},
+			want:   []string{"service-a", "service-b"},
+		},
+		{
+			name:   "topic and language",
+			filter: repository.DiscoverFilter{Topic: "Backend", Language: "go"},
+			want:   []string{"service-b"},
+		},
+		{
+			name:   "visibility",
+			filter: repository.DiscoverFilter{Visibility: "private"},
+			want:   []string{"service-b"},
+		},
+		{
+			name:   "pushed since",
+			filter: repository.DiscoverFilter{PushedSince: time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)},
+			want:   []string{"service-b", "website"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos, err := repository.Discover(owner, repository.OwnerAny, tt.filter)
+			assert.NoError(t, err)
+
+			var names []string
+			for _, repo := range repos {
+				assert.Equal(t, "acme", repo.Owner)
+				names = append(names, repo.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestDiscover_InvalidPattern(t *testing.T) {
+	_, err := repository.Discover(models.Repository{Owner: "acme"}, repository.OwnerOrg, repository.DiscoverFilter{Name: "["})
+	assert.Error(t, err)
+}
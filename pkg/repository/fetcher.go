@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/cache"
+	"yokiyoki/pkg/config"
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/parallel"
+)
+
+// Fetcher retrieves repository data from a code hosting service
+type Fetcher interface {
+	// Commits fetches commits authored on or after since
+	Commits(repo models.Repository, since time.Time, detailedStats bool) ([]models.Commit, error)
+	// PullRequests fetches pull requests created between since and until.
+	// A zero since fetches all of them; a zero until means now.
+	PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error)
+	// Issues fetches issues created between since and until, excluding pull requests.
+	// A zero since fetches all of them; a zero until means now.
+	Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error)
+	// Comments fetches the conversation comments of a pull request or issue.
+	// kind is models.KindPullRequest or models.KindIssue.
+	Comments(repo models.Repository, kind string, number int) ([]models.Comment, error)
+	// CommitStats fetches the lines added and deleted by a single commit
+	CommitStats(repo models.Repository, sha string) (int, int, error)
+	// CompareCommits fetches the commits reachable from head but not from base, without line statistics
+	CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error)
+	// Reviews fetches the submitted reviews of a pull request
+	Reviews(repo models.Repository, number int) ([]models.Review, error)
+	// ReviewRequests fetches the requests for reviewers made on a pull request
+	ReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error)
+	// ReviewComments fetches the inline comments on the diff of a pull request
+	ReviewComments(repo models.Repository, number int) ([]models.Comment, error)
+	// Timeline fetches the timeline events of a pull request or issue, oldest first.
+	// kind is models.KindPullRequest or models.KindIssue.
+	Timeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error)
+	// Deployments fetches deployments created between since and until together with their statuses.
+	// A zero since fetches all of them; a zero until means now.
+	Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error)
+	// Releases fetches all releases, including drafts
+	Releases(repo models.Repository) ([]models.Release, error)
+	// Tags fetches all tags
+	Tags(repo models.Repository) ([]models.Tag, error)
+	// WorkflowRuns fetches CI workflow runs created between since and until together with their jobs.
+	// A zero since fetches all of them; a zero until means now.
+	WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error)
+}
+
+// updatedFetcher is implemented by fetchers that can list items updated since a
+// point in time, which lets CachedFetcher sync pull requests and issues incrementally
+type updatedFetcher interface {
+	PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error)
+	IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error)
+}
+
+// Concurrency is the maximum number of requests in flight at once, across all repositories.
+// It must be set before the first request.
+var Concurrency = 4
+
+// DefaultFetcher is the fetcher used by GetCommits, GetPullRequests, GetIssues and GetComments
+var DefaultFetcher Fetcher = NewGHFetcher()
+
+// Backend names accepted by NewFetcher
+const (
+	BackendGH  = "gh"
+	BackendAPI = "api"
+)
+
+// NewFetcher creates a fetcher that reads GitHub repositories through the given backend
+// and repositories on other providers through their REST APIs, for any host.
+// When store is non-nil, REST clients revalidate responses with ETags kept in it.
+// detailedStats also fetches line counts that take a request per item, such as the diffs
+// of GitLab merge requests.
+func NewFetcher(backend string, cfg *config.Config, store *cache.Store, detailedStats bool) (Fetcher, error) {
+	if backend != "" && backend != BackendGH && backend != BackendAPI {
+		return nil, fmt.Errorf("unknown backend %q (expected %s or %s)", backend, BackendGH, BackendAPI)
+	}
+
+	providerClient := func(name, host, defaultHost string, baseURL func(string) string) *Client {
+		p := cfg.ProviderFor(name, host, defaultHost)
+		client := NewClient(baseURL(p.Host), p.Token)
+		client.Cache = store
+		return client
+	}
+
+	return NewProviderFetcher(func(provider, host string) (Fetcher, error) {
+		switch provider {
+		case models.ProviderGitHub:
+			if backend != BackendAPI {
+				// gh selects the host from the repository of each request
+				return NewGHFetcher(), nil
+			}
+			if host == "" {
+				host = DefaultHost
+			}
+			client := NewClient(GitHubBaseURL(host), cfg.Token(host))
+			client.Cache = store
+			return NewAPIFetcher(client), nil
+		case models.ProviderGitLab:
+			return NewGitLabFetcher(providerClient(provider, host, DefaultGitLabHost, GitLabBaseURL), detailedStats), nil
+		case models.ProviderGitea:
+			return NewGiteaFetcher(providerClient(provider, host, DefaultGiteaHost, GiteaBaseURL)), nil
+		case models.ProviderForgejo:
+			return NewGiteaFetcher(providerClient(provider, host, DefaultForgejoHost, GiteaBaseURL)), nil
+		default:
+			return nil, fmt.Errorf("unsupported provider %q", provider)
+		}
+	}), nil
+}
+
+// GetPullRequests fetches pull requests for the given repository.
+// If since is zero time, fetches all pull requests. Otherwise filters by creation date up to until.
+func GetPullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	return DefaultFetcher.PullRequests(repo, since, until)
+}
+
+// GetPullRequestsMerged fetches the pull requests of the given repository merged between since and until.
+// Merging updates a pull request, so they are looked up among those updated since since where the
+// fetcher can list them, and among all pull requests otherwise.
+func GetPullRequestsMerged(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	var prs []models.PullRequest
+	err := errNoIncrementalSync
+	if u, ok := DefaultFetcher.(updatedFetcher); ok {
+		prs, err = u.PullRequestsUpdatedSince(repo, since)
+	}
+	if errors.Is(err, errNoIncrementalSync) {
+		prs, err = DefaultFetcher.PullRequests(repo, time.Time{}, until)
+	}
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, err
+	}
+
+	var merged []models.PullRequest
+	for _, pr := range prs {
+		if pr.MergedAt != nil && inDateRange(*pr.MergedAt, since, until) {
+			merged = append(merged, pr)
+		}
+	}
+	return merged, err
+}
+
+// GetCommits fetches all commits for the given repository since the period start date.
+// When some line statistics could not be fetched, the commits are returned with an error wrapping ErrIncomplete.
+func GetCommits(repo models.Repository, from time.Time, detailedStats bool) ([]models.Commit, error) {
+	return DefaultFetcher.Commits(repo, from, detailedStats)
+}
+
+// GetIssues fetches issues for the given repository.
+// If since is zero time, fetches all issues. Otherwise filters by creation date up to until.
+func GetIssues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	return DefaultFetcher.Issues(repo, since, until)
+}
+
+// GetComments fetches the issue-level comments (general conversation thread) for the
+// given PR or issue number. kind is models.KindPullRequest or models.KindIssue.
+func GetComments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	return DefaultFetcher.Comments(repo, kind, number)
+}
+
+// GetReviews fetches the submitted reviews of the given pull request
+func GetReviews(repo models.Repository, number int) ([]models.Review, error) {
+	return DefaultFetcher.Reviews(repo, number)
+}
+
+// GetTimeline fetches the timeline events of the given pull request or issue
+func GetTimeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error) {
+	return DefaultFetcher.Timeline(repo, kind, number)
+}
+
+// GetReviewRequests fetches the review requests made on the given pull request
+func GetReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error) {
+	return DefaultFetcher.ReviewRequests(repo, number)
+}
+
+// GetReviewComments fetches the inline review comments of the given pull request
+func GetReviewComments(repo models.Repository, number int) ([]models.Comment, error) {
+	return DefaultFetcher.ReviewComments(repo, number)
+}
+
+// CompareCommits fetches the commits of the given repository reachable from head but not from base
+func CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	return DefaultFetcher.CompareCommits(repo, base, head)
+}
+
+// GetDeployments fetches the deployments of the given repository created between since and until,
+// with their statuses
+func GetDeployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	return DefaultFetcher.Deployments(repo, since, until)
+}
+
+// GetReleases fetches the releases of the given repository
+func GetReleases(repo models.Repository) ([]models.Release, error) {
+	return DefaultFetcher.Releases(repo)
+}
+
+// GetTags fetches the tags of the given repository
+func GetTags(repo models.Repository) ([]models.Tag, error) {
+	return DefaultFetcher.Tags(repo)
+}
+
+// GetWorkflowRuns fetches the CI workflow runs of the given repository created between since and until,
+// with their jobs
+func GetWorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	return DefaultFetcher.WorkflowRuns(repo, since, until)
+}
+
+// applyDeploymentStatuses fetches the statuses of each deployment, up to Concurrency at a time,
+// and sets the state of each deployment to its latest status
+func applyDeploymentStatuses(repo models.Repository, deployments []models.Deployment, statuses func(id int) ([]apiDeploymentStatus, error)) error {
+	type result struct {
+		statuses []apiDeploymentStatus
+		err      error
+	}
+	results := parallel.Map(deployments, Concurrency, func(d models.Deployment) result {
+		s, err := statuses(d.ID)
+		return result{statuses: s, err: err}
+	})
+
+	for i, r := range results {
+		if r.err != nil {
+			return fmt.Errorf("could not fetch statuses of deployment %d in %s/%s: %w", deployments[i].ID, repo.Owner, repo.Name, r.err)
+		}
+		for _, s := range r.statuses {
+			deployments[i].Statuses = append(deployments[i].Statuses, models.DeploymentStatus{State: s.State, CreatedAt: s.CreatedAt.Time})
+		}
+		sortStatuses(&deployments[i])
+	}
+	return nil
+}
+
+// sortStatuses orders the statuses of a deployment oldest first and records the latest state
+func sortStatuses(d *models.Deployment) {
+	sort.SliceStable(d.Statuses, func(i, j int) bool { return d.Statuses[i].CreatedAt.Before(d.Statuses[j].CreatedAt) })
+	if len(d.Statuses) > 0 {
+		d.State = d.Statuses[len(d.Statuses)-1].State
+	}
+}
+
+// applyWorkflowJobs fills in the jobs of each workflow run, fetching up to Concurrency runs at a time
+func applyWorkflowJobs(repo models.Repository, runs []models.WorkflowRun, jobs func(id int64) ([]apiWorkflowJob, error)) error {
+	type result struct {
+		jobs []apiWorkflowJob
+		err  error
+	}
+	results := parallel.Map(runs, Concurrency, func(r models.WorkflowRun) result {
+		j, err := jobs(r.ID)
+		return result{jobs: j, err: err}
+	})
+
+	for i, r := range results {
+		if r.err != nil {
+			return fmt.Errorf("could not fetch jobs of workflow run %d in %s/%s: %w", runs[i].ID, repo.Owner, repo.Name, r.err)
+		}
+		for _, job := range r.jobs {
+			runs[i].Jobs = append(runs[i].Jobs, job.toModel())
+		}
+	}
+	return nil
+}
+
+// workflowRuns lists the workflow runs created between since and until through list, which is
+// given the query filtering the runs. GitHub caps filtered listings like searches, so date
+// windows reaching the cap are bisected.
+func workflowRuns(since, until time.Time, list func(query string) ([]apiWorkflowRun, error)) ([]apiWorkflowRun, error) {
+	if since.IsZero() {
+		return list("")
+	}
+	return searchByWindow(newDateWindow(since, until), searchCap, func(query string) ([]apiWorkflowRun, error) {
+		// The created parameter takes the same range as the created: search qualifier
+		return list("?created=" + url.QueryEscape(strings.TrimPrefix(query, "created:")))
+	}, func(r apiWorkflowRun) int { return int(r.ID) })
+}
+
+// resolveCommitAuthors sets the author of commits whose git author was not matched to an account,
+// and the login of co-authors. The login is taken from another commit with the same email, or from
+// a noreply address such as 12345+login@users.noreply.github.com, falling back to the git author name.
+func resolveCommitAuthors(commits []models.Commit) {
+	logins := make(map[string]string)
+	for _, c := range commits {
+		if c.Author != "" && c.AuthorEmail != "" {
+			logins[strings.ToLower(c.AuthorEmail)] = c.Author
+		}
+	}
+	resolve := func(email string) (string, bool) {
+		if login, ok := logins[strings.ToLower(email)]; ok {
+			return login, true
+		}
+		return noreplyLogin(email)
+	}
+
+	for i, c := range commits {
+		if c.Author == "" {
+			if login, ok := resolve(c.AuthorEmail); ok {
+				commits[i].Author = login
+			} else {
+				commits[i].Author = c.AuthorName
+			}
+		}
+		for j, co := range c.CoAuthors {
+			if login, ok := resolve(co.Email); ok {
+				commits[i].CoAuthors[j].Login = login
+			}
+		}
+	}
+}
+
+// noreplyLogin returns the login of a GitHub (id+login@) or GitLab (id-login@) noreply email address
+func noreplyLogin(email string) (string, bool) {
+	local, domain, ok := strings.Cut(strings.ToLower(email), "@")
+	if !ok || !strings.HasPrefix(domain, "users.noreply.") {
+		return "", false
+	}
+	if _, login, ok := strings.Cut(local, "+"); ok {
+		local = login
+	} else if id, login, ok := strings.Cut(local, "-"); ok && isDigits(id) {
+		local = login
+	}
+	return local, local != ""
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// applyCommitStats fills in the line statistics of each commit, fetching up to Concurrency at a time.
+// It returns an error wrapping ErrIncomplete when some statistics could not be fetched.
+func applyCommitStats(f Fetcher, repo models.Repository, commits []models.Commit) error {
+	type result struct {
+		additions, deletions int
+		err                  error
+	}
+	results := parallel.Map(commits, Concurrency, func(c models.Commit) result {
+		additions, deletions, err := f.CommitStats(repo, c.SHA)
+		return result{additions: additions, deletions: deletions, err: err}
+	})
+
+	var failed []error
+	for i, r := range results {
+		if r.err != nil {
+			failed = append(failed, r.err)
+			continue
+		}
+		commits[i].Additions, commits[i].Deletions = r.additions, r.deletions
+	}
+	return statsError(repo, failed, len(commits))
+}
+
+// statsError summarizes failed commit stat lookups, or returns nil when none failed
+func statsError(repo models.Repository, failed []error, total int) error {
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: could not fetch line stats for %d of %d commits in %s/%s (first error: %v)",
+		ErrIncomplete, len(failed), total, repo.Owner, repo.Name, failed[0])
+}
@@ -1,364 +1,549 @@
 package repository
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"yokiyoki/pkg/models"
 )
 
-// Executor is the function used for executing commands (can be overridden for testing)
+// Executor is the function used for executing commands (can be overridden for testing).
+// It returns the raw response stream, which may hold several concatenated page arrays.
 var Executor executeFunc = execute
 
 // executeFunc defines the function signature for executing commands
-type executeFunc func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error)
+type executeFunc func(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error)
 
-// GetPullRequests fetches pull requests for the given repository using GitHub CLI
-// If since is zero time, fetches all pull requests. Otherwise filters by creation date.
-func GetPullRequests(repo models.Repository, since time.Time) []models.PullRequest {
+// GHFetcher fetches repository data by shelling out to the GitHub CLI
+type GHFetcher struct{}
+
+// NewGHFetcher creates a new GHFetcher
+func NewGHFetcher() *GHFetcher {
+	return &GHFetcher{}
+}
+
+// PullRequests fetches pull requests for the given repository using GitHub CLI
+// If since is zero time, fetches all pull requests. Otherwise filters by creation date up to until.
+func (f *GHFetcher) PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
 	// Use search-based filtering for date ranges (except in test environment)
 	if !since.IsZero() && !isTestEnvironment() {
-		return getPullRequestsWithSearch(repo, since)
+		return getPullRequestsWithSearch(repo, since, until)
 	}
 
 	endpoint := fmt.Sprintf("/repos/%s/%s/pulls?state=all", repo.Owner, repo.Name)
-	rawPRs, err := Executor(endpoint, repo, "pull requests")
-	if err != nil {
-		fmt.Printf("Warning: %v\n", err)
-		return []models.PullRequest{}
-	}
-
 	var prs []models.PullRequest
-	for _, raw := range rawPRs {
-		pr := models.PullRequest{
-			Number:    int(raw["number"].(float64)),
-			Title:     raw["title"].(string),
-			State:     raw["state"].(string),
-			URL:       raw["html_url"].(string),
-			Author:    parseUser(raw),
-			CreatedAt: parseCreatedAt(raw),
-		}
-
-		if body, ok := raw["body"].(string); ok {
-			pr.Body = body
-		}
-
-		if mergedTime := parseTimeField(raw, "merged_at"); mergedTime != nil {
-			pr.MergedAt = mergedTime
-		}
-
-		if closedTime := parseTimeField(raw, "closed_at"); closedTime != nil {
-			pr.ClosedAt = closedTime
-		}
-
-		if additions, ok := raw["additions"].(float64); ok {
-			pr.Additions = int(additions)
-		}
-		if deletions, ok := raw["deletions"].(float64); ok {
-			pr.Deletions = int(deletions)
-		}
-
-		prs = append(prs, pr)
+	err := fetchList(endpoint, repo, "pull requests", func(raw apiPullRequest) {
+		prs = append(prs, raw.toModel())
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return prs
+	return prs, nil
 }
 
-// GetCommits fetches all commits for the given repository since the period start date using GitHub CLI
-func GetCommits(repo models.Repository, from time.Time, detailedStats bool) []models.Commit {
+// Commits fetches all commits for the given repository since the period start date using GitHub CLI
+func (f *GHFetcher) Commits(repo models.Repository, from time.Time, detailedStats bool) ([]models.Commit, error) {
 	since := from.Format("2006-01-02")
 	endpoint := fmt.Sprintf("/repos/%s/%s/commits?since=%s", repo.Owner, repo.Name, since)
-	rawCommits, err := Executor(endpoint, repo, "commits")
+	var commits []models.Commit
+	err := fetchList(endpoint, repo, "commits", func(raw apiCommit) {
+		commits = append(commits, raw.toModel())
+	})
 	if err != nil {
-		fmt.Printf("Warning: %v\n", err)
-		return []models.Commit{}
+		return nil, err
 	}
+	resolveCommitAuthors(commits)
 
-	var commits []models.Commit
-	for _, raw := range rawCommits {
-		authorName, authorDate := parseCommitAuthor(raw)
-		additions, deletions := getDetailedStats(raw, repo, detailedStats)
-
-		commit := models.Commit{
-			SHA:       raw["sha"].(string),
-			Message:   raw["commit"].(map[string]any)["message"].(string),
-			URL:       raw["html_url"].(string),
-			Author:    authorName,
-			Date:      authorDate,
-			Additions: additions,
-			Deletions: deletions,
-		}
-
-		commits = append(commits, commit)
+	if detailedStats {
+		return commits, applyCommitStats(f, repo, commits)
 	}
 
-	return commits
+	return commits, nil
 }
 
-// GetIssues fetches issues for the given repository using GitHub CLI
-// If since is zero time, fetches all issues. Otherwise filters by creation date.
-func GetIssues(repo models.Repository, since time.Time) []models.Issue {
+// CompareCommits fetches the commits between base and head using GitHub CLI
+func (f *GHFetcher) CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/compare/%s...%s", repo.Owner, repo.Name, base, head)
+	var commits []models.Commit
+	err := fetchFieldList(endpoint, repo, "compared commits", "commits", func(raw apiCommit) {
+		commits = append(commits, raw.toModel())
+	})
+	if err != nil {
+		return nil, err
+	}
+	resolveCommitAuthors(commits)
+	return commits, nil
+}
+
+// Issues fetches issues for the given repository using GitHub CLI
+// If since is zero time, fetches all issues. Otherwise filters by creation date up to until.
+func (f *GHFetcher) Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
 	// Use search-based filtering for date ranges (except in test environment)
 	if !since.IsZero() && !isTestEnvironment() {
-		return getIssuesWithSearch(repo, since)
+		return getIssuesWithSearch(repo, since, until)
 	}
 
 	endpoint := fmt.Sprintf("/repos/%s/%s/issues?state=all", repo.Owner, repo.Name)
-	rawIssues, err := Executor(endpoint, repo, "issues")
+	var issues []models.Issue
+	err := fetchList(endpoint, repo, "issues", func(raw apiIssue) {
+		if raw.isPullRequest() {
+			return
+		}
+		issues = append(issues, raw.toModel())
+	})
 	if err != nil {
-		fmt.Printf("Warning: %v\n", err)
-		return []models.Issue{}
+		return nil, err
 	}
 
-	var issues []models.Issue
-	for _, raw := range rawIssues {
-		if _, exists := raw["pull_request"]; exists {
-			continue
-		}
+	return issues, nil
+}
 
-		issue := models.Issue{
-			Number:    int(raw["number"].(float64)),
-			Title:     raw["title"].(string),
-			State:     raw["state"].(string),
-			Author:    parseUser(raw),
-			CreatedAt: parseCreatedAt(raw),
-			Labels:    parseLabels(raw),
-		}
+// Comments fetches the issue-level comments for the given PR or issue number using GitHub CLI
+func (f *GHFetcher) Comments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", repo.Owner, repo.Name, number)
+	var comments []models.Comment
+	err := fetchList(endpoint, repo, "comments", func(raw apiComment) {
+		comments = append(comments, raw.toModel())
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if body, ok := raw["body"].(string); ok {
-			issue.Body = body
-		}
+	return comments, nil
+}
 
-		if url, ok := raw["html_url"].(string); ok {
-			issue.URL = url
+// Reviews fetches the submitted reviews of a pull request using GitHub CLI
+func (f *GHFetcher) Reviews(repo models.Repository, number int) ([]models.Review, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", repo.Owner, repo.Name, number)
+	var reviews []models.Review
+	err := fetchList(endpoint, repo, "reviews", func(raw apiReview) {
+		if raw.State != "PENDING" {
+			reviews = append(reviews, raw.toModel())
 		}
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if closedTime := parseTimeField(raw, "closed_at"); closedTime != nil {
-			issue.ClosedAt = closedTime
-		}
+	return reviews, nil
+}
 
-		issues = append(issues, issue)
+// Timeline fetches the timeline events of a pull request or issue using GitHub CLI
+func (f *GHFetcher) Timeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/timeline", repo.Owner, repo.Name, number)
+	var events []models.TimelineEvent
+	err := fetchList(endpoint, repo, "timeline events", func(raw apiTimelineEvent) {
+		if event, ok := raw.toModel(); ok {
+			events = append(events, event)
+		}
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return issues
+	return events, nil
 }
 
-// GetComments fetches the issue-level comments (general conversation thread) for the
-// given PR or issue number. Both PRs and plain issues share the same comments endpoint.
-func GetComments(repo models.Repository, number int) []models.Comment {
-	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", repo.Owner, repo.Name, number)
-	rawComments, err := Executor(endpoint, repo, "comments")
+// ReviewRequests fetches the review requests of a pull request from its issue events using GitHub CLI
+func (f *GHFetcher) ReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/events", repo.Owner, repo.Name, number)
+	var requests []models.ReviewRequest
+	err := fetchList(endpoint, repo, "review requests", func(raw apiIssueEvent) {
+		if request, ok := raw.reviewRequest(); ok {
+			requests = append(requests, request)
+		}
+	})
 	if err != nil {
-		fmt.Printf("Warning: %v\n", err)
-		return []models.Comment{}
+		return nil, err
 	}
 
+	return requests, nil
+}
+
+// ReviewComments fetches the inline comments on the diff of a pull request using GitHub CLI
+func (f *GHFetcher) ReviewComments(repo models.Repository, number int) ([]models.Comment, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/pulls/%d/comments", repo.Owner, repo.Name, number)
 	var comments []models.Comment
-	for _, raw := range rawComments {
-		body, _ := raw["body"].(string)
-		url, _ := raw["html_url"].(string)
-		comment := models.Comment{
-			Author:    parseUser(raw),
-			Body:      body,
-			URL:       url,
-			CreatedAt: parseCreatedAt(raw),
-		}
-		comments = append(comments, comment)
+	err := fetchList(endpoint, repo, "review comments", func(raw apiReviewComment) {
+		comments = append(comments, raw.toModel())
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return comments
+	return comments, nil
 }
 
-func execute(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
-	cmd := exec.Command("gh", "api", endpoint, "--paginate")
-	output, err := cmd.Output()
+// Deployments fetches the deployments created between since and until with their statuses using GitHub CLI
+func (f *GHFetcher) Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/deployments", repo.Owner, repo.Name)
+	var deployments []models.Deployment
+	err := fetchList(endpoint, repo, "deployments", func(raw apiDeployment) {
+		if inDateRange(raw.CreatedAt.Time, since, until) {
+			deployments = append(deployments, raw.toModel())
+		}
+	})
 	if err != nil {
-		return nil, fmt.Errorf("could not fetch %s for %s/%s: %w", resourceType, repo.Owner, repo.Name, err)
+		return nil, err
 	}
 
-	var rawData []map[string]any
-	err = json.Unmarshal(output, &rawData)
+	err = applyDeploymentStatuses(repo, deployments, func(id int) ([]apiDeploymentStatus, error) {
+		var statuses []apiDeploymentStatus
+		err := fetchList(fmt.Sprintf("%s/%d/statuses", endpoint, id), repo, "deployment statuses", func(raw apiDeploymentStatus) {
+			statuses = append(statuses, raw)
+		})
+		return statuses, err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("could not parse %s for %s/%s: %w", resourceType, repo.Owner, repo.Name, err)
+		return nil, err
 	}
 
-	fmt.Printf("Found %d %s for %s/%s\n", len(rawData), resourceType, repo.Owner, repo.Name)
-	return rawData, nil
+	return deployments, nil
 }
 
-func parseTimeField(raw map[string]any, fieldName string) *time.Time {
-	timeStr, ok := raw[fieldName].(string)
-	if !ok || timeStr == "" {
-		return nil
+// Releases fetches all releases of a repository using GitHub CLI
+func (f *GHFetcher) Releases(repo models.Repository) ([]models.Release, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/releases", repo.Owner, repo.Name)
+	var releases []models.Release
+	err := fetchList(endpoint, repo, "releases", func(raw apiRelease) {
+		releases = append(releases, raw.toModel())
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	date, err := time.Parse(time.RFC3339, timeStr)
+	return releases, nil
+}
+
+// Tags fetches all tags of a repository using GitHub CLI
+func (f *GHFetcher) Tags(repo models.Repository) ([]models.Tag, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/tags", repo.Owner, repo.Name)
+	var tags []models.Tag
+	err := fetchList(endpoint, repo, "tags", func(raw apiTag) {
+		tags = append(tags, raw.toModel())
+	})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
-	return &date
+	return tags, nil
 }
 
-func parseCreatedAt(raw map[string]any) time.Time {
-	timeStr, ok := raw["created_at"].(string)
-	if !ok {
-		return time.Time{}
+// WorkflowRuns fetches the GitHub Actions runs created between since and until with their jobs using GitHub CLI
+func (f *GHFetcher) WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/actions/runs", repo.Owner, repo.Name)
+	rawRuns, err := workflowRuns(since, until, func(query string) ([]apiWorkflowRun, error) {
+		var runs []apiWorkflowRun
+		err := fetchFieldList(endpoint+query, repo, "workflow runs", "workflow_runs", func(raw apiWorkflowRun) {
+			runs = append(runs, raw)
+		})
+		return runs, err
+	})
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, err
+	}
+
+	incomplete := err
+	var runs []models.WorkflowRun
+	for _, raw := range rawRuns {
+		if inDateRange(raw.CreatedAt.Time, since, until) {
+			runs = append(runs, raw.toModel())
+		}
 	}
 
-	date, err := time.Parse(time.RFC3339, timeStr)
+	err = applyWorkflowJobs(repo, runs, func(id int64) ([]apiWorkflowJob, error) {
+		var jobs []apiWorkflowJob
+		err := fetchFieldList(fmt.Sprintf("%s/%d/jobs", endpoint, id), repo, "workflow jobs", "jobs", func(raw apiWorkflowJob) {
+			jobs = append(jobs, raw)
+		})
+		return jobs, err
+	})
 	if err != nil {
-		return time.Time{}
+		return nil, err
 	}
 
-	return date
+	return runs, incomplete
 }
 
-func parseUser(raw map[string]any) string {
-	user, ok := raw["user"].(map[string]any)
-	if !ok {
-		return ""
-	}
+// fetchList passes every item of a paginated endpoint from the Executor to onItem.
+// Rate-limited and failed requests are retried from the page they failed on.
+func fetchList[T any](endpoint string, repo models.Repository, resourceType string, onItem func(T)) error {
+	return fetchDecodedList(endpoint, repo, resourceType, decodePages[T], onItem)
+}
 
-	login, ok := user["login"].(string)
-	if !ok {
-		return ""
+// fetchFieldList is fetchList for endpoints wrapping each page in an object holding the items in field
+func fetchFieldList[T any](endpoint string, repo models.Repository, resourceType, field string, onItem func(T)) error {
+	return fetchDecodedList(endpoint, repo, resourceType, func(r io.Reader, onItem func(T) error, onPage func(page, items int)) error {
+		return decodeFieldPages(r, field, onItem, onPage)
+	}, onItem)
+}
+
+// pageDecoder streams the items of consecutive pages read from r
+type pageDecoder[T any] func(r io.Reader, onItem func(T) error, onPage func(page, items int)) error
+
+// fetchDecodedList hands every item over to onItem as it is decoded. A request failing partway is
+// retried from the page it failed on, skipping the items of that page already handed over.
+func fetchDecodedList[T any](endpoint string, repo models.Repository, resourceType string, decode pageDecoder[T], onItem func(T)) error {
+	// pages counts the pages completed and partial the items handed over from the page after them
+	pages, partial, total := 0, 0, 0
+	err := DefaultRetryPolicy.retry(func() error {
+		skip := partial
+		return streamPages(withPage(endpoint, pages+1), repo, resourceType, decode, func(item T) {
+			if skip > 0 {
+				skip--
+				return
+			}
+			onItem(item)
+			partial++
+			total++
+		}, func(page, items int) {
+			pages++
+			partial = 0
+		})
+	})
+	if err != nil {
+		return err
 	}
 
-	return login
+	fmt.Printf("Found %d %s for %s/%s\n", total, resourceType, repo.Owner, repo.Name)
+	return nil
 }
 
-func parseLabels(raw map[string]any) []string {
-	labels, ok := raw["labels"].([]any)
-	if !ok {
-		return nil
+// withPage makes a paginated endpoint start from the given 1-based page
+func withPage(endpoint string, page int) string {
+	if page <= 1 {
+		return endpoint
+	}
+	if strings.Contains(endpoint, "?") {
+		return fmt.Sprintf("%s&page=%d", endpoint, page)
 	}
+	return fmt.Sprintf("%s?page=%d", endpoint, page)
+}
 
-	var result []string
-	for _, label := range labels {
-		labelMap, ok := label.(map[string]any)
-		if !ok {
-			continue
-		}
+// fetchPages streams every item of a paginated endpoint from the Executor into onItem
+func fetchPages[T any](endpoint string, repo models.Repository, resourceType string, onItem func(T)) error {
+	return streamPages(endpoint, repo, resourceType, decodePages[T], onItem, nil)
+}
 
-		name, ok := labelMap["name"].(string)
-		if !ok {
-			continue
+// streamPages streams the items of an endpoint into onItem, calling onPage, if non-nil, after every page
+func streamPages[T any](endpoint string, repo models.Repository, resourceType string, decode pageDecoder[T], onItem func(T), onPage func(page, items int)) error {
+	body, err := Executor(endpoint, repo, resourceType)
+	if err != nil {
+		return err
+	}
+
+	progress := newPageProgress(resourceType)
+	err = decode(body, func(item T) error {
+		onItem(item)
+		return nil
+	}, func(page, items int) {
+		progress.page(page, items)
+		if onPage != nil {
+			onPage(page, items)
 		}
+	})
+	closeErr := body.Close()
+	progress.done()
 
-		result = append(result, name)
+	if closeErr != nil {
+		return fmt.Errorf("could not fetch %s for %s/%s: %w", resourceType, repo.Owner, repo.Name, closeErr)
+	}
+	if err != nil {
+		return fmt.Errorf("could not parse %s for %s/%s: %w", resourceType, repo.Owner, repo.Name, err)
 	}
+	return nil
+}
 
-	return result
+// commandOutput is the stdout stream of a running command. Closing it waits for the command to
+// exit and releases its request slot.
+type commandOutput struct {
+	io.ReadCloser
+	cmd     *exec.Cmd
+	stderr  *bytes.Buffer
+	release func()
 }
 
-func parseCommitAuthor(raw map[string]any) (string, time.Time) {
-	commit, ok := raw["commit"].(map[string]any)
-	if !ok {
-		return "", time.Time{}
+func (o *commandOutput) Close() error {
+	// Drain any unread output so the command is not blocked on a full pipe
+	io.Copy(io.Discard, o.ReadCloser)
+	err := o.cmd.Wait()
+	o.release()
+	if err != nil {
+		return ghError(err, o.stderr.String())
 	}
+	return nil
+}
 
-	author, ok := commit["author"].(map[string]any)
-	if !ok {
-		return "", time.Time{}
+// ghHostArgs returns the --hostname flag for repositories on a GitHub Enterprise Server
+func ghHostArgs(repo models.Repository) []string {
+	if repo.Host == "" || repo.Host == DefaultHost {
+		return nil
 	}
+	return []string{"--hostname", repo.Host}
+}
 
-	var name string
-	var date time.Time
-
-	if n, ok := author["name"].(string); ok {
-		name = n
+// ghRepoArg returns the -R argument of gh commands, qualified with the host when not github.com
+func ghRepoArg(repo models.Repository) string {
+	if repo.Host == "" || repo.Host == DefaultHost {
+		return repo.FullName()
 	}
+	return repo.Host + "/" + repo.FullName()
+}
 
-	if dateStr, ok := author["date"].(string); ok {
-		if d, err := time.Parse(time.RFC3339, dateStr); err == nil {
-			date = d
+// ghOutput runs a gh command and returns its output, retrying rate-limited and failed requests
+func ghOutput(args ...string) ([]byte, error) {
+	var output []byte
+	err := DefaultRetryPolicy.retry(func() error {
+		cmd := exec.Command("gh", args...)
+		stderr := &bytes.Buffer{}
+		cmd.Stderr = stderr
+
+		release := acquireRequest()
+		var err error
+		output, err = cmd.Output()
+		release()
+		if err != nil {
+			return ghError(err, stderr.String())
 		}
-	}
-
-	return name, date
+		return nil
+	})
+	return output, err
 }
 
-func getDetailedStats(raw map[string]any, repo models.Repository, detailedStats bool) (int, int) {
-	if !detailedStats {
-		return 0, 0
+// ghError adds the stderr of a failed gh command to err, marking rate limit and
+// server errors as retryable
+func ghError(err error, stderr string) error {
+	msg := strings.TrimSpace(stderr)
+	if msg == "" {
+		return err
 	}
+	err = fmt.Errorf("%w: %s", err, msg)
 
-	sha, ok := raw["sha"].(string)
-	if !ok {
-		return 0, 0
+	switch {
+	case strings.Contains(msg, "API rate limit exceeded"):
+		return &retryableError{err: err, after: ghRateLimitWait()}
+	case isSecondaryRateLimit(msg), ghServerErrorPattern.MatchString(msg):
+		return &retryableError{err: err}
 	}
-
-	return getCommitStats(repo, sha)
+	return err
 }
 
-func getCommitStats(repo models.Repository, sha string) (int, int) {
-	cmd := exec.Command("gh", "api", fmt.Sprintf("/repos/%s/%s/commits/%s", repo.Owner, repo.Name, sha))
-	output, err := cmd.Output()
+// ghServerErrorPattern matches the HTTP status gh reports for server errors and throttling
+var ghServerErrorPattern = regexp.MustCompile(`\(HTTP (5\d\d|429)\)`)
+
+// ghRateLimitWait asks gh when the primary rate limit resets. It returns zero when unknown.
+func ghRateLimitWait() time.Duration {
+	output, err := exec.Command("gh", "api", "rate_limit", "--jq", ".rate.reset").Output()
 	if err != nil {
-		return 0, 0
+		return 0
 	}
-
-	var statsData map[string]any
-	if err := json.Unmarshal(output, &statsData); err != nil {
-		return 0, 0
+	reset, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return 0
 	}
+	return time.Until(time.Unix(reset, 0)) + time.Second
+}
 
-	stats, ok := statsData["stats"].(map[string]any)
-	if !ok {
-		return 0, 0
-	}
+func execute(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error) {
+	args := append([]string{"api", endpoint, "--paginate"}, ghHostArgs(repo)...)
+	cmd := exec.Command("gh", args...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
 
-	var additions, deletions int
-	if add, ok := stats["additions"].(float64); ok {
-		additions = int(add)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s for %s/%s: %w", resourceType, repo.Owner, repo.Name, err)
 	}
-	if del, ok := stats["deletions"].(float64); ok {
-		deletions = int(del)
+	release := acquireRequest()
+	if err := cmd.Start(); err != nil {
+		release()
+		return nil, fmt.Errorf("could not fetch %s for %s/%s: %w", resourceType, repo.Owner, repo.Name, err)
 	}
 
-	return additions, deletions
+	return &commandOutput{ReadCloser: stdout, cmd: cmd, stderr: stderr, release: release}, nil
 }
 
-func getPullRequestsWithSearch(repo models.Repository, since time.Time) []models.PullRequest {
-	searchQuery := buildDateRangeQuery(since)
-	rawPRs := fetchPRsWithGHCommand(repo, searchQuery)
-	return parsePRsFromJSON(rawPRs, repo.Owner, repo.Name)
+// CommitStats fetches the line statistics of a single commit using GitHub CLI
+func (f *GHFetcher) CommitStats(repo models.Repository, sha string) (int, int, error) {
+	args := append([]string{"api", fmt.Sprintf("/repos/%s/%s/commits/%s", repo.Owner, repo.Name, sha)}, ghHostArgs(repo)...)
+	output, err := ghOutput(args...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not fetch stats for commit %s: %w", sha, err)
+	}
+
+	var detail apiCommit
+	if err := json.Unmarshal(output, &detail); err != nil {
+		return 0, 0, fmt.Errorf("could not parse stats for commit %s: %w", sha, err)
+	}
+	if detail.Stats == nil {
+		return 0, 0, nil
+	}
+
+	return detail.Stats.Additions, detail.Stats.Deletions, nil
 }
 
-func parseUserFromJSON(raw map[string]any) string {
-	if author, ok := raw["author"].(map[string]any); ok {
-		if login, ok := author["login"].(string); ok {
-			return login
-		}
+// PullRequestsUpdatedSince fetches pull requests updated on or after the given date using GitHub CLI
+func (f *GHFetcher) PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error) {
+	// Searches are not run in the test environment
+	if isTestEnvironment() {
+		return nil, errNoIncrementalSync
+	}
+	rawPRs, err := fetchPRsWithGHCommand(repo, updatedQuery(updated))
+	if err != nil {
+		return nil, err
+	}
+	if len(rawPRs) >= searchCap {
+		return nil, errTooManyUpdates
 	}
-	return ""
+	return parsePRsFromJSON(rawPRs, repo.Owner, repo.Name), nil
 }
 
-func parseCreatedAtFromJSON(raw map[string]any) time.Time {
-	if timeStr, ok := raw["createdAt"].(string); ok {
-		if date, err := time.Parse(time.RFC3339, timeStr); err == nil {
-			return date
-		}
+// IssuesUpdatedSince fetches issues updated on or after the given date using GitHub CLI
+func (f *GHFetcher) IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error) {
+	if isTestEnvironment() {
+		return nil, errNoIncrementalSync
+	}
+	rawIssues, err := fetchIssuesWithGHCommand(repo, updatedQuery(updated))
+	if err != nil {
+		return nil, err
+	}
+	if len(rawIssues) >= searchCap {
+		return nil, errTooManyUpdates
 	}
-	return time.Time{}
+	return parseIssuesFromJSON(rawIssues, repo.Owner, repo.Name), nil
 }
 
-func parseTimeFieldFromJSON(raw map[string]any, fieldName string) *time.Time {
-	if timeStr, ok := raw[fieldName].(string); ok && timeStr != "" {
-		if date, err := time.Parse(time.RFC3339, timeStr); err == nil {
-			return &date
-		}
+func getPullRequestsWithSearch(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	rawPRs, err := searchByWindow(newDateWindow(since, until), searchCap,
+		func(searchQuery string) ([]ghListPullRequest, error) {
+			return fetchPRsWithGHCommand(repo, searchQuery)
+		},
+		func(pr ghListPullRequest) int { return pr.Number })
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, err
 	}
-	return nil
+	return parsePRsFromJSON(rawPRs, repo.Owner, repo.Name), err
 }
 
-func getIssuesWithSearch(repo models.Repository, since time.Time) []models.Issue {
-	searchQuery := buildDateRangeQuery(since)
-	rawIssues := fetchIssuesWithGHCommand(repo, searchQuery)
-	return parseIssuesFromJSON(rawIssues, repo.Owner, repo.Name)
+func getIssuesWithSearch(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	rawIssues, err := searchByWindow(newDateWindow(since, until), searchCap,
+		func(searchQuery string) ([]ghListIssue, error) {
+			return fetchIssuesWithGHCommand(repo, searchQuery)
+		},
+		func(issue ghListIssue) int { return issue.Number })
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, err
+	}
+	return parseIssuesFromJSON(rawIssues, repo.Owner, repo.Name), err
 }
 
 var isInTestMode = false
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingBody serves a response cut off partway, failing with a retryable error on close
+type failingBody struct {
+	io.Reader
+}
+
+func (b failingBody) Close() error {
+	return &retryableError{err: errors.New("HTTP 502")}
+}
+
+func TestFetchList_ResumesFromFailedPage(t *testing.T) {
+	originalExecutor, originalPolicy := Executor, DefaultRetryPolicy
+	defer func() { Executor, DefaultRetryPolicy = originalExecutor, originalPolicy }()
+	DefaultRetryPolicy = RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxWait: time.Second}
+
+	var endpoints []string
+	Executor = func(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error) {
+		endpoints = append(endpoints, endpoint)
+		if len(endpoints) == 1 {
+			// Page 1 completes, then the stream breaks after the first item of page 2
+			return failingBody{strings.NewReader(`[{"number":1},{"number":2}][{"number":3},`)}, nil
+		}
+		return io.NopCloser(strings.NewReader(`[{"number":3},{"number":4}][{"number":5}]`)), nil
+	}
+
+	var numbers []int
+	err := fetchList("/repos/o/r/issues?state=all", models.Repository{Owner: "o", Name: "r"}, "issues", func(raw apiIssue) {
+		numbers = append(numbers, raw.Number)
+		// Items are handed over while the response is still being read
+		if raw.Number == 1 {
+			assert.Len(t, endpoints, 1)
+		}
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, numbers)
+	assert.Equal(t, []string{"/repos/o/r/issues?state=all", "/repos/o/r/issues?state=all&page=2"}, endpoints)
+}
+
+func TestParseGHListResults_BotAuthors(t *testing.T) {
+	output := `[
+		{"number": 1, "author": {"id": "BOT_1", "is_bot": true, "login": "app/dependabot", "name": ""}, "createdAt": "2024-01-01T00:00:00Z"},
+		{"number": 2, "author": {"id": "U_1", "is_bot": false, "login": "alice", "name": "Alice"}, "createdAt": "2024-01-02T00:00:00Z"}
+	]`
+
+	var rawPRs []ghListPullRequest
+	assert.NoError(t, decodePages(strings.NewReader(output), func(raw ghListPullRequest) error {
+		rawPRs = append(rawPRs, raw)
+		return nil
+	}, nil))
+	prs := parsePRsFromJSON(rawPRs, "owner", "repo")
+	assert.Equal(t, []string{"dependabot[bot]", "alice"}, []string{prs[0].Author, prs[1].Author})
+
+	var rawIssues []ghListIssue
+	assert.NoError(t, decodePages(strings.NewReader(output), func(raw ghListIssue) error {
+		rawIssues = append(rawIssues, raw)
+		return nil
+	}, nil))
+	issues := parseIssuesFromJSON(rawIssues, "owner", "repo")
+	assert.Equal(t, []string{"dependabot[bot]", "alice"}, []string{issues[0].Author, issues[1].Author})
+}
@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// Commit sources accepted by --commit-source and the commit_source setting
+const (
+	CommitSourceAPI = "api"
+	CommitSourceGit = "git"
+)
+
+// Separators used in the git log format; they cannot appear in commit metadata
+const (
+	gitRecordSep = "\x1e"
+	gitFieldSep  = "\x1f"
+)
+
+// GitOptions configures which repositories GitFetcher reads from git
+type GitOptions struct {
+	// Clones maps "owner/name" (or "host/owner/name" off github.com) to a local clone.
+	// An empty path mirrors the GitHub repository into MirrorDir.
+	Clones map[string]string
+	// All reads every repository from git, mirroring those without a configured clone
+	All bool
+	// MirrorDir holds bare mirrors of repositories without a local clone
+	MirrorDir string
+	// Token returns the token authenticating mirror fetches from a host over HTTPS.
+	// When nil or empty, git's credential helpers are used.
+	Token func(host string) string
+	// Offline reads existing clones and mirrors without fetching
+	Offline bool
+}
+
+// GitFetcher reads commits and line statistics from local git clones with `git log --numstat`,
+// which avoids one API call per commit. Everything else is delegated to the wrapped fetcher.
+type GitFetcher struct {
+	inner Fetcher
+	opts  GitOptions
+
+	mu      sync.Mutex
+	synced  map[string]*sync.Once
+	syncErr map[string]error
+}
+
+// NewGitFetcher creates a GitFetcher that falls back to inner for repositories not read from git
+func NewGitFetcher(inner Fetcher, opts GitOptions) *GitFetcher {
+	return &GitFetcher{
+		inner:   inner,
+		opts:    opts,
+		synced:  make(map[string]*sync.Once),
+		syncErr: make(map[string]error),
+	}
+}
+
+// Commits reads commits authored since the given date from git, including per-file line statistics.
+// git log selects commits by committer date, so those authored earlier, e.g. rebased ones, are dropped.
+func (f *GitFetcher) Commits(repo models.Repository, since time.Time, detailedStats bool) ([]models.Commit, error) {
+	dir, rev, ok, err := f.clone(repo)
+	if !ok {
+		return f.inner.Commits(repo, since, detailedStats)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logged, err := gitLog(dir, repo, rev, "--since="+since.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	var commits []models.Commit
+	for _, c := range logged {
+		if !c.Date.Before(since) {
+			commits = append(commits, c)
+		}
+	}
+
+	fmt.Printf("Found %d commits for %s/%s (git)\n", len(commits), repo.Owner, repo.Name)
+	return commits, nil
+}
+
+// CompareCommits reads the commits between base and head from git
+func (f *GitFetcher) CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	dir, _, ok, err := f.clone(repo)
+	if !ok {
+		return f.inner.CompareCommits(repo, base, head)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return gitLog(dir, repo, base+".."+head)
+}
+
+// gitLog reads the commits selected by args, including per-file line statistics
+func gitLog(dir string, repo models.Repository, args ...string) ([]models.Commit, error) {
+	args = append([]string{"log", "--numstat", "--no-renames",
+		"--format=" + gitRecordSep + strings.Join([]string{"%H", "%an", "%ae", "%aI", "%B"}, gitFieldSep) + gitFieldSep}, args...)
+	output, err := git(dir, nil, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not read commits for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	commits, err := parseGitLog(output, repo)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse commits for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return commits, nil
+}
+
+// CommitStats reads the line statistics of a single commit from git
+func (f *GitFetcher) CommitStats(repo models.Repository, sha string) (int, int, error) {
+	dir, _, ok, err := f.clone(repo)
+	if !ok {
+		return f.inner.CommitStats(repo, sha)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	output, err := git(dir, nil, "show", "--numstat", "--no-renames", "--format=", sha)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not read stats for commit %s: %w", sha, err)
+	}
+
+	var additions, deletions int
+	for _, file := range parseNumstat(string(output)) {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+	return additions, deletions, nil
+}
+
+// PullRequests delegates to the wrapped fetcher
+func (f *GitFetcher) PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	return f.inner.PullRequests(repo, since, until)
+}
+
+// PullRequestsUpdatedSince delegates to the wrapped fetcher when it can list updated pull requests
+func (f *GitFetcher) PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error) {
+	u, ok := f.inner.(updatedFetcher)
+	if !ok {
+		return nil, errNoIncrementalSync
+	}
+	return u.PullRequestsUpdatedSince(repo, updated)
+}
+
+// IssuesUpdatedSince delegates to the wrapped fetcher when it can list updated issues
+func (f *GitFetcher) IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error) {
+	u, ok := f.inner.(updatedFetcher)
+	if !ok {
+		return nil, errNoIncrementalSync
+	}
+	return u.IssuesUpdatedSince(repo, updated)
+}
+
+// Issues delegates to the wrapped fetcher
+func (f *GitFetcher) Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	return f.inner.Issues(repo, since, until)
+}
+
+// Comments delegates to the wrapped fetcher
+func (f *GitFetcher) Comments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	return f.inner.Comments(repo, kind, number)
+}
+
+// Reviews delegates to the wrapped fetcher
+func (f *GitFetcher) Reviews(repo models.Repository, number int) ([]models.Review, error) {
+	return f.inner.Reviews(repo, number)
+}
+
+// Timeline delegates to the wrapped fetcher
+func (f *GitFetcher) Timeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error) {
+	return f.inner.Timeline(repo, kind, number)
+}
+
+// ReviewRequests delegates to the wrapped fetcher
+func (f *GitFetcher) ReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error) {
+	return f.inner.ReviewRequests(repo, number)
+}
+
+// ReviewComments delegates to the wrapped fetcher
+func (f *GitFetcher) ReviewComments(repo models.Repository, number int) ([]models.Comment, error) {
+	return f.inner.ReviewComments(repo, number)
+}
+
+// Deployments delegates to the wrapped fetcher
+func (f *GitFetcher) Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	return f.inner.Deployments(repo, since, until)
+}
+
+// Releases delegates to the wrapped fetcher
+func (f *GitFetcher) Releases(repo models.Repository) ([]models.Release, error) {
+	return f.inner.Releases(repo)
+}
+
+// Tags delegates to the wrapped fetcher
+func (f *GitFetcher) Tags(repo models.Repository) ([]models.Tag, error) {
+	return f.inner.Tags(repo)
+}
+
+// WorkflowRuns delegates to the wrapped fetcher
+func (f *GitFetcher) WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	return f.inner.WorkflowRuns(repo, since, until)
+}
+
+// clone returns the git directory and revision to read for repo, and whether repo is read from git.
+// Mirrors are created or fetched at most once per run.
+func (f *GitFetcher) clone(repo models.Repository) (dir, rev string, ok bool, err error) {
+	fullName := repo.FullName()
+	if repo.Host != "" && repo.Host != DefaultHost {
+		fullName = repo.Host + "/" + fullName
+	}
+	path, configured := f.opts.Clones[fullName]
+	if !configured && !f.opts.All {
+		return "", "", false, nil
+	}
+
+	if path != "" {
+		return path, localRevision(path), true, nil
+	}
+	if repo.ProviderName() != models.ProviderGitHub {
+		// Only GitHub repositories are mirrored; others need a local clone path
+		return "", "", false, nil
+	}
+
+	dir = filepath.Join(f.opts.MirrorDir, filepath.FromSlash(fullName)+".git")
+	f.mu.Lock()
+	once, found := f.synced[fullName]
+	if !found {
+		once = &sync.Once{}
+		f.synced[fullName] = once
+	}
+	f.mu.Unlock()
+
+	once.Do(func() {
+		err := f.syncMirror(repo, dir)
+		f.mu.Lock()
+		f.syncErr[fullName] = err
+		f.mu.Unlock()
+	})
+
+	f.mu.Lock()
+	err = f.syncErr[fullName]
+	f.mu.Unlock()
+	return dir, "HEAD", true, err
+}
+
+// syncMirror creates or updates the bare mirror of repo in dir
+func (f *GitFetcher) syncMirror(repo models.Repository, dir string) error {
+	_, statErr := os.Stat(dir)
+	exists := statErr == nil
+	if statErr != nil && !errors.Is(statErr, fs.ErrNotExist) {
+		return statErr
+	}
+
+	if f.opts.Offline {
+		if !exists {
+			return offlineError(repo, "git mirror")
+		}
+		return nil
+	}
+
+	host := repo.Host
+	if host == "" {
+		host = DefaultHost
+	}
+	var env []string
+	if f.opts.Token != nil {
+		env = gitAuthEnv(f.opts.Token(host))
+	}
+	if exists {
+		fmt.Printf("Fetching git mirror of %s/%s\n", repo.Owner, repo.Name)
+		if _, err := git(dir, env, "remote", "update", "--prune"); err != nil {
+			return fmt.Errorf("could not update git mirror of %s/%s: %w", repo.Owner, repo.Name, err)
+		}
+		return nil
+	}
+
+	fmt.Printf("Cloning git mirror of %s/%s\n", repo.Owner, repo.Name)
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+	url := fmt.Sprintf("https://%s/%s/%s.git", host, repo.Owner, repo.Name)
+	if _, err := git("", env, "clone", "--mirror", "--quiet", url, dir); err != nil {
+		return fmt.Errorf("could not clone git mirror of %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return nil
+}
+
+// localRevision prefers the remote default branch of a working clone over whatever is checked out
+func localRevision(dir string) string {
+	if _, err := git(dir, nil, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/HEAD"); err == nil {
+		return "refs/remotes/origin/HEAD"
+	}
+	return "HEAD"
+}
+
+// gitAuthEnv passes the token as an HTTP header through the environment, keeping it off the command line
+func gitAuthEnv(token string) []string {
+	if token == "" {
+		return nil
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + credentials,
+	}
+}
+
+// git runs a git command in dir and returns its standard output
+func git(dir string, env []string, args ...string) ([]byte, error) {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), env...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// parseGitLog parses records written with the gitRecordSep/gitFieldSep format followed by --numstat lines
+func parseGitLog(output []byte, repo models.Repository) ([]models.Commit, error) {
+	var commits []models.Commit
+	for _, record := range strings.Split(string(output), gitRecordSep) {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, gitFieldSep, 6)
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("unexpected git log record %q", record)
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, err
+		}
+
+		commit := models.Commit{
+			SHA:         fields[0],
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			Date:        date,
+			Message:     strings.TrimSpace(fields[4]),
+			CoAuthors:   models.ParseCoAuthors(fields[4]),
+			URL:         repo.CommitURL(fields[0]),
+			Files:       parseNumstat(fields[5]),
+		}
+		for _, file := range commit.Files {
+			commit.Additions += file.Additions
+			commit.Deletions += file.Deletions
+		}
+		commits = append(commits, commit)
+	}
+	resolveCommitAuthors(commits)
+	return commits, nil
+}
+
+// parseNumstat parses `git --numstat` lines. Binary files are listed with zero line counts.
+func parseNumstat(output string) []models.FileChange {
+	var files []models.FileChange
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		additions, _ := strconv.Atoi(parts[0])
+		deletions, _ := strconv.Atoi(parts[1])
+		files = append(files, models.FileChange{Path: parts[2], Additions: additions, Deletions: deletions})
+	}
+	return files
+}
+
+// LocalRepository resolves the repository of the git working tree at dir from its origin remote,
+// or its only remote when there is no origin
+func LocalRepository(dir string) (models.Repository, error) {
+	remote := "origin"
+	output, err := git(dir, nil, "remote")
+	if err != nil {
+		return models.Repository{}, fmt.Errorf("could not read git remotes of %s: %w", dir, err)
+	}
+	remotes := strings.Fields(string(output))
+	if len(remotes) == 1 {
+		remote = remotes[0]
+	}
+
+	output, err = git(dir, nil, "remote", "get-url", remote)
+	if err != nil {
+		return models.Repository{}, fmt.Errorf("could not read git remote %s of %s: %w", remote, dir, err)
+	}
+	return models.ParseRepository(strings.TrimSpace(string(output)))
+}
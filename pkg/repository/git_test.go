@@ -0,0 +1,140 @@
+package repository_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// initGitRepo creates a repository with one commit per file content map
+func initGitRepo(t *testing.T, commits ...map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=alice", "GIT_COMMITTER_EMAIL=alice@example.com")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	run("init", "--quiet")
+	for i, files := range commits {
+		for name, content := range files {
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+			run("add", name)
+		}
+		run("commit", "--quiet", "-m", "commit "+string(rune('A'+i)))
+	}
+	return dir
+}
+
+func TestGitFetcher_Commits(t *testing.T) {
+	dir := initGitRepo(t,
+		map[string]string{"a.txt": "1\n2\n3\n"},
+		map[string]string{"a.txt": "1\n3\n", "b.txt": "x\n"},
+	)
+	repo := models.Repository{Owner: "o", Name: "r"}
+	inner := &stubFetcher{}
+	fetcher := repository.NewGitFetcher(inner, repository.GitOptions{Clones: map[string]string{"o/r": dir}})
+
+	commits, err := fetcher.Commits(repo, time.Now().AddDate(0, 0, -1), true)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Empty(t, inner.commitSince)
+
+	latest := commits[0]
+	assert.Equal(t, "commit B", latest.Message)
+	assert.Equal(t, "alice", latest.Author)
+	assert.Equal(t, "alice@example.com", latest.AuthorEmail)
+	assert.Equal(t, "https://github.com/o/r/commit/"+latest.SHA, latest.URL)
+	assert.Equal(t, 1, latest.Additions)
+	assert.Equal(t, 1, latest.Deletions)
+	assert.ElementsMatch(t, []models.FileChange{
+		{Path: "a.txt", Additions: 0, Deletions: 1},
+		{Path: "b.txt", Additions: 1, Deletions: 0},
+	}, latest.Files)
+	assert.Equal(t, 3, commits[1].Additions)
+
+	additions, deletions, err := fetcher.CommitStats(repo, latest.SHA)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, additions)
+	assert.Equal(t, 1, deletions)
+	assert.Zero(t, inner.statsCalls)
+
+	// Commits are selected by their author date, like the commit dates reported
+	output, err := exec.Command("git", "-C", dir, "-c", "user.name=bob", "-c", "user.email=bob@example.com",
+		"commit", "--quiet", "--allow-empty", "-m", "rebased", "--date=2020-01-01T00:00:00Z").CombinedOutput()
+	assert.NoError(t, err, string(output))
+	commits, err = fetcher.Commits(repo, time.Now().AddDate(0, 0, -1), true)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "commit B", commits[0].Message)
+}
+
+func TestGitFetcher_CompareCommits(t *testing.T) {
+	dir := initGitRepo(t,
+		map[string]string{"a.txt": "1\n"},
+		map[string]string{"a.txt": "2\n"},
+		map[string]string{"a.txt": "3\n"},
+	)
+	repo := models.Repository{Owner: "o", Name: "r"}
+	fetcher := repository.NewGitFetcher(&stubFetcher{}, repository.GitOptions{Clones: map[string]string{"o/r": dir}})
+
+	commits, err := fetcher.CompareCommits(repo, "HEAD~2", "HEAD")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "commit C", commits[0].Message)
+	assert.Equal(t, "commit B", commits[1].Message)
+}
+
+func TestGitFetcher_DelegatesUnconfiguredRepositories(t *testing.T) {
+	inner := &stubFetcher{commits: []models.Commit{{SHA: "abc"}}}
+	fetcher := repository.NewGitFetcher(inner, repository.GitOptions{Clones: map[string]string{"o/other": "/nonexistent"}})
+
+	commits, err := fetcher.Commits(models.Repository{Owner: "o", Name: "r"}, time.Time{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, inner.commits, commits)
+	assert.Len(t, inner.commitSince, 1)
+}
+
+func TestGitFetcher_OfflineWithoutMirror(t *testing.T) {
+	fetcher := repository.NewGitFetcher(&stubFetcher{}, repository.GitOptions{
+		All:       true,
+		MirrorDir: t.TempDir(),
+		Offline:   true,
+	})
+
+	_, err := fetcher.Commits(models.Repository{Owner: "o", Name: "r"}, time.Time{}, false)
+	assert.ErrorContains(t, err, "offline")
+}
+
+func TestLocalRepository(t *testing.T) {
+	dir := initGitRepo(t)
+	cmd := exec.Command("git", "-C", dir, "remote", "add", "upstream", "git@github.com:kotaoue/chiken.git")
+	assert.NoError(t, cmd.Run())
+
+	repo, err := repository.LocalRepository(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Repository{Owner: "kotaoue", Name: "chiken"}, repo)
+}
+
+func TestLocalRepository_NoRemote(t *testing.T) {
+	_, err := repository.LocalRepository(initGitRepo(t))
+	assert.Error(t, err)
+}
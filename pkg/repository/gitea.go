@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// Default instances of Gitea-compatible providers
+const (
+	DefaultGiteaHost   = "gitea.com"
+	DefaultForgejoHost = "codeberg.org"
+)
+
+// giteaPageLimit is the largest page size Gitea allows by default
+const giteaPageLimit = 50
+
+// GiteaBaseURL returns the REST API endpoint of a Gitea or Forgejo instance
+func GiteaBaseURL(host string) string {
+	return "https://" + host + "/api/v1"
+}
+
+// GiteaFetcher fetches repository data from the Gitea REST API, which Forgejo also serves.
+// Its payloads mirror GitHub's, so the GitHub API types are reused.
+type GiteaFetcher struct {
+	client *Client
+}
+
+// NewGiteaFetcher creates a new GiteaFetcher using the given client
+func NewGiteaFetcher(client *Client) *GiteaFetcher {
+	return &GiteaFetcher{client: client}
+}
+
+// PullRequests fetches pull requests created between since and until.
+// Gitea cannot filter pull requests by date, so all of them are listed and filtered locally.
+func (f *GiteaFetcher) PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	endpoint := giteaRepo(repo, "pulls?state=all")
+	var prs []models.PullRequest
+	err := listAll(f.client, endpoint, "pull requests", func(raw apiPullRequest) {
+		if inDateRange(raw.CreatedAt.Time, since, until) {
+			prs = append(prs, raw.toModel())
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch pull requests for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	fmt.Printf("Found %d pull requests for %s/%s\n", len(prs), repo.Owner, repo.Name)
+	return prs, nil
+}
+
+// Commits fetches commits since the given date, with line statistics when detailedStats is set
+func (f *GiteaFetcher) Commits(repo models.Repository, since time.Time, detailedStats bool) ([]models.Commit, error) {
+	endpoint := giteaRepo(repo, fmt.Sprintf("commits?since=%s&stat=%t&files=false&verification=false",
+		url.QueryEscape(since.UTC().Format(time.RFC3339)), detailedStats))
+
+	var commits []models.Commit
+	err := listAll(f.client, endpoint, "commits", func(raw apiCommit) {
+		commit := raw.toModel()
+		if raw.Stats != nil {
+			commit.Additions = raw.Stats.Additions
+			commit.Deletions = raw.Stats.Deletions
+		}
+		commits = append(commits, commit)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch commits for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	resolveCommitAuthors(commits)
+
+	fmt.Printf("Found %d commits for %s/%s\n", len(commits), repo.Owner, repo.Name)
+	return commits, nil
+}
+
+// CommitStats fetches the line statistics of a single commit
+func (f *GiteaFetcher) CommitStats(repo models.Repository, sha string) (int, int, error) {
+	var detail apiCommit
+	if err := f.client.Get(giteaRepo(repo, "git/commits/"+sha+"?stat=true&files=false"), &detail); err != nil {
+		return 0, 0, fmt.Errorf("could not fetch stats for commit %s: %w", sha, err)
+	}
+	if detail.Stats == nil {
+		return 0, 0, nil
+	}
+	return detail.Stats.Additions, detail.Stats.Deletions, nil
+}
+
+// CompareCommits fetches the commits between base and head
+func (f *GiteaFetcher) CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	var comparison struct {
+		Commits []apiCommit `json:"commits"`
+	}
+	endpoint := fmt.Sprintf("/repos/%s/%s/compare/%s...%s", repo.Owner, repo.Name, base, head)
+	if err := f.client.Get(endpoint, &comparison); err != nil {
+		return nil, fmt.Errorf("could not compare %s...%s in %s/%s: %w", base, head, repo.Owner, repo.Name, err)
+	}
+
+	commits := make([]models.Commit, 0, len(comparison.Commits))
+	for _, raw := range comparison.Commits {
+		commits = append(commits, raw.toModel())
+	}
+	resolveCommitAuthors(commits)
+	return commits, nil
+}
+
+// Issues fetches issues created between since and until, excluding pull requests
+func (f *GiteaFetcher) Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	// since filters on the update time, which is never before the creation time
+	endpoint := giteaRepo(repo, "issues?state=all&type=issues")
+	if !since.IsZero() {
+		endpoint += "&since=" + url.QueryEscape(truncateDay(since).UTC().Format(time.RFC3339))
+	}
+
+	var issues []models.Issue
+	err := listAll(f.client, endpoint, "issues", func(raw apiIssue) {
+		if inDateRange(raw.CreatedAt.Time, since, until) {
+			issues = append(issues, raw.toModel())
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch issues for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	fmt.Printf("Found %d issues for %s/%s\n", len(issues), repo.Owner, repo.Name)
+	return issues, nil
+}
+
+// PullRequestsUpdatedSince is unsupported because Gitea cannot filter pull requests by update time
+func (f *GiteaFetcher) PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error) {
+	return nil, errNoIncrementalSync
+}
+
+// IssuesUpdatedSince fetches issues updated on or after the given time
+func (f *GiteaFetcher) IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error) {
+	endpoint := giteaRepo(repo, "issues?state=all&type=issues&since="+url.QueryEscape(updated.UTC().Format(time.RFC3339)))
+	var issues []models.Issue
+	err := listAll(f.client, endpoint, "issues", func(raw apiIssue) {
+		issues = append(issues, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch issues for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return issues, nil
+}
+
+// Comments fetches the comments on a pull request or issue, which share the issue comments endpoint
+func (f *GiteaFetcher) Comments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	endpoint := giteaRepo(repo, fmt.Sprintf("issues/%d/comments", number))
+	var comments []models.Comment
+	err := listAll(f.client, endpoint, "comments", func(raw apiComment) {
+		comments = append(comments, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch comments for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return comments, nil
+}
+
+// Reviews fetches the submitted reviews of a pull request.
+// Gitea lists requested reviewers as reviews too, so those and pending reviews are skipped.
+func (f *GiteaFetcher) Reviews(repo models.Repository, number int) ([]models.Review, error) {
+	raw, err := f.reviews(repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []models.Review
+	for _, r := range raw {
+		state, ok := giteaReviewStates[r.State]
+		if !ok {
+			continue
+		}
+		review := r.toModel()
+		review.State = state
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
+
+// ReviewRequests returns the requested reviewers, which Gitea lists among the reviews
+func (f *GiteaFetcher) ReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error) {
+	raw, err := f.reviews(repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []models.ReviewRequest
+	for _, r := range raw {
+		if r.State == "REQUEST_REVIEW" {
+			requests = append(requests, models.ReviewRequest{Reviewer: login(r.User), RequestedAt: r.SubmittedAt.Time})
+		}
+	}
+	return requests, nil
+}
+
+// ReviewComments fetches the inline comments of every review on a pull request.
+// Gitea has no endpoint listing them for the whole pull request.
+func (f *GiteaFetcher) ReviewComments(repo models.Repository, number int) ([]models.Comment, error) {
+	raw, err := f.reviews(repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []models.Comment
+	for _, r := range raw {
+		if r.State == "REQUEST_REVIEW" {
+			continue
+		}
+		var reviewComments []giteaReviewComment
+		endpoint := giteaRepo(repo, fmt.Sprintf("pulls/%d/reviews/%d/comments", number, r.ID))
+		if err := f.client.Get(endpoint, &reviewComments); err != nil {
+			return nil, fmt.Errorf("could not fetch review comments for %s/%s: %w", repo.Owner, repo.Name, err)
+		}
+		for _, c := range reviewComments {
+			comment := c.toModel()
+			// Gitea reports the line as the position in the new or old file
+			comment.Line = c.Position
+			if comment.Line == 0 {
+				comment.Line = c.OriginalPosition
+			}
+			comments = append(comments, comment)
+		}
+	}
+	return comments, nil
+}
+
+// Deployments is not supported, as Gitea has no deployments API
+func (f *GiteaFetcher) Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	return nil, fmt.Errorf("%w: %s/%s is on %s, which has no deployments API", errors.ErrUnsupported, repo.Owner, repo.Name, repo.ProviderName())
+}
+
+// Releases fetches all releases of a repository
+func (f *GiteaFetcher) Releases(repo models.Repository) ([]models.Release, error) {
+	var releases []models.Release
+	err := listAll(f.client, giteaRepo(repo, "releases"), "releases", func(raw apiRelease) {
+		releases = append(releases, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch releases for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	fmt.Printf("Found %d releases for %s/%s\n", len(releases), repo.Owner, repo.Name)
+	return releases, nil
+}
+
+// Tags fetches all tags of a repository
+func (f *GiteaFetcher) Tags(repo models.Repository) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := listAll(f.client, giteaRepo(repo, "tags"), "tags", func(raw apiTag) {
+		tags = append(tags, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch tags for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return tags, nil
+}
+
+// Timeline is not supported; timelines are read from GitHub only
+func (f *GiteaFetcher) Timeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error) {
+	return nil, fmt.Errorf("%w: %s/%s is on %s; timelines are only read from GitHub", errors.ErrUnsupported, repo.Owner, repo.Name, repo.ProviderName())
+}
+
+// WorkflowRuns is not supported; workflow runs are read from GitHub Actions only
+func (f *GiteaFetcher) WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	return nil, fmt.Errorf("%w: %s/%s is on %s; workflow runs are only read from GitHub Actions", errors.ErrUnsupported, repo.Owner, repo.Name, repo.ProviderName())
+}
+
+func (f *GiteaFetcher) reviews(repo models.Repository, number int) ([]apiReview, error) {
+	var reviews []apiReview
+	err := listAll(f.client, giteaRepo(repo, fmt.Sprintf("pulls/%d/reviews", number)), "reviews", func(raw apiReview) {
+		reviews = append(reviews, raw)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch reviews for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return reviews, nil
+}
+
+// giteaReviewComment is an inline review comment, which Gitea locates by position rather than line
+type giteaReviewComment struct {
+	apiReviewComment
+	Position         int `json:"position"`
+	OriginalPosition int `json:"original_position"`
+}
+
+// giteaReviewStates maps Gitea review states onto GitHub's
+var giteaReviewStates = map[string]string{
+	"APPROVED":        models.ReviewApproved,
+	"REQUEST_CHANGES": models.ReviewChangesRequested,
+	"COMMENT":         models.ReviewCommented,
+}
+
+// giteaRepo builds a repository endpoint, requesting the largest page size
+func giteaRepo(repo models.Repository, resource string) string {
+	endpoint := fmt.Sprintf("/repos/%s/%s/%s", repo.Owner, repo.Name, resource)
+	if strings.Contains(endpoint, "?") {
+		return fmt.Sprintf("%s&limit=%d", endpoint, giteaPageLimit)
+	}
+	return fmt.Sprintf("%s?limit=%d", endpoint, giteaPageLimit)
+}
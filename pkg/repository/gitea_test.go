@@ -0,0 +1,96 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/repository/repositorytest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGiteaFetcher(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+	server.PageSize = 1
+
+	server.Handle("/api/v1/repos/o/r/pulls", []map[string]any{
+		{
+			"number":     2,
+			"title":      "Recent",
+			"state":      "closed",
+			"html_url":   "https://forgejo.example.com/o/r/pulls/2",
+			"user":       map[string]any{"login": "alice"},
+			"created_at": "2024-01-10T00:00:00+09:00",
+			"merged_at":  "2024-01-11T00:00:00+09:00",
+			"closed_at":  "2024-01-11T00:00:00+09:00",
+			"additions":  12,
+			"deletions":  3,
+		},
+		{
+			"number":     1,
+			"title":      "Old",
+			"state":      "closed",
+			"user":       map[string]any{"login": "bob"},
+			"created_at": "2023-06-01T00:00:00Z",
+		},
+	})
+	server.Handle("/api/v1/repos/o/r/issues", []map[string]any{
+		{
+			"number":       3,
+			"title":        "Bug",
+			"state":        "open",
+			"user":         map[string]any{"login": "carol"},
+			"created_at":   "2024-01-05T00:00:00Z",
+			"labels":       []map[string]any{{"name": "bug"}},
+			"pull_request": nil,
+		},
+	})
+	server.Handle("/api/v1/repos/o/r/issues/2/comments", []map[string]any{
+		{"body": "LGTM", "user": map[string]any{"login": "bob"}, "created_at": "2024-01-10T01:00:00Z"},
+	})
+	server.Handle("/api/v1/repos/o/r/commits", []map[string]any{
+		{
+			"sha":      "abc",
+			"html_url": "https://forgejo.example.com/o/r/commit/abc",
+			"commit": map[string]any{
+				"message": "Fix bug",
+				"author":  map[string]any{"name": "alice", "date": "2024-01-02T00:00:00Z"},
+			},
+			"stats": map[string]any{"additions": 7, "deletions": 2, "total": 9},
+		},
+	})
+
+	fetcher := repository.NewGiteaFetcher(repository.NewClient(server.URL+"/api/v1", "token"))
+	repo := models.Repository{Provider: models.ProviderForgejo, Owner: "o", Name: "r"}
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	prs, err := fetcher.PullRequests(repo, since, until)
+	assert.NoError(t, err)
+	assert.Len(t, prs, 1)
+	assert.Equal(t, 2, prs[0].Number)
+	assert.NotNil(t, prs[0].MergedAt)
+	assert.Equal(t, 12, prs[0].Additions)
+
+	issues, err := fetcher.Issues(repo, since, until)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 1)
+	assert.Equal(t, []string{"bug"}, issues[0].Labels)
+
+	comments, err := fetcher.Comments(repo, models.KindPullRequest, 2)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "bob", comments[0].Author)
+
+	commits, err := fetcher.Commits(repo, since, true)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+	assert.Equal(t, 7, commits[0].Additions)
+
+	for _, uri := range server.Requests() {
+		assert.Contains(t, uri, "limit=50")
+	}
+}
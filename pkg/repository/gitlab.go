@@ -0,0 +1,637 @@
+package repository
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/parallel"
+)
+
+// DefaultGitLabHost is the host name of GitLab.com
+const DefaultGitLabHost = "gitlab.com"
+
+// GitLabBaseURL returns the REST API endpoint of a GitLab instance
+func GitLabBaseURL(host string) string {
+	return "https://" + host + "/api/v4"
+}
+
+// GitLabFetcher fetches project data from the GitLab REST API.
+// Merge requests map onto pull requests and notes onto comments.
+type GitLabFetcher struct {
+	client *Client
+	// detailedStats sums the line counts of merge requests from their diffs, a request each
+	detailedStats bool
+}
+
+// NewGitLabFetcher creates a new GitLabFetcher using the given client. With detailedStats,
+// merge requests get their line counts.
+func NewGitLabFetcher(client *Client, detailedStats bool) *GitLabFetcher {
+	return &GitLabFetcher{client: client, detailedStats: detailedStats}
+}
+
+// PullRequests fetches merge requests created between since and until, including their line counts
+func (f *GitLabFetcher) PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	endpoint := gitlabProject(repo, "merge_requests?scope=all&state=all") + createdRange(since, until)
+	prs, err := f.mergeRequests(repo, endpoint)
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, err
+	}
+
+	fmt.Printf("Found %d pull requests for %s/%s\n", len(prs), repo.Owner, repo.Name)
+	return prs, err
+}
+
+// PullRequestsUpdatedSince fetches merge requests updated on or after the given time
+func (f *GitLabFetcher) PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error) {
+	endpoint := gitlabProject(repo, "merge_requests?scope=all&state=all&updated_after="+gitlabTime(updated))
+	return f.mergeRequests(repo, endpoint)
+}
+
+func (f *GitLabFetcher) mergeRequests(repo models.Repository, endpoint string) ([]models.PullRequest, error) {
+	var raw []gitlabMergeRequest
+	err := listAll(f.client, endpoint, "pull requests", func(mr gitlabMergeRequest) {
+		raw = append(raw, mr)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch merge requests for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	prs := make([]models.PullRequest, len(raw))
+	for i, mr := range raw {
+		prs[i] = mr.toModel()
+	}
+	if !f.detailedStats {
+		return prs, nil
+	}
+
+	// The list endpoint has no line counts, so they are summed from each merge request's diffs
+	type result struct {
+		additions, deletions int
+		err                  error
+	}
+	results := parallel.Map(raw, Concurrency, func(mr gitlabMergeRequest) result {
+		additions, deletions, err := f.diffStats(repo, mr.IID)
+		return result{additions: additions, deletions: deletions, err: err}
+	})
+
+	var failed []error
+	for i, r := range results {
+		if r.err != nil {
+			failed = append(failed, r.err)
+			continue
+		}
+		prs[i].Additions, prs[i].Deletions = r.additions, r.deletions
+	}
+	if len(failed) > 0 {
+		return prs, fmt.Errorf("%w: could not fetch diffs of %d of %d merge requests in %s/%s (first error: %v)",
+			ErrIncomplete, len(failed), len(prs), repo.Owner, repo.Name, failed[0])
+	}
+	return prs, nil
+}
+
+// diffStats counts the lines added and deleted by a merge request
+func (f *GitLabFetcher) diffStats(repo models.Repository, iid int) (int, int, error) {
+	var additions, deletions int
+	endpoint := gitlabProject(repo, fmt.Sprintf("merge_requests/%d/diffs", iid))
+	err := f.client.paginate(endpoint, func(_ int, resp *http.Response) error {
+		return decodePages(resp.Body, func(d gitlabDiff) error {
+			a, del := countDiffLines(d.Diff)
+			additions += a
+			deletions += del
+			return nil
+		}, nil)
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not fetch diff of !%d: %w", iid, err)
+	}
+	return additions, deletions, nil
+}
+
+// Commits fetches commits since the given date. GitLab includes line statistics in the list.
+func (f *GitLabFetcher) Commits(repo models.Repository, since time.Time, detailedStats bool) ([]models.Commit, error) {
+	endpoint := gitlabProject(repo, "repository/commits?since="+gitlabTime(since))
+	if detailedStats {
+		endpoint += "&with_stats=true"
+	}
+
+	var commits []models.Commit
+	err := listAll(f.client, endpoint, "commits", func(raw gitlabCommit) {
+		commits = append(commits, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch commits for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	// GitLab links commits to no user, so authors are resolved from their email
+	resolveCommitAuthors(commits)
+
+	fmt.Printf("Found %d commits for %s/%s\n", len(commits), repo.Owner, repo.Name)
+	return commits, nil
+}
+
+// CommitStats fetches the line statistics of a single commit
+func (f *GitLabFetcher) CommitStats(repo models.Repository, sha string) (int, int, error) {
+	var detail gitlabCommit
+	if err := f.client.Get(gitlabProject(repo, "repository/commits/"+sha), &detail); err != nil {
+		return 0, 0, fmt.Errorf("could not fetch stats for commit %s: %w", sha, err)
+	}
+	if detail.Stats == nil {
+		return 0, 0, nil
+	}
+	return detail.Stats.Additions, detail.Stats.Deletions, nil
+}
+
+// CompareCommits fetches the commits between base and head
+func (f *GitLabFetcher) CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	var comparison struct {
+		Commits []gitlabCommit `json:"commits"`
+	}
+	endpoint := gitlabProject(repo, "repository/compare?from="+url.QueryEscape(base)+"&to="+url.QueryEscape(head))
+	if err := f.client.Get(endpoint, &comparison); err != nil {
+		return nil, fmt.Errorf("could not compare %s...%s in %s/%s: %w", base, head, repo.Owner, repo.Name, err)
+	}
+
+	commits := make([]models.Commit, 0, len(comparison.Commits))
+	for _, raw := range comparison.Commits {
+		commits = append(commits, raw.toModel())
+	}
+	resolveCommitAuthors(commits)
+	return commits, nil
+}
+
+// Issues fetches issues created between since and until
+func (f *GitLabFetcher) Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	endpoint := gitlabProject(repo, "issues?scope=all&state=all") + createdRange(since, until)
+	issues, err := f.issues(repo, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Found %d issues for %s/%s\n", len(issues), repo.Owner, repo.Name)
+	return issues, nil
+}
+
+// IssuesUpdatedSince fetches issues updated on or after the given time
+func (f *GitLabFetcher) IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error) {
+	return f.issues(repo, gitlabProject(repo, "issues?scope=all&state=all&updated_after="+gitlabTime(updated)))
+}
+
+func (f *GitLabFetcher) issues(repo models.Repository, endpoint string) ([]models.Issue, error) {
+	var issues []models.Issue
+	err := listAll(f.client, endpoint, "issues", func(raw gitlabIssue) {
+		issues = append(issues, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch issues for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return issues, nil
+}
+
+// Comments fetches the user notes on a merge request or issue, skipping system notes
+// and the diff notes returned by ReviewComments
+func (f *GitLabFetcher) Comments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	resource := "issues"
+	if kind == models.KindPullRequest {
+		resource = "merge_requests"
+	}
+	endpoint := gitlabProject(repo, fmt.Sprintf("%s/%d/notes?sort=asc&order_by=created_at", resource, number))
+	webURL := f.webURL(repo, resource, number)
+
+	var comments []models.Comment
+	err := listAll(f.client, endpoint, "comments", func(raw gitlabNote) {
+		if raw.System || raw.Type == gitlabDiffNote {
+			return
+		}
+		comment := raw.toModel()
+		comment.URL = fmt.Sprintf("%s#note_%d", webURL, raw.ID)
+		comments = append(comments, comment)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch comments for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return comments, nil
+}
+
+// ReviewComments fetches the diff notes of a merge request. Notes after the first in
+// a discussion are replies to it.
+func (f *GitLabFetcher) ReviewComments(repo models.Repository, iid int) ([]models.Comment, error) {
+	endpoint := gitlabProject(repo, fmt.Sprintf("merge_requests/%d/discussions", iid))
+	webURL := f.webURL(repo, "merge_requests", iid)
+
+	var comments []models.Comment
+	err := listAll(f.client, endpoint, "review comments", func(raw gitlabDiscussion) {
+		if len(raw.Notes) == 0 || raw.Notes[0].Type != gitlabDiffNote {
+			return
+		}
+		first := int64(raw.Notes[0].ID)
+		for i, note := range raw.Notes {
+			comment := note.toModel()
+			comment.SubType = models.CommentReviewComment
+			comment.ID = int64(note.ID)
+			comment.URL = fmt.Sprintf("%s#note_%d", webURL, note.ID)
+			if p := note.Position; p != nil {
+				comment.Path, comment.Line = p.NewPath, p.NewLine
+				if comment.Line == 0 {
+					comment.Path, comment.Line = p.OldPath, p.OldLine
+				}
+			}
+			if i > 0 {
+				comment.InReplyTo = first
+			}
+			comments = append(comments, comment)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch review comments for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return comments, nil
+}
+
+// Deployments fetches the deployments created between since and until. Each GitLab deployment
+// is a single attempt, so its status becomes its only status.
+func (f *GitLabFetcher) Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	endpoint := gitlabProject(repo, "deployments?order_by=created_at&sort=asc")
+	if !since.IsZero() {
+		endpoint += "&updated_after=" + gitlabTime(truncateDay(since))
+	}
+
+	var deployments []models.Deployment
+	err := listAll(f.client, endpoint, "deployments", func(raw gitlabDeployment) {
+		if inDateRange(raw.CreatedAt.Time, since, until) {
+			deployments = append(deployments, raw.toModel())
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch deployments for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	fmt.Printf("Found %d deployments for %s/%s\n", len(deployments), repo.Owner, repo.Name)
+	return deployments, nil
+}
+
+// Releases fetches all releases of a project. GitLab has no draft releases; upcoming
+// releases, whose release date is in the future, are reported as drafts.
+func (f *GitLabFetcher) Releases(repo models.Repository) ([]models.Release, error) {
+	var releases []models.Release
+	err := listAll(f.client, gitlabProject(repo, "releases"), "releases", func(raw gitlabRelease) {
+		releases = append(releases, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch releases for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	fmt.Printf("Found %d releases for %s/%s\n", len(releases), repo.Owner, repo.Name)
+	return releases, nil
+}
+
+// Tags fetches all tags of a project
+func (f *GitLabFetcher) Tags(repo models.Repository) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := listAll(f.client, gitlabProject(repo, "repository/tags"), "tags", func(raw gitlabTag) {
+		tags = append(tags, models.Tag{Name: raw.Name, SHA: raw.Commit.ID})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch tags for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return tags, nil
+}
+
+// Timeline is not supported; timelines are read from GitHub only
+func (f *GitLabFetcher) Timeline(repo models.Repository, kind string, iid int) ([]models.TimelineEvent, error) {
+	return nil, fmt.Errorf("%w: %s/%s is on %s; timelines are only read from GitHub", errors.ErrUnsupported, repo.Owner, repo.Name, repo.ProviderName())
+}
+
+// WorkflowRuns is not supported; workflow runs are read from GitHub Actions only
+func (f *GitLabFetcher) WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	return nil, fmt.Errorf("%w: %s/%s is on %s; workflow runs are only read from GitHub Actions", errors.ErrUnsupported, repo.Owner, repo.Name, repo.ProviderName())
+}
+
+// webURL returns the web page of a merge request or issue
+func (f *GitLabFetcher) webURL(repo models.Repository, resource string, number int) string {
+	return fmt.Sprintf("%s/%s/%s/-/%s/%d",
+		strings.TrimSuffix(f.client.BaseURL, "/api/v4"), repo.Owner, repo.Name, resource, number)
+}
+
+// Reviews derives reviews from the system notes GitLab adds when a merge request is approved or changes are requested
+func (f *GitLabFetcher) Reviews(repo models.Repository, iid int) ([]models.Review, error) {
+	notes, err := f.systemNotes(repo, iid)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []models.Review
+	for _, note := range notes {
+		state := ""
+		switch {
+		case strings.HasPrefix(note.Body, "approved this merge request"):
+			state = models.ReviewApproved
+		case strings.HasPrefix(note.Body, "requested changes"):
+			state = models.ReviewChangesRequested
+		default:
+			continue
+		}
+		reviews = append(reviews, models.Review{Reviewer: note.Author.username(), State: state, SubmittedAt: note.CreatedAt.Time})
+	}
+	return reviews, nil
+}
+
+// ReviewRequests derives review requests from "requested review from @user" system notes
+func (f *GitLabFetcher) ReviewRequests(repo models.Repository, iid int) ([]models.ReviewRequest, error) {
+	notes, err := f.systemNotes(repo, iid)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []models.ReviewRequest
+	for _, note := range notes {
+		rest, ok := strings.CutPrefix(note.Body, "requested review from ")
+		if !ok {
+			continue
+		}
+		for _, word := range strings.Fields(rest) {
+			if reviewer, ok := strings.CutPrefix(strings.TrimRight(word, ","), "@"); ok {
+				requests = append(requests, models.ReviewRequest{Reviewer: reviewer, RequestedAt: note.CreatedAt.Time})
+			}
+		}
+	}
+	return requests, nil
+}
+
+func (f *GitLabFetcher) systemNotes(repo models.Repository, iid int) ([]gitlabNote, error) {
+	endpoint := gitlabProject(repo, fmt.Sprintf("merge_requests/%d/notes?sort=asc&order_by=created_at", iid))
+	var notes []gitlabNote
+	err := listAll(f.client, endpoint, "reviews", func(raw gitlabNote) {
+		if raw.System {
+			notes = append(notes, raw)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch reviews for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return notes, nil
+}
+
+// gitlabProject builds a project endpoint; the project is addressed by its URL-encoded path
+func gitlabProject(repo models.Repository, resource string) string {
+	return fmt.Sprintf("/projects/%s/%s", url.PathEscape(repo.Owner+"/"+repo.Name), resource)
+}
+
+// createdRange adds created_after/created_before parameters for the calendar days from since to until
+func createdRange(since, until time.Time) string {
+	var params string
+	if !since.IsZero() {
+		params += "&created_after=" + gitlabTime(truncateDay(since))
+	}
+	if !until.IsZero() {
+		params += "&created_before=" + gitlabTime(truncateDay(until).AddDate(0, 0, 1))
+	}
+	return params
+}
+
+func gitlabTime(t time.Time) string {
+	return url.QueryEscape(t.UTC().Format(time.RFC3339))
+}
+
+// countDiffLines counts added and deleted lines in a GitLab diff, which has hunks but no file headers
+func countDiffLines(diff string) (int, int) {
+	var additions, deletions int
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// gitlabState maps GitLab states (opened, closed, merged, locked) onto GitHub's open and closed
+func gitlabState(state string) string {
+	if state == "opened" {
+		return "open"
+	}
+	return "closed"
+}
+
+type gitlabUser struct {
+	Username string `json:"username"`
+	Bot      bool   `json:"bot"`
+}
+
+func (u *gitlabUser) username() string {
+	if u == nil {
+		return ""
+	}
+	return botLogin(u.Username, u.Bot)
+}
+
+type gitlabMergeRequest struct {
+	IID          int         `json:"iid"`
+	Title        string      `json:"title"`
+	State        string      `json:"state"`
+	Description  string      `json:"description"`
+	WebURL       string      `json:"web_url"`
+	Author       *gitlabUser `json:"author"`
+	CreatedAt    timestamp   `json:"created_at"`
+	MergedAt     timestamp   `json:"merged_at"`
+	ClosedAt     timestamp   `json:"closed_at"`
+	MergeCommit  string      `json:"merge_commit_sha"`
+	SquashCommit string      `json:"squash_commit_sha"`
+	SHA          string      `json:"sha"`
+}
+
+func (m gitlabMergeRequest) toModel() models.PullRequest {
+	closedAt := m.ClosedAt.ptr()
+	if closedAt == nil {
+		// GitLab leaves closed_at empty for merged requests, unlike GitHub
+		closedAt = m.MergedAt.ptr()
+	}
+	// Fast-forward merges leave the head commit itself on the target branch
+	mergeCommit := ""
+	if m.MergedAt.ptr() != nil {
+		mergeCommit = cmp.Or(m.MergeCommit, m.SquashCommit, m.SHA)
+	}
+
+	return models.PullRequest{
+		Number:    m.IID,
+		Title:     m.Title,
+		State:     gitlabState(m.State),
+		Author:    m.Author.username(),
+		Body:      m.Description,
+		URL:       m.WebURL,
+		CreatedAt: m.CreatedAt.Time,
+		MergedAt:  m.MergedAt.ptr(),
+		ClosedAt:  closedAt,
+
+		MergeCommitSHA: mergeCommit,
+	}
+}
+
+type gitlabDiff struct {
+	Diff string `json:"diff"`
+}
+
+type gitlabIssue struct {
+	IID         int         `json:"iid"`
+	Title       string      `json:"title"`
+	State       string      `json:"state"`
+	Description string      `json:"description"`
+	WebURL      string      `json:"web_url"`
+	Author      *gitlabUser `json:"author"`
+	CreatedAt   timestamp   `json:"created_at"`
+	ClosedAt    timestamp   `json:"closed_at"`
+	Labels      []string    `json:"labels"`
+}
+
+func (i gitlabIssue) toModel() models.Issue {
+	return models.Issue{
+		Number:    i.IID,
+		Title:     i.Title,
+		State:     gitlabState(i.State),
+		Author:    i.Author.username(),
+		Body:      i.Description,
+		URL:       i.WebURL,
+		CreatedAt: i.CreatedAt.Time,
+		ClosedAt:  i.ClosedAt.ptr(),
+		Labels:    i.Labels,
+	}
+}
+
+type gitlabCommit struct {
+	ID           string          `json:"id"`
+	Message      string          `json:"message"`
+	AuthorName   string          `json:"author_name"`
+	AuthorEmail  string          `json:"author_email"`
+	AuthoredDate timestamp       `json:"authored_date"`
+	WebURL       string          `json:"web_url"`
+	Stats        *apiCommitStats `json:"stats"`
+}
+
+func (c gitlabCommit) toModel() models.Commit {
+	commit := models.Commit{
+		SHA:         c.ID,
+		Message:     c.Message,
+		AuthorName:  c.AuthorName,
+		AuthorEmail: c.AuthorEmail,
+		CoAuthors:   models.ParseCoAuthors(c.Message),
+		Date:        c.AuthoredDate.Time,
+		URL:         c.WebURL,
+	}
+	if c.Stats != nil {
+		commit.Additions = c.Stats.Additions
+		commit.Deletions = c.Stats.Deletions
+	}
+	return commit
+}
+
+type gitlabDeployment struct {
+	ID          int       `json:"id"`
+	SHA         string    `json:"sha"`
+	Status      string    `json:"status"`
+	CreatedAt   timestamp `json:"created_at"`
+	UpdatedAt   timestamp `json:"updated_at"`
+	FinishedAt  timestamp `json:"finished_at"`
+	Environment struct {
+		Name string `json:"name"`
+	} `json:"environment"`
+}
+
+// gitlabDeploymentStates maps GitLab deployment statuses onto GitHub's; others are kept as they are
+var gitlabDeploymentStates = map[string]string{
+	"failed":   models.DeploymentFailure,
+	"canceled": models.DeploymentInactive,
+}
+
+func (d gitlabDeployment) toModel() models.Deployment {
+	state := d.Status
+	if mapped, ok := gitlabDeploymentStates[state]; ok {
+		state = mapped
+	}
+	// finished_at is only reported by newer GitLab versions
+	at := d.FinishedAt.Time
+	if at.IsZero() {
+		at = d.UpdatedAt.Time
+	}
+	return models.Deployment{
+		ID:          d.ID,
+		Environment: d.Environment.Name,
+		State:       state,
+		CreatedAt:   d.CreatedAt.Time,
+		UpdatedAt:   d.UpdatedAt.Time,
+		SHA:         d.SHA,
+		Statuses:    []models.DeploymentStatus{{State: state, CreatedAt: at}},
+	}
+}
+
+type gitlabCommitRef struct {
+	ID string `json:"id"`
+}
+
+type gitlabRelease struct {
+	TagName         string          `json:"tag_name"`
+	Name            string          `json:"name"`
+	CreatedAt       timestamp       `json:"created_at"`
+	ReleasedAt      timestamp       `json:"released_at"`
+	UpcomingRelease bool            `json:"upcoming_release"`
+	Commit          gitlabCommitRef `json:"commit"`
+	Links           struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+func (r gitlabRelease) toModel() models.Release {
+	return models.Release{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Draft:       r.UpcomingRelease,
+		SHA:         r.Commit.ID,
+		CreatedAt:   r.CreatedAt.Time,
+		PublishedAt: r.ReleasedAt.Time,
+		URL:         r.Links.Self,
+	}
+}
+
+type gitlabTag struct {
+	Name   string          `json:"name"`
+	Commit gitlabCommitRef `json:"commit"`
+}
+
+// gitlabDiffNote is the type of notes on a merge request diff
+const gitlabDiffNote = "DiffNote"
+
+type gitlabNote struct {
+	ID        int             `json:"id"`
+	Type      string          `json:"type"`
+	Body      string          `json:"body"`
+	System    bool            `json:"system"`
+	Author    *gitlabUser     `json:"author"`
+	CreatedAt timestamp       `json:"created_at"`
+	Position  *gitlabPosition `json:"position"`
+}
+
+// gitlabPosition locates a diff note; the new side is empty for removed lines
+type gitlabPosition struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+	OldLine int    `json:"old_line"`
+	NewLine int    `json:"new_line"`
+}
+
+// gitlabDiscussion is a thread of notes
+type gitlabDiscussion struct {
+	Notes []gitlabNote `json:"notes"`
+}
+
+func (n gitlabNote) toModel() models.Comment {
+	return models.Comment{
+		Author:    n.Author.username(),
+		Body:      n.Body,
+		CreatedAt: n.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,228 @@
+package repository_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/repository/repositorytest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitLabFetcher(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+
+	// Project paths are URL-encoded in requests; the server sees them decoded
+	project := "/api/v4/projects/group/sub/project"
+	server.Handle(project+"/merge_requests", []map[string]any{
+		{
+			"iid":         3,
+			"title":       "Add feature",
+			"state":       "merged",
+			"description": "Implements it",
+			"web_url":     "https://gitlab.example.com/group/sub/project/-/merge_requests/3",
+			"author":      map[string]any{"username": "alice"},
+			"created_at":  "2024-01-02T00:00:00.000Z",
+			"merged_at":   "2024-01-03T00:00:00.000+09:00",
+			"closed_at":   nil,
+		},
+	})
+	server.Handle(project+"/merge_requests/3/diffs", []map[string]any{
+		{"diff": "@@ -1,2 +1,2 @@\n-old\n--- sql comment\n+new\n+more\n context\n"},
+	})
+	server.Handle(project+"/issues", []map[string]any{
+		{
+			"iid":        5,
+			"title":      "Bug",
+			"state":      "opened",
+			"author":     map[string]any{"username": "bob"},
+			"created_at": "2024-01-04T00:00:00Z",
+			"labels":     []string{"bug", "p1"},
+		},
+	})
+	server.Handle(project+"/merge_requests/3/notes", []map[string]any{
+		{"id": 9, "body": "requested review from @bob and @carol", "system": true, "author": map[string]any{"username": "alice"}, "created_at": "2024-01-02T00:30:00Z"},
+		{"id": 10, "body": "approved this merge request", "system": true, "author": map[string]any{"username": "bob"}, "created_at": "2024-01-02T01:00:00Z"},
+		{"id": 11, "body": "LGTM", "system": false, "author": map[string]any{"username": "bob"}, "created_at": "2024-01-02T02:00:00Z"},
+		{"id": 12, "type": "DiffNote", "body": "Typo", "system": false, "author": map[string]any{"username": "carol"}, "created_at": "2024-01-02T03:00:00Z"},
+	})
+	server.Handle(project+"/merge_requests/3/discussions", []map[string]any{
+		{"notes": []map[string]any{
+			{"id": 11, "body": "LGTM", "author": map[string]any{"username": "bob"}, "created_at": "2024-01-02T02:00:00Z"},
+		}},
+		{"notes": []map[string]any{
+			{
+				"id": 12, "type": "DiffNote", "body": "Typo", "author": map[string]any{"username": "carol"},
+				"created_at": "2024-01-02T03:00:00Z",
+				"position":   map[string]any{"old_path": "a.go", "new_path": "a.go", "old_line": 4, "new_line": nil},
+			},
+			{
+				"id": 13, "type": "DiffNote", "body": "Fixed", "author": map[string]any{"username": "alice"},
+				"created_at": "2024-01-02T04:00:00Z",
+				"position":   map[string]any{"old_path": "a.go", "new_path": "a.go", "old_line": 4, "new_line": nil},
+			},
+		}},
+	})
+	server.Handle(project+"/deployments", []map[string]any{
+		{
+			"id": 7, "sha": "abc", "status": "failed", "environment": map[string]any{"name": "production"},
+			"created_at": "2024-01-02T00:00:00Z", "updated_at": "2024-01-02T00:10:00Z", "finished_at": nil,
+		},
+		{
+			"id": 8, "sha": "abc", "status": "success", "environment": map[string]any{"name": "production"},
+			"created_at": "2024-01-02T01:00:00Z", "updated_at": "2024-01-02T01:20:00Z", "finished_at": "2024-01-02T01:15:00Z",
+		},
+	})
+	server.Handle(project+"/releases", []map[string]any{
+		{
+			"tag_name": "v1.0", "name": "First", "commit": map[string]any{"id": "abc"},
+			"created_at": "2024-01-05T00:00:00Z", "released_at": "2024-01-05T00:00:00Z",
+			"_links": map[string]any{"self": "https://gitlab.example.com/group/sub/project/-/releases/v1.0"},
+		},
+		{"tag_name": "v2.0", "upcoming_release": true, "created_at": "2024-01-06T00:00:00Z", "released_at": "2024-02-01T00:00:00Z"},
+	})
+	server.Handle(project+"/repository/tags", []map[string]any{
+		{"name": "v1.0", "commit": map[string]any{"id": "abc"}},
+	})
+	server.Handle(project+"/repository/commits", []map[string]any{
+		{
+			"id":            "abc",
+			"message":       "Fix bug",
+			"author_name":   "Alice",
+			"author_email":  "123-alice@users.noreply.gitlab.com",
+			"authored_date": "2024-01-02T00:00:00Z",
+			"web_url":       "https://gitlab.example.com/group/sub/project/-/commit/abc",
+			"stats":         map[string]any{"additions": 4, "deletions": 1},
+		},
+	})
+
+	server.Handle(project+"/repository/compare", map[string]any{
+		"commits": []map[string]any{
+			{"id": "def", "message": "Add", "author_name": "Bob", "author_email": "bob@example.com", "authored_date": "2024-01-03T00:00:00Z"},
+		},
+	})
+
+	fetcher := repository.NewGitLabFetcher(repository.NewClient(server.URL+"/api/v4", "token"), false)
+	repo := models.Repository{Provider: models.ProviderGitLab, Owner: "group/sub", Name: "project"}
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("merge requests", func(t *testing.T) {
+		prs, err := fetcher.PullRequests(repo, since, until)
+		assert.NoError(t, err)
+		assert.Len(t, prs, 1)
+		assert.Equal(t, 3, prs[0].Number)
+		assert.Equal(t, "closed", prs[0].State)
+		assert.Equal(t, "alice", prs[0].Author)
+		assert.NotNil(t, prs[0].MergedAt)
+		assert.Equal(t, prs[0].MergedAt, prs[0].ClosedAt)
+		assert.Zero(t, prs[0].Additions)
+		for _, request := range server.Requests() {
+			assert.NotContains(t, request, "/diffs")
+		}
+	})
+
+	t.Run("merge requests with detailed stats", func(t *testing.T) {
+		fetcher := repository.NewGitLabFetcher(repository.NewClient(server.URL+"/api/v4", "token"), true)
+
+		prs, err := fetcher.PullRequests(repo, since, until)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, prs[0].Additions)
+		assert.Equal(t, 2, prs[0].Deletions)
+
+		server.Fail(project+"/merge_requests/3/diffs", http.StatusNotFound, nil)
+		prs, err = fetcher.PullRequests(repo, since, until)
+		assert.ErrorIs(t, err, repository.ErrIncomplete)
+		assert.Len(t, prs, 1)
+		assert.Zero(t, prs[0].Additions)
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		issues, err := fetcher.Issues(repo, since, until)
+		assert.NoError(t, err)
+		assert.Len(t, issues, 1)
+		assert.Equal(t, "open", issues[0].State)
+		assert.Equal(t, []string{"bug", "p1"}, issues[0].Labels)
+	})
+
+	t.Run("notes", func(t *testing.T) {
+		comments, err := fetcher.Comments(repo, models.KindPullRequest, 3)
+		assert.NoError(t, err)
+		assert.Len(t, comments, 1)
+		assert.Equal(t, "LGTM", comments[0].Body)
+		assert.Equal(t, server.URL+"/group/sub/project/-/merge_requests/3#note_11", comments[0].URL)
+	})
+
+	t.Run("diff notes", func(t *testing.T) {
+		comments, err := fetcher.ReviewComments(repo, 3)
+		assert.NoError(t, err)
+		assert.Len(t, comments, 2)
+		assert.Equal(t, models.CommentReviewComment, comments[0].SubType)
+		assert.Equal(t, int64(12), comments[0].ID)
+		assert.Equal(t, "a.go", comments[0].Path)
+		assert.Equal(t, 4, comments[0].Line)
+		assert.Equal(t, int64(0), comments[0].InReplyTo)
+		assert.Equal(t, int64(12), comments[1].InReplyTo)
+		assert.Equal(t, server.URL+"/group/sub/project/-/merge_requests/3#note_13", comments[1].URL)
+	})
+
+	t.Run("reviews", func(t *testing.T) {
+		reviews, err := fetcher.Reviews(repo, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Review{
+			{Reviewer: "bob", State: models.ReviewApproved, SubmittedAt: time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC)},
+		}, reviews)
+
+		requests, err := fetcher.ReviewRequests(repo, 3)
+		assert.NoError(t, err)
+		assert.Len(t, requests, 2)
+		assert.Equal(t, "bob", requests[0].Reviewer)
+		assert.Equal(t, "carol", requests[1].Reviewer)
+	})
+
+	t.Run("deployments", func(t *testing.T) {
+		deployments, err := fetcher.Deployments(repo, since, until)
+		assert.NoError(t, err)
+		assert.Len(t, deployments, 2)
+		assert.True(t, deployments[0].Failed())
+		assert.Equal(t, models.DeploymentFailure, deployments[0].State)
+		assert.Equal(t, time.Date(2024, 1, 2, 0, 10, 0, 0, time.UTC), *deployments[0].FailedAt())
+		assert.Equal(t, "production", deployments[1].Environment)
+		assert.Equal(t, time.Date(2024, 1, 2, 1, 15, 0, 0, time.UTC), *deployments[1].SucceededAt())
+	})
+
+	t.Run("releases", func(t *testing.T) {
+		releases, err := fetcher.Releases(repo)
+		assert.NoError(t, err)
+		assert.Len(t, releases, 2)
+		assert.Equal(t, "abc", releases[0].SHA)
+		assert.Equal(t, "https://gitlab.example.com/group/sub/project/-/releases/v1.0", releases[0].URL)
+		assert.True(t, releases[1].Draft)
+
+		tags, err := fetcher.Tags(repo)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Tag{{Name: "v1.0", SHA: "abc"}}, tags)
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		commits, err := fetcher.CompareCommits(repo, "abc", "def")
+		assert.NoError(t, err)
+		assert.Len(t, commits, 1)
+		assert.Equal(t, "def", commits[0].SHA)
+		assert.Equal(t, "Bob", commits[0].Author)
+		assert.Contains(t, server.Requests(), "/api/v4/projects/group%2Fsub%2Fproject/repository/compare?from=abc&to=def")
+	})
+
+	t.Run("commits", func(t *testing.T) {
+		commits, err := fetcher.Commits(repo, since, true)
+		assert.NoError(t, err)
+		assert.Len(t, commits, 1)
+		assert.Equal(t, "alice", commits[0].Author)
+		assert.Equal(t, "Alice", commits[0].AuthorName)
+		assert.Equal(t, 4, commits[0].Additions)
+	})
+}
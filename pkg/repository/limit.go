@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"io"
+	"sync"
+)
+
+// requestSlots holds a slot for every request in flight. It is sized from Concurrency on first
+// use, so the limit applies across all repositories and all the lookups running for them.
+var requestSlots = sync.OnceValue(func() chan struct{} {
+	return make(chan struct{}, max(Concurrency, 1))
+})
+
+// acquireRequest waits for a free request slot and returns the function releasing it
+func acquireRequest() func() {
+	slots := requestSlots()
+	slots <- struct{}{}
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-slots })
+	}
+}
+
+// releasingBody releases its request slot once the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
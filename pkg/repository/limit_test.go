@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/parallel"
+	"yokiyoki/pkg/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_RequestsShareConcurrencyLimit(t *testing.T) {
+	var running, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := repository.NewClient(server.URL, "")
+
+	// Nested pools, like repositories fanning out to per-item lookups, stay within one limit
+	outer := make([]int, repository.Concurrency)
+	parallel.Map(outer, repository.Concurrency, func(int) struct{} {
+		parallel.Map(make([]int, 8), repository.Concurrency, func(int) struct{} {
+			var v map[string]any
+			assert.NoError(t, client.Get("/", &v))
+			return struct{}{}
+		})
+		return struct{}{}
+	})
+
+	assert.LessOrEqual(t, peak, int32(repository.Concurrency))
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// errNoIncrementalSync reports that items updated since the last sync cannot be listed reliably,
+// so CachedFetcher falls back to a full fetch
+var errNoIncrementalSync = errors.New("incremental sync unavailable")
+
+// FetcherFactory creates the fetcher for a provider and host. host is "" for the provider's default host.
+type FetcherFactory func(provider, host string) (Fetcher, error)
+
+// origin identifies the provider and host a repository lives on
+type origin struct {
+	provider string
+	host     string
+}
+
+// ProviderFetcher dispatches each request to the fetcher of the repository's provider and host,
+// creating fetchers on first use
+type ProviderFetcher struct {
+	factory FetcherFactory
+
+	mu       sync.Mutex
+	fetchers map[origin]Fetcher
+}
+
+// NewProviderFetcher creates a ProviderFetcher that builds fetchers with factory
+func NewProviderFetcher(factory FetcherFactory) *ProviderFetcher {
+	return &ProviderFetcher{factory: factory, fetchers: make(map[origin]Fetcher)}
+}
+
+func (f *ProviderFetcher) fetcher(repo models.Repository) (Fetcher, error) {
+	key := origin{provider: repo.ProviderName(), host: repo.Host}
+	if key.provider == models.ProviderGitHub && key.host == models.GitHubHost {
+		key.host = ""
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if fetcher, ok := f.fetchers[key]; ok {
+		return fetcher, nil
+	}
+
+	fetcher, err := f.factory(key.provider, key.host)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	f.fetchers[key] = fetcher
+	return fetcher, nil
+}
+
+// Commits fetches commits from the repository's provider
+func (f *ProviderFetcher) Commits(repo models.Repository, since time.Time, detailedStats bool) ([]models.Commit, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.Commits(repo, since, detailedStats)
+}
+
+// PullRequests fetches pull requests from the repository's provider
+func (f *ProviderFetcher) PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.PullRequests(repo, since, until)
+}
+
+// Issues fetches issues from the repository's provider
+func (f *ProviderFetcher) Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.Issues(repo, since, until)
+}
+
+// Comments fetches comments from the repository's provider
+func (f *ProviderFetcher) Comments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.Comments(repo, kind, number)
+}
+
+// CommitStats fetches commit statistics from the repository's provider
+func (f *ProviderFetcher) CommitStats(repo models.Repository, sha string) (int, int, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return 0, 0, err
+	}
+	return fetcher.CommitStats(repo, sha)
+}
+
+// CompareCommits compares commits on the repository's provider
+func (f *ProviderFetcher) CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.CompareCommits(repo, base, head)
+}
+
+// Reviews fetches pull request reviews from the repository's provider
+func (f *ProviderFetcher) Reviews(repo models.Repository, number int) ([]models.Review, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.Reviews(repo, number)
+}
+
+// Timeline fetches timeline events from the repository's provider
+func (f *ProviderFetcher) Timeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.Timeline(repo, kind, number)
+}
+
+// ReviewRequests fetches pull request review requests from the repository's provider
+func (f *ProviderFetcher) ReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.ReviewRequests(repo, number)
+}
+
+// ReviewComments fetches inline review comments from the repository's provider
+func (f *ProviderFetcher) ReviewComments(repo models.Repository, number int) ([]models.Comment, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.ReviewComments(repo, number)
+}
+
+// Deployments fetches deployments from the repository's provider
+func (f *ProviderFetcher) Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.Deployments(repo, since, until)
+}
+
+// Releases fetches releases from the repository's provider
+func (f *ProviderFetcher) Releases(repo models.Repository) ([]models.Release, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.Releases(repo)
+}
+
+// Tags fetches tags from the repository's provider
+func (f *ProviderFetcher) Tags(repo models.Repository) ([]models.Tag, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.Tags(repo)
+}
+
+// WorkflowRuns fetches workflow runs from the repository's provider
+func (f *ProviderFetcher) WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.WorkflowRuns(repo, since, until)
+}
+
+// PullRequestsUpdatedSince lists updated pull requests when the provider's fetcher supports it
+func (f *ProviderFetcher) PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	u, ok := fetcher.(updatedFetcher)
+	if !ok {
+		return nil, errNoIncrementalSync
+	}
+	return u.PullRequestsUpdatedSince(repo, updated)
+}
+
+// IssuesUpdatedSince lists updated issues when the provider's fetcher supports it
+func (f *ProviderFetcher) IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error) {
+	fetcher, err := f.fetcher(repo)
+	if err != nil {
+		return nil, err
+	}
+	u, ok := fetcher.(updatedFetcher)
+	if !ok {
+		return nil, errNoIncrementalSync
+	}
+	return u.IssuesUpdatedSince(repo, updated)
+}
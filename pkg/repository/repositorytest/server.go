@@ -0,0 +1,170 @@
+// Package repositorytest provides an httptest-backed stand-in for the GitHub REST API.
+package repositorytest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server serves canned JSON responses for API endpoints.
+// List responses are split into pages of PageSize items linked with Link headers.
+type Server struct {
+	*httptest.Server
+	PageSize int
+
+	mu          sync.Mutex
+	routes      map[string]any
+	failures    map[string][]failure
+	requests    []string
+	notModified int
+}
+
+// failure is an error response queued for a path
+type failure struct {
+	status int
+	header http.Header
+}
+
+// NewServer starts a new Server. Callers should Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		PageSize: 100,
+		routes:   make(map[string]any),
+		failures: make(map[string][]failure),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Handle registers the response body for an endpoint such as "/repos/o/r/pulls?state=all".
+// Query parameters other than page and per_page must match for the route to apply.
+func (s *Server) Handle(endpoint string, body any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, query, _ := strings.Cut(endpoint, "?")
+	values, _ := url.ParseQuery(query)
+	s.routes[routeKey(path, values)] = body
+}
+
+// Fail queues an error response with the given status and headers for the next request to path.
+// Queued failures are served in order before the registered response.
+func (s *Server) Fail(path string, status int, header http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[path] = append(s.failures[path], failure{status: status, header: header})
+}
+
+// NotModified returns how many conditional requests were answered with 304 Not Modified
+func (s *Server) NotModified() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notModified
+}
+
+// Requests returns the request URIs received so far
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	if queued := s.failures[r.URL.Path]; len(queued) > 0 {
+		s.failures[r.URL.Path] = queued[1:]
+		s.mu.Unlock()
+		for k, v := range queued[0].header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(queued[0].status)
+		fmt.Fprintf(w, `{"message":%q}`, http.StatusText(queued[0].status))
+		return
+	}
+	body, ok := s.routes[routeKey(r.URL.Path, r.URL.Query())]
+	if !ok {
+		body, ok = s.routes[r.URL.Path]
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		s.write(w, r, data)
+		return
+	}
+
+	s.writePage(w, r, items)
+}
+
+// write sends a JSON body with an ETag, answering matching conditional requests with 304
+func (s *Server) write(w http.ResponseWriter, r *http.Request, data []byte) {
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		s.mu.Lock()
+		s.notModified++
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []json.RawMessage) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	start := min((page-1)*s.PageSize, len(items))
+	end := min(start+s.PageSize, len(items))
+	if end < len(items) {
+		next := *r.URL
+		query := next.Query()
+		query.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s>; rel="next"`, s.URL, next.RequestURI()))
+	}
+
+	data, _ := json.Marshal(items[start:end])
+	s.write(w, r, data)
+}
+
+func routeKey(path string, values url.Values) string {
+	values = cloneValues(values)
+	values.Del("page")
+	values.Del("per_page")
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
+
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for k, v := range values {
+		clone[k] = v
+	}
+	return clone
+}
@@ -0,0 +1,588 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/cache"
+	"yokiyoki/pkg/models"
+)
+
+// DefaultHost is the host name of public GitHub
+const DefaultHost = models.GitHubHost
+
+// DefaultBaseURL is the REST API endpoint of public GitHub
+const DefaultBaseURL = "https://api.github.com"
+
+// GitHubBaseURL returns the REST API endpoint of github.com or a GitHub Enterprise Server host
+func GitHubBaseURL(host string) string {
+	if host == "" || host == DefaultHost {
+		return DefaultBaseURL
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// linkNextPattern matches the rel="next" entry of a Link response header
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// Client is a minimal GitHub REST API client
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+	// Cache, when set, keeps response bodies so repeated requests are revalidated with ETags
+	Cache *cache.Store
+	// Retry controls retries of rate-limited and failed requests
+	Retry RetryPolicy
+
+	limiter rateLimiter
+}
+
+// cachedResponse is a response page kept for conditional revalidation
+type cachedResponse struct {
+	ETag string `json:"etag"`
+	Link string `json:"link,omitempty"`
+	Body []byte `json:"body"`
+}
+
+// NewClient creates a new Client for the given API base URL and token
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 60 * time.Second},
+		Retry:      DefaultRetryPolicy,
+	}
+}
+
+// Get fetches a single resource and decodes it into v
+func (c *Client) Get(endpoint string, v any) error {
+	resp, err := c.do(c.BaseURL + endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// listAll streams every item of a list endpoint into onItem, following Link headers
+func listAll[T any](c *Client, endpoint, resourceType string, onItem func(T)) error {
+	progress := newPageProgress(resourceType)
+	defer progress.done()
+
+	return c.paginate(endpoint, func(page int, resp *http.Response) error {
+		return decodePages(resp.Body, func(item T) error {
+			onItem(item)
+			return nil
+		}, func(_, items int) {
+			progress.page(page, items)
+		})
+	})
+}
+
+// searchAll streams every item of a search endpoint into onItem, following Link headers
+func searchAll[T any](c *Client, endpoint, resourceType string, onItem func(T)) error {
+	return listField(c, endpoint, "items", resourceType, onItem)
+}
+
+// listField streams every item of an endpoint wrapping its pages in objects, where the items
+// are held in field, into onItem, following Link headers
+func listField[T any](c *Client, endpoint, field, resourceType string, onItem func(T)) error {
+	progress := newPageProgress(resourceType)
+	defer progress.done()
+
+	return c.paginate(endpoint, func(page int, resp *http.Response) error {
+		return decodeFieldPages(resp.Body, field, func(item T) error {
+			onItem(item)
+			return nil
+		}, func(_, items int) {
+			progress.page(page, items)
+		})
+	})
+}
+
+func (c *Client) paginate(endpoint string, decode func(page int, resp *http.Response) error) error {
+	next := c.BaseURL + withPerPage(endpoint)
+	for page := 1; next != ""; page++ {
+		resp, err := c.do(next)
+		if err != nil {
+			return err
+		}
+
+		err = decode(page, resp)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("could not parse response from %s: %w", endpoint, err)
+		}
+
+		next = nextPageURL(resp.Header.Get("Link"))
+	}
+	return nil
+}
+
+// do sends a GET request, pausing while the rate limit is exhausted and retrying transient failures
+func (c *Client) do(rawURL string) (*http.Response, error) {
+	var resp *http.Response
+	err := c.Retry.retry(func() error {
+		var err error
+		resp, err = c.attempt(rawURL)
+		return err
+	})
+	return resp, err
+}
+
+func (c *Client) attempt(rawURL string) (*http.Response, error) {
+	c.limiter.wait(c.Retry.MaxWait)
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	var cached cachedResponse
+	cacheKey := responseCacheKey(rawURL)
+	hasCached := false
+	if c.Cache != nil {
+		hasCached, _ = c.Cache.Load(cacheKey, &cached)
+		if hasCached && cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
+	// The slot is held until the body is read, or handed over with the body when it is streamed
+	release := acquireRequest()
+	streamed := false
+	defer func() {
+		if !streamed {
+			release()
+		}
+	}()
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
+	}
+	c.limiter.observe(resp.Header)
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		resp.Body.Close()
+		return cached.response(), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return nil, classifyResponse(resp, apiErr.Message)
+	}
+
+	etag := resp.Header.Get("ETag")
+	if c.Cache == nil || etag == "" {
+		resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+		streamed = true
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := cachedResponse{ETag: etag, Link: resp.Header.Get("Link"), Body: body}
+	if err := c.Cache.Save(cacheKey, fresh); err != nil {
+		fmt.Printf("Warning: could not cache response: %v\n", err)
+	}
+	return fresh.response(), nil
+}
+
+// response rebuilds an HTTP response from the cached page
+func (r cachedResponse) response() *http.Response {
+	header := http.Header{}
+	if r.Link != "" {
+		header.Set("Link", r.Link)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(r.Body)),
+	}
+}
+
+// responseCacheKey maps a request URL to a cache key
+func responseCacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return "http/" + hex.EncodeToString(sum[:])
+}
+
+// withPerPage requests the maximum page size unless the endpoint already sets one
+func withPerPage(endpoint string) string {
+	if strings.Contains(endpoint, "per_page=") {
+		return endpoint
+	}
+	if strings.Contains(endpoint, "?") {
+		return endpoint + "&per_page=100"
+	}
+	return endpoint + "?per_page=100"
+}
+
+// nextPageURL extracts the rel="next" URL from a Link header
+func nextPageURL(link string) string {
+	match := linkNextPattern.FindStringSubmatch(link)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// APIFetcher fetches repository data from the GitHub REST API without the GitHub CLI
+type APIFetcher struct {
+	client *Client
+}
+
+// NewAPIFetcher creates a new APIFetcher using the given client
+func NewAPIFetcher(client *Client) *APIFetcher {
+	return &APIFetcher{client: client}
+}
+
+// PullRequests fetches pull requests for the given repository.
+// Date-bounded requests go through the search API, split into windows below the result cap.
+func (f *APIFetcher) PullRequests(repo models.Repository, since, until time.Time) ([]models.PullRequest, error) {
+	var rawPRs []apiPullRequest
+	var err error
+	if since.IsZero() {
+		err = listAll(f.client, fmt.Sprintf("/repos/%s/%s/pulls?state=all", repo.Owner, repo.Name), "pull requests", func(raw apiPullRequest) {
+			rawPRs = append(rawPRs, raw)
+		})
+	} else {
+		rawPRs, err = searchByWindow(newDateWindow(since, until), searchCap,
+			func(query string) ([]apiPullRequest, error) {
+				return searchItems[apiPullRequest](f.client, repo, "pr", query, "pull requests")
+			},
+			func(pr apiPullRequest) int { return pr.Number })
+	}
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, fmt.Errorf("could not fetch pull requests for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	var prs []models.PullRequest
+	for _, raw := range rawPRs {
+		prs = append(prs, raw.toModel())
+	}
+
+	fmt.Printf("Found %d pull requests for %s/%s\n", len(prs), repo.Owner, repo.Name)
+	return prs, err
+}
+
+// Commits fetches all commits for the given repository since the period start date
+func (f *APIFetcher) Commits(repo models.Repository, from time.Time, detailedStats bool) ([]models.Commit, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/commits?since=%s", repo.Owner, repo.Name, from.Format("2006-01-02"))
+	var commits []models.Commit
+	err := listAll(f.client, endpoint, "commits", func(raw apiCommit) {
+		commits = append(commits, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch commits for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	resolveCommitAuthors(commits)
+
+	fmt.Printf("Found %d commits for %s/%s\n", len(commits), repo.Owner, repo.Name)
+
+	if detailedStats {
+		return commits, applyCommitStats(f, repo, commits)
+	}
+	return commits, nil
+}
+
+// CommitStats fetches the line statistics of a single commit
+func (f *APIFetcher) CommitStats(repo models.Repository, sha string) (int, int, error) {
+	var detail apiCommit
+	endpoint := fmt.Sprintf("/repos/%s/%s/commits/%s", repo.Owner, repo.Name, sha)
+	if err := f.client.Get(endpoint, &detail); err != nil {
+		return 0, 0, fmt.Errorf("could not fetch stats for commit %s: %w", sha, err)
+	}
+	if detail.Stats == nil {
+		return 0, 0, nil
+	}
+	return detail.Stats.Additions, detail.Stats.Deletions, nil
+}
+
+// CompareCommits fetches the commits between base and head
+func (f *APIFetcher) CompareCommits(repo models.Repository, base, head string) ([]models.Commit, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/compare/%s...%s", repo.Owner, repo.Name, base, head)
+	var commits []models.Commit
+	err := listField(f.client, endpoint, "commits", "compared commits", func(raw apiCommit) {
+		commits = append(commits, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not compare %s...%s in %s/%s: %w", base, head, repo.Owner, repo.Name, err)
+	}
+	resolveCommitAuthors(commits)
+	return commits, nil
+}
+
+// PullRequestsUpdatedSince fetches pull requests updated on or after the given date
+func (f *APIFetcher) PullRequestsUpdatedSince(repo models.Repository, updated time.Time) ([]models.PullRequest, error) {
+	rawPRs, err := searchItems[apiPullRequest](f.client, repo, "pr", updatedQuery(updated), "pull requests")
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch pull requests for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	if len(rawPRs) >= searchCap {
+		return nil, errTooManyUpdates
+	}
+
+	var prs []models.PullRequest
+	for _, raw := range rawPRs {
+		prs = append(prs, raw.toModel())
+	}
+	return prs, nil
+}
+
+// IssuesUpdatedSince fetches issues updated on or after the given date
+func (f *APIFetcher) IssuesUpdatedSince(repo models.Repository, updated time.Time) ([]models.Issue, error) {
+	rawIssues, err := searchItems[apiIssue](f.client, repo, "issue", updatedQuery(updated), "issues")
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch issues for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	if len(rawIssues) >= searchCap {
+		return nil, errTooManyUpdates
+	}
+
+	var issues []models.Issue
+	for _, raw := range rawIssues {
+		issues = append(issues, raw.toModel())
+	}
+	return issues, nil
+}
+
+// Issues fetches issues for the given repository, excluding pull requests.
+// Date-bounded requests go through the search API, split into windows below the result cap.
+func (f *APIFetcher) Issues(repo models.Repository, since, until time.Time) ([]models.Issue, error) {
+	var rawIssues []apiIssue
+	var err error
+	if since.IsZero() {
+		err = listAll(f.client, fmt.Sprintf("/repos/%s/%s/issues?state=all", repo.Owner, repo.Name), "issues", func(raw apiIssue) {
+			rawIssues = append(rawIssues, raw)
+		})
+	} else {
+		rawIssues, err = searchByWindow(newDateWindow(since, until), searchCap,
+			func(query string) ([]apiIssue, error) {
+				return searchItems[apiIssue](f.client, repo, "issue", query, "issues")
+			},
+			func(issue apiIssue) int { return issue.Number })
+	}
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, fmt.Errorf("could not fetch issues for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	var issues []models.Issue
+	for _, raw := range rawIssues {
+		if raw.isPullRequest() {
+			continue
+		}
+		issues = append(issues, raw.toModel())
+	}
+
+	fmt.Printf("Found %d issues for %s/%s\n", len(issues), repo.Owner, repo.Name)
+	return issues, err
+}
+
+// Comments fetches the issue-level comments for the given PR or issue number.
+// PRs and plain issues share the same comments endpoint.
+func (f *APIFetcher) Comments(repo models.Repository, kind string, number int) ([]models.Comment, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", repo.Owner, repo.Name, number)
+	var comments []models.Comment
+	err := listAll(f.client, endpoint, "comments", func(raw apiComment) {
+		comments = append(comments, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch comments for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return comments, nil
+}
+
+// Reviews fetches the submitted reviews of a pull request
+func (f *APIFetcher) Reviews(repo models.Repository, number int) ([]models.Review, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", repo.Owner, repo.Name, number)
+	var reviews []models.Review
+	err := listAll(f.client, endpoint, "reviews", func(raw apiReview) {
+		if raw.State != "PENDING" {
+			reviews = append(reviews, raw.toModel())
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch reviews for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return reviews, nil
+}
+
+// ReviewRequests fetches the review requests of a pull request from its issue events
+func (f *APIFetcher) ReviewRequests(repo models.Repository, number int) ([]models.ReviewRequest, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/events", repo.Owner, repo.Name, number)
+	var requests []models.ReviewRequest
+	err := listAll(f.client, endpoint, "review requests", func(raw apiIssueEvent) {
+		if request, ok := raw.reviewRequest(); ok {
+			requests = append(requests, request)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch review requests for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return requests, nil
+}
+
+// Timeline fetches the timeline events of a pull request or issue
+func (f *APIFetcher) Timeline(repo models.Repository, kind string, number int) ([]models.TimelineEvent, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/timeline", repo.Owner, repo.Name, number)
+	var events []models.TimelineEvent
+	err := listAll(f.client, endpoint, "timeline events", func(raw apiTimelineEvent) {
+		if event, ok := raw.toModel(); ok {
+			events = append(events, event)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch timeline of #%d in %s/%s: %w", number, repo.Owner, repo.Name, err)
+	}
+	return events, nil
+}
+
+// ReviewComments fetches the inline comments on the diff of a pull request
+func (f *APIFetcher) ReviewComments(repo models.Repository, number int) ([]models.Comment, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/pulls/%d/comments", repo.Owner, repo.Name, number)
+	var comments []models.Comment
+	err := listAll(f.client, endpoint, "review comments", func(raw apiReviewComment) {
+		comments = append(comments, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch review comments for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return comments, nil
+}
+
+// Deployments fetches the deployments created between since and until with their statuses.
+// The API cannot filter deployments by date, so all of them are listed and filtered locally.
+func (f *APIFetcher) Deployments(repo models.Repository, since, until time.Time) ([]models.Deployment, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/deployments", repo.Owner, repo.Name)
+	var deployments []models.Deployment
+	err := listAll(f.client, endpoint, "deployments", func(raw apiDeployment) {
+		if inDateRange(raw.CreatedAt.Time, since, until) {
+			deployments = append(deployments, raw.toModel())
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch deployments for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	err = applyDeploymentStatuses(repo, deployments, func(id int) ([]apiDeploymentStatus, error) {
+		var statuses []apiDeploymentStatus
+		err := listAll(f.client, fmt.Sprintf("%s/%d/statuses", endpoint, id), "deployment statuses", func(raw apiDeploymentStatus) {
+			statuses = append(statuses, raw)
+		})
+		return statuses, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Found %d deployments for %s/%s\n", len(deployments), repo.Owner, repo.Name)
+	return deployments, nil
+}
+
+// Releases fetches all releases of a repository
+func (f *APIFetcher) Releases(repo models.Repository) ([]models.Release, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/releases", repo.Owner, repo.Name)
+	var releases []models.Release
+	err := listAll(f.client, endpoint, "releases", func(raw apiRelease) {
+		releases = append(releases, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch releases for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	fmt.Printf("Found %d releases for %s/%s\n", len(releases), repo.Owner, repo.Name)
+	return releases, nil
+}
+
+// Tags fetches all tags of a repository
+func (f *APIFetcher) Tags(repo models.Repository) ([]models.Tag, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/tags", repo.Owner, repo.Name)
+	var tags []models.Tag
+	err := listAll(f.client, endpoint, "tags", func(raw apiTag) {
+		tags = append(tags, raw.toModel())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch tags for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+	return tags, nil
+}
+
+// WorkflowRuns fetches the GitHub Actions runs created between since and until with their jobs
+func (f *APIFetcher) WorkflowRuns(repo models.Repository, since, until time.Time) ([]models.WorkflowRun, error) {
+	endpoint := fmt.Sprintf("/repos/%s/%s/actions/runs", repo.Owner, repo.Name)
+	rawRuns, err := workflowRuns(since, until, func(query string) ([]apiWorkflowRun, error) {
+		var runs []apiWorkflowRun
+		err := listField(f.client, endpoint+query, "workflow_runs", "workflow runs", func(raw apiWorkflowRun) {
+			runs = append(runs, raw)
+		})
+		return runs, err
+	})
+	if err != nil && !errors.Is(err, ErrIncomplete) {
+		return nil, fmt.Errorf("could not fetch workflow runs for %s/%s: %w", repo.Owner, repo.Name, err)
+	}
+
+	incomplete := err
+	var runs []models.WorkflowRun
+	for _, raw := range rawRuns {
+		if inDateRange(raw.CreatedAt.Time, since, until) {
+			runs = append(runs, raw.toModel())
+		}
+	}
+
+	err = applyWorkflowJobs(repo, runs, func(id int64) ([]apiWorkflowJob, error) {
+		var jobs []apiWorkflowJob
+		err := listField(f.client, fmt.Sprintf("%s/%d/jobs", endpoint, id), "jobs", "workflow jobs", func(raw apiWorkflowJob) {
+			jobs = append(jobs, raw)
+		})
+		return jobs, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Found %d workflow runs for %s/%s\n", len(runs), repo.Owner, repo.Name)
+	return runs, incomplete
+}
+
+// searchItems runs an issue search for items of the given kind matching the date qualifier
+func searchItems[T any](c *Client, repo models.Repository, kind, dateQuery, resourceType string) ([]T, error) {
+	query := fmt.Sprintf("repo:%s/%s is:%s %s", repo.Owner, repo.Name, kind, dateQuery)
+	var items []T
+	err := searchAll(c, "/search/issues?q="+url.QueryEscape(query), resourceType, func(item T) {
+		items = append(items, item)
+	})
+	return items, err
+}
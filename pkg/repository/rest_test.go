@@ -0,0 +1,240 @@
+package repository_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/cache"
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/repository/repositorytest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIFetcher_Commits_Paginates(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+	server.PageSize = 1
+
+	server.Handle("/repos/o/r/commits?since=2024-01-01", []map[string]any{
+		{
+			"sha":      "aaa",
+			"html_url": "https://github.com/o/r/commit/aaa",
+			"commit": map[string]any{
+				"message": "first",
+				"author":  map[string]any{"name": "alice", "date": "2024-01-02T00:00:00Z"},
+			},
+		},
+		{
+			"sha":      "bbb",
+			"html_url": "https://github.com/o/r/commit/bbb",
+			"commit": map[string]any{
+				"message": "second",
+				"author":  map[string]any{"name": "bob", "date": "2024-01-03T00:00:00Z"},
+			},
+		},
+	})
+	server.Handle("/repos/o/r/commits/aaa", map[string]any{"stats": map[string]any{"additions": 3, "deletions": 1}})
+	server.Handle("/repos/o/r/commits/bbb", map[string]any{"stats": map[string]any{"additions": 5, "deletions": 2}})
+
+	fetcher := repository.NewAPIFetcher(repository.NewClient(server.URL, "token"))
+	repo := models.Repository{Owner: "o", Name: "r"}
+
+	commits, err := fetcher.Commits(repo, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "alice", commits[0].Author)
+	assert.Equal(t, 3, commits[0].Additions)
+	assert.Equal(t, "bob", commits[1].Author)
+	assert.Equal(t, 2, commits[1].Deletions)
+}
+
+func TestAPIFetcher_Commits_ResolvesLogins(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+
+	commit := func(sha, name, email string, author any) map[string]any {
+		return map[string]any{
+			"sha":    sha,
+			"author": author,
+			"commit": map[string]any{
+				"author": map[string]any{"name": name, "email": email, "date": "2024-01-02T00:00:00Z"},
+			},
+		}
+	}
+	server.Handle("/repos/o/r/commits?since=2024-01-01", []map[string]any{
+		commit("aaa", "Kota Oue", "kota@example.com", map[string]any{"login": "kotaoue"}),
+		// Committed from another machine whose email is not linked to the account
+		commit("bbb", "Kota Oue", "KOTA@example.com", nil),
+		commit("ccc", "Bob", "12345+bobby@users.noreply.github.com", nil),
+		commit("ddd", "Carol", "carol@example.com", nil),
+	})
+
+	fetcher := repository.NewAPIFetcher(repository.NewClient(server.URL, ""))
+	commits, err := fetcher.Commits(models.Repository{Owner: "o", Name: "r"}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false)
+	assert.NoError(t, err)
+
+	authors := make([]string, len(commits))
+	for i, c := range commits {
+		authors[i] = c.Author
+	}
+	assert.Equal(t, []string{"kotaoue", "kotaoue", "bobby", "Carol"}, authors)
+	assert.Equal(t, "Kota Oue", commits[0].AuthorName)
+	assert.Equal(t, "kota@example.com", commits[0].AuthorEmail)
+}
+
+func TestAPIFetcher_PullRequests_Search(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+
+	server.Handle("/search/issues", map[string]any{
+		"total_count": 1,
+		"items": []map[string]any{
+			{
+				"number":       7,
+				"title":        "Add feature",
+				"state":        "closed",
+				"html_url":     "https://github.com/o/r/pull/7",
+				"created_at":   "2024-01-02T00:00:00Z",
+				"closed_at":    "2024-01-03T00:00:00Z",
+				"user":         map[string]any{"login": "alice"},
+				"pull_request": map[string]any{"merged_at": "2024-01-03T00:00:00Z"},
+			},
+		},
+	})
+
+	fetcher := repository.NewAPIFetcher(repository.NewClient(server.URL, ""))
+	repo := models.Repository{Owner: "o", Name: "r"}
+
+	prs, err := fetcher.PullRequests(repo, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, prs, 1)
+	assert.Equal(t, 7, prs[0].Number)
+	assert.Equal(t, "alice", prs[0].Author)
+	assert.NotNil(t, prs[0].MergedAt)
+}
+
+func TestAPIFetcher_Issues_SkipsPullRequests(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+
+	server.Handle("/repos/o/r/issues?state=all", []map[string]any{
+		{"number": 1, "title": "Bug", "state": "open", "user": map[string]any{"login": "bob"}},
+		{"number": 2, "title": "PR", "state": "open", "pull_request": map[string]any{}},
+	})
+
+	fetcher := repository.NewAPIFetcher(repository.NewClient(server.URL, ""))
+	issues, err := fetcher.Issues(models.Repository{Owner: "o", Name: "r"}, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, issues, 1)
+	assert.Equal(t, "Bug", issues[0].Title)
+}
+
+func TestAPIFetcher_CompareCommits(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+
+	commit := func(sha string) map[string]any {
+		return map[string]any{"sha": sha, "author": map[string]any{"login": "alice"}, "commit": map[string]any{"author": map[string]any{"name": "Alice", "date": "2024-01-02T00:00:00Z"}}}
+	}
+	server.Handle("/repos/o/r/compare/aaa...ccc", map[string]any{
+		"status":        "ahead",
+		"total_commits": 2,
+		"commits":       []map[string]any{commit("bbb"), commit("ccc")},
+	})
+
+	fetcher := repository.NewAPIFetcher(repository.NewClient(server.URL, ""))
+	commits, err := fetcher.CompareCommits(models.Repository{Owner: "o", Name: "r"}, "aaa", "ccc")
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, "bbb", commits[0].SHA)
+	assert.Equal(t, "alice", commits[0].Author)
+}
+
+func TestAPIFetcher_Error(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+
+	fetcher := repository.NewAPIFetcher(repository.NewClient(server.URL, ""))
+	_, err := fetcher.Comments(models.Repository{Owner: "o", Name: "r"}, models.KindIssue, 1)
+	assert.Error(t, err)
+}
+
+func TestClient_RevalidatesWithETag(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+	server.Handle("/repos/o/r/issues/1/comments", []map[string]any{
+		{"body": "hello", "user": map[string]any{"login": "alice"}},
+	})
+
+	client := repository.NewClient(server.URL, "")
+	client.Cache = cache.NewStore(t.TempDir())
+	fetcher := repository.NewAPIFetcher(client)
+	repo := models.Repository{Owner: "o", Name: "r"}
+
+	first, err := fetcher.Comments(repo, models.KindIssue, 1)
+	assert.NoError(t, err)
+	second, err := fetcher.Comments(repo, models.KindIssue, 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, server.NotModified())
+}
+
+func TestClient_RetriesTransientErrors(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+	server.Handle("/repos/o/r/issues/1/comments", []map[string]any{
+		{"body": "hello", "user": map[string]any{"login": "alice"}},
+	})
+	server.Fail("/repos/o/r/issues/1/comments", http.StatusBadGateway, nil)
+	server.Fail("/repos/o/r/issues/1/comments", http.StatusForbidden, http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(time.Now().Unix(), 10)},
+	})
+
+	client := repository.NewClient(server.URL, "")
+	client.Retry.BaseDelay = time.Millisecond
+	fetcher := repository.NewAPIFetcher(client)
+
+	comments, err := fetcher.Comments(models.Repository{Owner: "o", Name: "r"}, models.KindIssue, 1)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Len(t, server.Requests(), 3)
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+	for i := 0; i < 3; i++ {
+		server.Fail("/repos/o/r/issues/1/comments", http.StatusServiceUnavailable, nil)
+	}
+
+	client := repository.NewClient(server.URL, "")
+	client.Retry = repository.RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, MaxWait: time.Second}
+	fetcher := repository.NewAPIFetcher(client)
+
+	_, err := fetcher.Comments(models.Repository{Owner: "o", Name: "r"}, models.KindIssue, 1)
+	assert.ErrorContains(t, err, "503")
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestAPIFetcher_Commits_IncompleteStats(t *testing.T) {
+	server := repositorytest.NewServer()
+	defer server.Close()
+	server.Handle("/repos/o/r/commits?since=2024-01-01", []map[string]any{
+		{"sha": "aaa", "commit": map[string]any{"author": map[string]any{"name": "alice", "date": "2024-01-02T00:00:00Z"}}},
+		{"sha": "bbb", "commit": map[string]any{"author": map[string]any{"name": "bob", "date": "2024-01-03T00:00:00Z"}}},
+	})
+	server.Handle("/repos/o/r/commits/aaa", map[string]any{"stats": map[string]any{"additions": 3, "deletions": 1}})
+
+	fetcher := repository.NewAPIFetcher(repository.NewClient(server.URL, ""))
+	commits, err := fetcher.Commits(models.Repository{Owner: "o", Name: "r"}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true)
+
+	assert.ErrorIs(t, err, repository.ErrIncomplete)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, 3, commits[0].Additions)
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrIncomplete is wrapped by errors returned alongside partial data,
+// for example when the line statistics of some commits could not be fetched
+var ErrIncomplete = errors.New("data incomplete")
+
+// RetryPolicy controls how transient failures and exhausted rate limits are retried
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseDelay is the first backoff delay; it doubles with every retry
+	BaseDelay time.Duration
+	// MaxDelay caps a single backoff delay
+	MaxDelay time.Duration
+	// MaxWait caps how long to pause for a rate limit reset before giving up
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy used by new clients and the gh backend
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  time.Second,
+	MaxDelay:   time.Minute,
+	MaxWait:    time.Hour,
+}
+
+// retryableError marks a failure worth retrying, optionally after a known delay
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// retry runs fn until it succeeds, fails permanently or the retries are exhausted
+func (p RetryPolicy) retry(fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || attempt >= p.MaxRetries {
+			return err
+		}
+
+		wait := retryable.after
+		if wait <= 0 {
+			wait = p.backoff(attempt)
+		}
+		if wait > p.MaxWait {
+			return fmt.Errorf("%w (retry would wait %s)", err, wait.Round(time.Second))
+		}
+
+		fmt.Printf("Warning: %v; retrying in %s\n", err, wait.Round(time.Millisecond))
+		time.Sleep(wait)
+	}
+}
+
+// backoff returns the exponential delay before the given retry
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// rateLimiter pauses requests once the primary rate limit budget is spent
+type rateLimiter struct {
+	mu      sync.Mutex
+	resetAt time.Time
+}
+
+// wait blocks until the rate limit resets, if the last response exhausted it
+func (l *rateLimiter) wait(maxWait time.Duration) {
+	l.mu.Lock()
+	wait := time.Until(l.resetAt)
+	l.mu.Unlock()
+
+	if wait <= 0 || wait > maxWait {
+		return
+	}
+	fmt.Printf("Rate limit exhausted, waiting %s until reset\n", wait.Round(time.Second))
+	time.Sleep(wait)
+}
+
+// observe records the remaining budget reported by a response
+func (l *rateLimiter) observe(header http.Header) {
+	if header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	if reset, ok := rateLimitReset(header); ok {
+		l.mu.Lock()
+		l.resetAt = reset
+		l.mu.Unlock()
+	}
+}
+
+// classifyResponse turns a failed response into an error, marking rate limit and
+// server errors as retryable
+func classifyResponse(resp *http.Response, message string) error {
+	err := fmt.Errorf("GET %s: %s", resp.Request.URL.Path, resp.Status)
+	if message != "" {
+		err = fmt.Errorf("%w: %s", err, message)
+	}
+
+	switch {
+	case resp.StatusCode >= 500:
+		return &retryableError{err: err}
+	case resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests:
+		return err
+	}
+
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil {
+		return &retryableError{err: err, after: time.Duration(seconds) * time.Second}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, ok := rateLimitReset(resp.Header); ok {
+			return &retryableError{err: err, after: time.Until(reset) + time.Second}
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || isSecondaryRateLimit(message) {
+		return &retryableError{err: err}
+	}
+	return err
+}
+
+// rateLimitReset parses the X-RateLimit-Reset header, a Unix timestamp
+func rateLimitReset(header http.Header) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// isSecondaryRateLimit reports whether an error message describes GitHub's secondary (abuse) rate limit
+func isSecondaryRateLimit(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse")
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// searchCap is the maximum number of results GitHub search returns for a single query
+const searchCap = 1000
+
+// errTooManyUpdates reports that an updated: search hit the result cap and cannot be trusted
+var errTooManyUpdates = fmt.Errorf("too many updated items: %w", errNoIncrementalSync)
+
+// updatedQuery returns the search qualifier for items updated on or after the given date in UTC
+func updatedQuery(updated time.Time) string {
+	return "updated:>=" + updated.UTC().Format("2006-01-02")
+}
+
+// dateWindow is an inclusive range of UTC calendar days used in a created: search qualifier,
+// which GitHub evaluates in UTC
+type dateWindow struct {
+	from time.Time
+	to   time.Time
+}
+
+// newDateWindow creates a window covering the UTC days from since to until. A zero until means today.
+func newDateWindow(since, until time.Time) dateWindow {
+	if until.IsZero() {
+		until = time.Now()
+	}
+	return dateWindow{from: utcDay(since), to: utcDay(until)}
+}
+
+// query returns the search qualifier for the window
+func (w dateWindow) query() string {
+	return fmt.Sprintf("created:%s..%s", w.from.Format("2006-01-02"), w.to.Format("2006-01-02"))
+}
+
+// days returns the number of calendar days covered by the window, counting dates so that
+// daylight saving transitions do not shorten it
+func (w dateWindow) days() int {
+	from := time.Date(w.from.Year(), w.from.Month(), w.from.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(w.to.Year(), w.to.Month(), w.to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from)/(24*time.Hour)) + 1
+}
+
+// split bisects the window into two non-overlapping halves
+func (w dateWindow) split() (dateWindow, dateWindow) {
+	mid := w.from.AddDate(0, 0, w.days()/2-1)
+	return dateWindow{from: w.from, to: mid}, dateWindow{from: mid.AddDate(0, 0, 1), to: w.to}
+}
+
+// searchByWindow runs fetch for the window and recursively bisects any window whose
+// results reach limit, so every slice is complete. Results are merged in window order
+// and de-duplicated by number. When a single day still reaches limit, the results are
+// returned with an error wrapping ErrIncomplete.
+func searchByWindow[T any](w dateWindow, limit int, fetch func(query string) ([]T, error), number func(T) int) ([]T, error) {
+	var results []T
+	seen := make(map[int]bool)
+	var capped []string
+
+	var run func(w dateWindow) error
+	run = func(w dateWindow) error {
+		items, err := fetch(w.query())
+		if err != nil {
+			return err
+		}
+
+		if len(items) >= limit {
+			if w.days() > 1 {
+				left, right := w.split()
+				if err := run(left); err != nil {
+					return err
+				}
+				return run(right)
+			}
+			capped = append(capped, w.from.Format("2006-01-02"))
+		}
+
+		for _, item := range items {
+			if seen[number(item)] {
+				continue
+			}
+			seen[number(item)] = true
+			results = append(results, item)
+		}
+		return nil
+	}
+
+	if err := run(w); err != nil {
+		return nil, err
+	}
+	if len(capped) > 0 {
+		return results, fmt.Errorf("%w: more than %d results created on %s; some may be missing",
+			ErrIncomplete, limit, strings.Join(capped, ", "))
+	}
+	return results, nil
+}
+
+// utcDay returns the start of the UTC calendar day containing t
+func utcDay(t time.Time) time.Time {
+	return truncateDay(t.UTC())
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
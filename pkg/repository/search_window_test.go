@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateWindow_Query(t *testing.T) {
+	since := time.Date(2024, 4, 1, 15, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	w := newDateWindow(since, until)
+	assert.Equal(t, "created:2024-04-01..2025-03-31", w.query())
+	assert.Equal(t, 365, w.days())
+
+	left, right := w.split()
+	assert.Equal(t, "created:2024-04-01..2024-09-29", left.query())
+	assert.Equal(t, "created:2024-09-30..2025-03-31", right.query())
+}
+
+func TestSearchByWindow_BisectsCappedWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// One item per day for ten days; the last day is returned twice to exercise de-duplication
+	var queries []string
+	fetch := func(query string) ([]int, error) {
+		queries = append(queries, query)
+		from, to, _ := strings.Cut(strings.TrimPrefix(query, "created:"), "..")
+
+		var items []int
+		for day := 0; day < 10; day++ {
+			date := start.AddDate(0, 0, day).Format("2006-01-02")
+			if date >= from && date <= to {
+				items = append(items, day+1)
+			}
+		}
+		if to == "2024-01-10" {
+			items = append(items, 10)
+		}
+		return items, nil
+	}
+
+	items, err := searchByWindow(newDateWindow(start, start.AddDate(0, 0, 9)), 4, fetch, func(n int) int { return n })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, items)
+	assert.Equal(t, "created:2024-01-01..2024-01-10", queries[0])
+	assert.Greater(t, len(queries), 1)
+}
+
+func TestSearchByWindow_CappedDayIsIncomplete(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// The second day alone reaches the limit, so it cannot be split any further
+	fetch := func(query string) ([]int, error) {
+		from, to, _ := strings.Cut(strings.TrimPrefix(query, "created:"), "..")
+		var items []int
+		if from <= "2024-01-01" && to >= "2024-01-01" {
+			items = append(items, 1)
+		}
+		if from <= "2024-01-02" && to >= "2024-01-02" {
+			items = append(items, 2, 3, 4)
+		}
+		return items, nil
+	}
+
+	items, err := searchByWindow(newDateWindow(start, start.AddDate(0, 0, 1)), 3, fetch, func(n int) int { return n })
+	assert.True(t, errors.Is(err, ErrIncomplete))
+	assert.Contains(t, err.Error(), "2024-01-02")
+	assert.Equal(t, []int{1, 2, 3, 4}, items)
+}
+
+func TestDateWindow_DaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+
+	// Clocks go forward on 2024-03-10, so the window spans only 47 hours of local time
+	w := dateWindow{from: time.Date(2024, 3, 9, 0, 0, 0, 0, newYork), to: time.Date(2024, 3, 11, 0, 0, 0, 0, newYork)}
+	assert.Equal(t, 3, w.days())
+
+	// Local times are searched by the UTC days they fall on
+	since := time.Date(2024, 3, 9, 21, 0, 0, 0, newYork)
+	until := time.Date(2024, 3, 11, 22, 0, 0, 0, newYork)
+	w = newDateWindow(since, until)
+	assert.Equal(t, "created:2024-03-10..2024-03-12", w.query())
+	assert.Equal(t, 3, w.days())
+
+	left, right := w.split()
+	assert.Equal(t, "created:2024-03-10..2024-03-10", left.query())
+	assert.Equal(t, "created:2024-03-11..2024-03-12", right.query())
+}
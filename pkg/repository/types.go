@@ -0,0 +1,530 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"yokiyoki/pkg/models"
+)
+
+// timestamp decodes API timestamps, treating null and empty strings as the zero time
+type timestamp struct {
+	time.Time
+}
+
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil || *s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// ptr returns nil for the zero time, otherwise a pointer to the time
+func (t timestamp) ptr() *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
+
+type apiUser struct {
+	Login string `json:"login"`
+	// Type is "Bot" for GitHub App accounts
+	Type string `json:"type"`
+	// IsBot marks GitHub App accounts in gh list output, whose logins take the form app/<name>
+	IsBot bool `json:"is_bot"`
+}
+
+type apiLabel struct {
+	Name string `json:"name"`
+}
+
+func labelNames(labels []apiLabel) []string {
+	var names []string
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+// apiPullRequest is a REST API pull request or an issue search result item
+type apiPullRequest struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	User        *apiUser  `json:"user"`
+	CreatedAt   timestamp `json:"created_at"`
+	MergedAt    timestamp `json:"merged_at"`
+	ClosedAt    timestamp `json:"closed_at"`
+	Additions   int       `json:"additions"`
+	Deletions   int       `json:"deletions"`
+	MergeCommit string    `json:"merge_commit_sha"`
+	PullRequest *struct {
+		// Search results carry the merge time on the nested pull_request object
+		MergedAt timestamp `json:"merged_at"`
+	} `json:"pull_request"`
+}
+
+func (p apiPullRequest) toModel() models.PullRequest {
+	mergedAt := p.MergedAt.ptr()
+	if mergedAt == nil && p.PullRequest != nil {
+		mergedAt = p.PullRequest.MergedAt.ptr()
+	}
+	// GitHub also reports the test merge commit of open pull requests
+	mergeCommit := ""
+	if mergedAt != nil {
+		mergeCommit = p.MergeCommit
+	}
+
+	return models.PullRequest{
+		Number:    p.Number,
+		Title:     p.Title,
+		State:     p.State,
+		Author:    login(p.User),
+		Body:      p.Body,
+		URL:       p.HTMLURL,
+		CreatedAt: p.CreatedAt.Time,
+		MergedAt:  mergedAt,
+		ClosedAt:  p.ClosedAt.ptr(),
+		Additions: p.Additions,
+		Deletions: p.Deletions,
+
+		MergeCommitSHA: mergeCommit,
+	}
+}
+
+// apiIssue is a REST API issue. PullRequest is set when the item is actually a pull request.
+type apiIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	State       string          `json:"state"`
+	Body        string          `json:"body"`
+	HTMLURL     string          `json:"html_url"`
+	User        *apiUser        `json:"user"`
+	CreatedAt   timestamp       `json:"created_at"`
+	ClosedAt    timestamp       `json:"closed_at"`
+	Labels      []apiLabel      `json:"labels"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+func (i apiIssue) isPullRequest() bool {
+	return i.PullRequest != nil
+}
+
+func (i apiIssue) toModel() models.Issue {
+	return models.Issue{
+		Number:    i.Number,
+		Title:     i.Title,
+		State:     i.State,
+		Author:    login(i.User),
+		Body:      i.Body,
+		URL:       i.HTMLURL,
+		CreatedAt: i.CreatedAt.Time,
+		ClosedAt:  i.ClosedAt.ptr(),
+		Labels:    labelNames(i.Labels),
+	}
+}
+
+type apiCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string    `json:"name"`
+			Email string    `json:"email"`
+			Date  timestamp `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	// Author is the account matched to the git author's email, or null when none matched
+	Author *apiUser        `json:"author"`
+	Stats  *apiCommitStats `json:"stats"`
+}
+
+type apiCommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// toModel converts the commit, leaving Author empty when no account matched the git author;
+// resolveCommitAuthors fills it in afterwards
+func (c apiCommit) toModel() models.Commit {
+	return models.Commit{
+		SHA:         c.SHA,
+		Message:     c.Commit.Message,
+		URL:         c.HTMLURL,
+		Author:      login(c.Author),
+		AuthorName:  c.Commit.Author.Name,
+		AuthorEmail: c.Commit.Author.Email,
+		CoAuthors:   models.ParseCoAuthors(c.Commit.Message),
+		Date:        c.Commit.Author.Date.Time,
+	}
+}
+
+type apiComment struct {
+	Body      string    `json:"body"`
+	HTMLURL   string    `json:"html_url"`
+	User      *apiUser  `json:"user"`
+	CreatedAt timestamp `json:"created_at"`
+}
+
+func (c apiComment) toModel() models.Comment {
+	return models.Comment{
+		Author:    login(c.User),
+		Body:      c.Body,
+		URL:       c.HTMLURL,
+		CreatedAt: c.CreatedAt.Time,
+	}
+}
+
+type apiReview struct {
+	ID          int64     `json:"id"`
+	User        *apiUser  `json:"user"`
+	State       string    `json:"state"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	SubmittedAt timestamp `json:"submitted_at"`
+}
+
+func (r apiReview) toModel() models.Review {
+	return models.Review{
+		Reviewer:    login(r.User),
+		State:       r.State,
+		SubmittedAt: r.SubmittedAt.Time,
+		Body:        r.Body,
+		URL:         r.HTMLURL,
+	}
+}
+
+// apiReviewComment is an inline comment on a pull request diff
+type apiReviewComment struct {
+	ID           int64     `json:"id"`
+	Body         string    `json:"body"`
+	HTMLURL      string    `json:"html_url"`
+	User         *apiUser  `json:"user"`
+	CreatedAt    timestamp `json:"created_at"`
+	Path         string    `json:"path"`
+	Line         *int      `json:"line"`
+	OriginalLine *int      `json:"original_line"`
+	DiffHunk     string    `json:"diff_hunk"`
+	InReplyToID  int64     `json:"in_reply_to_id"`
+}
+
+func (c apiReviewComment) toModel() models.Comment {
+	// line is null once the commented code is outdated
+	line := c.Line
+	if line == nil {
+		line = c.OriginalLine
+	}
+	comment := models.Comment{
+		SubType:   models.CommentReviewComment,
+		ID:        c.ID,
+		Author:    login(c.User),
+		Body:      c.Body,
+		URL:       c.HTMLURL,
+		CreatedAt: c.CreatedAt.Time,
+		Path:      c.Path,
+		DiffHunk:  c.DiffHunk,
+		InReplyTo: c.InReplyToID,
+	}
+	if line != nil {
+		comment.Line = *line
+	}
+	return comment
+}
+
+// apiDeployment is an item of the deployments endpoint
+type apiDeployment struct {
+	ID          int       `json:"id"`
+	SHA         string    `json:"sha"`
+	Environment string    `json:"environment"`
+	CreatedAt   timestamp `json:"created_at"`
+	UpdatedAt   timestamp `json:"updated_at"`
+}
+
+func (d apiDeployment) toModel() models.Deployment {
+	return models.Deployment{
+		ID:          d.ID,
+		Environment: d.Environment,
+		SHA:         d.SHA,
+		CreatedAt:   d.CreatedAt.Time,
+		UpdatedAt:   d.UpdatedAt.Time,
+	}
+}
+
+// apiDeploymentStatus is an item of the statuses endpoint of a deployment
+type apiDeploymentStatus struct {
+	State     string    `json:"state"`
+	CreatedAt timestamp `json:"created_at"`
+}
+
+type apiRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	HTMLURL     string    `json:"html_url"`
+	CreatedAt   timestamp `json:"created_at"`
+	PublishedAt timestamp `json:"published_at"`
+}
+
+func (r apiRelease) toModel() models.Release {
+	return models.Release{
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		CreatedAt:   r.CreatedAt.Time,
+		PublishedAt: r.PublishedAt.Time,
+		URL:         r.HTMLURL,
+	}
+}
+
+type apiTag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+func (t apiTag) toModel() models.Tag {
+	return models.Tag{Name: t.Name, SHA: t.Commit.SHA}
+}
+
+type apiWorkflowRun struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	HeadBranch   string    `json:"head_branch"`
+	Event        string    `json:"event"`
+	Status       string    `json:"status"`
+	Conclusion   string    `json:"conclusion"`
+	RunAttempt   int       `json:"run_attempt"`
+	HTMLURL      string    `json:"html_url"`
+	CreatedAt    timestamp `json:"created_at"`
+	RunStartedAt timestamp `json:"run_started_at"`
+	UpdatedAt    timestamp `json:"updated_at"`
+}
+
+func (r apiWorkflowRun) toModel() models.WorkflowRun {
+	attempt := r.RunAttempt
+	if attempt == 0 {
+		attempt = 1
+	}
+	return models.WorkflowRun{
+		ID:         r.ID,
+		Workflow:   r.Name,
+		Branch:     r.HeadBranch,
+		Event:      r.Event,
+		Status:     r.Status,
+		Conclusion: r.Conclusion,
+		Attempt:    attempt,
+		CreatedAt:  r.CreatedAt.Time,
+		StartedAt:  r.RunStartedAt.Time,
+		UpdatedAt:  r.UpdatedAt.Time,
+		URL:        r.HTMLURL,
+	}
+}
+
+type apiWorkflowJob struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Conclusion  string    `json:"conclusion"`
+	CreatedAt   timestamp `json:"created_at"`
+	StartedAt   timestamp `json:"started_at"`
+	CompletedAt timestamp `json:"completed_at"`
+}
+
+func (j apiWorkflowJob) toModel() models.WorkflowJob {
+	return models.WorkflowJob{
+		ID:          j.ID,
+		Name:        j.Name,
+		Conclusion:  j.Conclusion,
+		CreatedAt:   j.CreatedAt.Time,
+		StartedAt:   j.StartedAt.Time,
+		CompletedAt: j.CompletedAt.Time,
+	}
+}
+
+// apiIssueEvent is an item of the issue events endpoint
+type apiIssueEvent struct {
+	Event             string    `json:"event"`
+	CreatedAt         timestamp `json:"created_at"`
+	RequestedReviewer *apiUser  `json:"requested_reviewer"`
+	RequestedTeam     *struct {
+		Slug string `json:"slug"`
+	} `json:"requested_team"`
+}
+
+// reviewRequest converts a review_requested event, reporting false for other events
+func (e apiIssueEvent) reviewRequest() (models.ReviewRequest, bool) {
+	if e.Event != "review_requested" {
+		return models.ReviewRequest{}, false
+	}
+	reviewer := login(e.RequestedReviewer)
+	if reviewer == "" && e.RequestedTeam != nil {
+		reviewer = e.RequestedTeam.Slug
+	}
+	return models.ReviewRequest{Reviewer: reviewer, RequestedAt: e.CreatedAt.Time}, true
+}
+
+// apiTimelineEvent is an item of the issue timeline endpoint. Comments and reviews name their
+// author in user rather than actor, and reviews are dated by submitted_at.
+type apiTimelineEvent struct {
+	Event       string    `json:"event"`
+	Actor       *apiUser  `json:"actor"`
+	User        *apiUser  `json:"user"`
+	CreatedAt   timestamp `json:"created_at"`
+	SubmittedAt timestamp `json:"submitted_at"`
+	Label       *apiLabel `json:"label"`
+	Assignee    *apiUser  `json:"assignee"`
+	Source      *struct {
+		Issue *struct {
+			Number     int `json:"number"`
+			Repository *struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		} `json:"issue"`
+	} `json:"source"`
+}
+
+// timelineEvents lists the timeline event types converted by toModel
+var timelineEvents = map[string]bool{
+	models.EventCommented:       true,
+	models.EventReviewed:        true,
+	models.EventLabeled:         true,
+	models.EventUnlabeled:       true,
+	models.EventAssigned:        true,
+	models.EventUnassigned:      true,
+	models.EventClosed:          true,
+	models.EventReopened:        true,
+	models.EventCrossReferenced: true,
+}
+
+// toModel converts the event, reporting false for event types that are not tracked
+func (e apiTimelineEvent) toModel() (models.TimelineEvent, bool) {
+	if !timelineEvents[e.Event] {
+		return models.TimelineEvent{}, false
+	}
+
+	event := models.TimelineEvent{Event: e.Event, Actor: login(e.Actor), CreatedAt: e.CreatedAt.Time}
+	if event.Actor == "" {
+		event.Actor = login(e.User)
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = e.SubmittedAt.Time
+	}
+	if e.Label != nil {
+		event.Label = e.Label.Name
+	}
+	event.Assignee = login(e.Assignee)
+	if e.Source != nil && e.Source.Issue != nil {
+		event.Source = fmt.Sprintf("#%d", e.Source.Issue.Number)
+		if e.Source.Issue.Repository != nil {
+			event.Source = e.Source.Issue.Repository.FullName + event.Source
+		}
+	}
+	return event, true
+}
+
+// ghListPullRequest is an item of `gh pr list --json` output
+type ghListPullRequest struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	State       string    `json:"state"`
+	URL         string    `json:"url"`
+	Author      *apiUser  `json:"author"`
+	CreatedAt   timestamp `json:"createdAt"`
+	MergedAt    timestamp `json:"mergedAt"`
+	ClosedAt    timestamp `json:"closedAt"`
+	Additions   int       `json:"additions"`
+	Deletions   int       `json:"deletions"`
+	Body        string    `json:"body"`
+	MergeCommit *struct {
+		OID string `json:"oid"`
+	} `json:"mergeCommit"`
+}
+
+func (p ghListPullRequest) toModel() models.PullRequest {
+	mergeCommit := ""
+	if p.MergeCommit != nil {
+		mergeCommit = p.MergeCommit.OID
+	}
+
+	return models.PullRequest{
+		Number:    p.Number,
+		Title:     p.Title,
+		State:     p.State,
+		Author:    login(p.Author),
+		Body:      p.Body,
+		URL:       p.URL,
+		CreatedAt: p.CreatedAt.Time,
+		MergedAt:  p.MergedAt.ptr(),
+		ClosedAt:  p.ClosedAt.ptr(),
+		Additions: p.Additions,
+		Deletions: p.Deletions,
+
+		MergeCommitSHA: mergeCommit,
+	}
+}
+
+// ghListIssue is an item of `gh issue list --json` output
+type ghListIssue struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	URL       string     `json:"url"`
+	Author    *apiUser   `json:"author"`
+	CreatedAt timestamp  `json:"createdAt"`
+	ClosedAt  timestamp  `json:"closedAt"`
+	Labels    []apiLabel `json:"labels"`
+	Body      string     `json:"body"`
+}
+
+func (i ghListIssue) toModel() models.Issue {
+	return models.Issue{
+		Number:    i.Number,
+		Title:     i.Title,
+		State:     i.State,
+		Author:    login(i.Author),
+		Body:      i.Body,
+		URL:       i.URL,
+		CreatedAt: i.CreatedAt.Time,
+		ClosedAt:  i.ClosedAt.ptr(),
+		Labels:    labelNames(i.Labels),
+	}
+}
+
+// login returns the login of user, marking bot accounts with the [bot] suffix when it is missing
+func login(user *apiUser) string {
+	if user == nil {
+		return ""
+	}
+	if user.IsBot {
+		return botLogin(strings.TrimPrefix(user.Login, "app/"), true)
+	}
+	return botLogin(user.Login, user.Type == "Bot")
+}
+
+// botLogin appends the [bot] suffix to the logins of bots that lack it, so bots can be told from people
+func botLogin(login string, bot bool) string {
+	if bot && login != "" && !strings.HasSuffix(strings.ToLower(login), models.BotSuffix) {
+		return login + models.BotSuffix
+	}
+	return login
+}
@@ -0,0 +1,77 @@
+package services
+
+import "yokiyoki/pkg/models"
+
+// Bot policies deciding how activity of bot accounts is reported
+const (
+	// BotsInclude reports bots like people
+	BotsInclude = "include"
+	// BotsExclude leaves bot activity out
+	BotsExclude = "exclude"
+	// BotsOnly reports bot activity alone
+	BotsOnly = "only"
+	// BotsGroup reports the activity of all bots under one automation user
+	BotsGroup = "group"
+)
+
+// BotOptions represents how bot accounts are detected and reported
+type BotOptions struct {
+	Bots models.Bots
+	// Policy is one of the Bots* policies; empty means BotsInclude
+	Policy string
+}
+
+// keep reports whether the activity of user is reported under the policy
+func (o BotOptions) keep(user string) bool {
+	switch o.Policy {
+	case BotsExclude:
+		return !o.Bots.IsBot(user)
+	case BotsOnly:
+		return o.Bots.IsBot(user)
+	default:
+		return true
+	}
+}
+
+// filterBots returns the items whose author is kept under the policy
+func filterBots[T any](items []T, opts BotOptions, author func(T) string) []T {
+	if opts.Policy != BotsExclude && opts.Policy != BotsOnly {
+		return items
+	}
+
+	var kept []T
+	for _, item := range items {
+		if opts.keep(author(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// groupBotAuthors reports the items of bots under the automation user when bots are grouped
+func groupBotAuthors[T any](items []T, opts BotOptions, author func(*T) *string) []T {
+	if opts.Policy != BotsGroup {
+		return items
+	}
+	for i := range items {
+		if name := author(&items[i]); opts.Bots.IsBot(*name) {
+			*name = models.AutomationUser
+		}
+	}
+	return items
+}
+
+// applyBotPolicy drops the users that are not reported under the policy from items grouped by
+// user, or merges bot users into the automation user when bots are grouped
+func applyBotPolicy[T any](byUser map[string][]T, opts BotOptions) map[string][]T {
+	for user, items := range byUser {
+		switch {
+		case !opts.keep(user):
+			delete(byUser, user)
+		case opts.Policy == BotsGroup && user != models.AutomationUser && opts.Bots.IsBot(user):
+			byUser[models.AutomationUser] = append(byUser[models.AutomationUser], items...)
+			delete(byUser, user)
+		}
+	}
+	return byUser
+}
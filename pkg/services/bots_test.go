@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotPolicies(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	at := chronometer.StartTime().Add(24 * time.Hour).Format(time.RFC3339)
+	commit := func(sha, login, userType string) map[string]any {
+		return map[string]any{
+			"sha":    sha,
+			"author": map[string]any{"login": login, "type": userType},
+			"commit": map[string]any{"author": map[string]any{"name": login, "date": at}},
+		}
+	}
+	pr := func(number int, login string) map[string]any {
+		return map[string]any{
+			"number":     float64(number),
+			"state":      "open",
+			"created_at": at,
+			"body":       "Bump dependency",
+			"user":       map[string]any{"login": login},
+		}
+	}
+
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch resourceType {
+		case "commits":
+			return []map[string]any{
+				commit("a", "alice", "User"),
+				commit("b", "dependabot[bot]", "Bot"),
+				// A GitHub App account whose login lacks the [bot] suffix
+				commit("c", "copilot", "Bot"),
+			}, nil
+		case "pull requests":
+			return []map[string]any{pr(1, "alice"), pr(2, "renovate"), pr(3, "dependabot[bot]")}, nil
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+
+	repo := models.Repository{Owner: "test-owner", Name: "test-repo"}
+	bots := func(policy string) services.BotOptions {
+		return services.BotOptions{Bots: models.NewBots([]string{"renovate"}), Policy: policy}
+	}
+	users := func(metrics []models.Metrics) map[string][2]int {
+		result := make(map[string][2]int)
+		for _, m := range metrics {
+			result[m.User] = [2]int{int(m.Commits), m.PRsCreated}
+		}
+		return result
+	}
+
+	t.Run("group", func(t *testing.T) {
+		metrics := services.Execute(repo, services.MetricsOptions{Period: chronometer, ByUser: true, Bots: bots(services.BotsGroup)})
+		assert.Equal(t, map[string][2]int{"alice": {1, 1}, models.AutomationUser: {2, 2}}, users(metrics))
+
+		commits := services.ExecuteCommits(repo, services.CommitsOptions{Period: chronometer, Bots: bots(services.BotsGroup)})
+		authors := make([]string, len(commits))
+		for i, c := range commits {
+			authors[i] = c.Author
+		}
+		assert.Equal(t, []string{"alice", models.AutomationUser, models.AutomationUser}, authors)
+
+		comments := services.ExecuteConversations(repo, services.ConversationsOptions{Period: chronometer, Concurrency: 1, Bots: bots(services.BotsGroup)})
+		authors = make([]string, len(comments))
+		for i, c := range comments {
+			authors[i] = c.Author
+		}
+		assert.ElementsMatch(t, []string{"alice", models.AutomationUser, models.AutomationUser}, authors)
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		metrics := services.Execute(repo, services.MetricsOptions{Period: chronometer, ByUser: true, Bots: bots(services.BotsExclude)})
+		assert.Equal(t, map[string][2]int{"alice": {1, 1}}, users(metrics))
+
+		metrics = services.Execute(repo, services.MetricsOptions{Period: chronometer, Bots: bots(services.BotsExclude)})
+		assert.Equal(t, 1.0, metrics[0].Commits)
+		assert.Equal(t, 1, metrics[0].PRsCreated)
+	})
+
+	t.Run("only", func(t *testing.T) {
+		commits := services.ExecuteCommits(repo, services.CommitsOptions{Period: chronometer, Bots: bots(services.BotsOnly)})
+		authors := make([]string, len(commits))
+		for i, c := range commits {
+			authors[i] = c.Author
+		}
+		assert.Equal(t, []string{"dependabot[bot]", "copilot[bot]"}, authors)
+
+		comments := services.ExecuteConversations(repo, services.ConversationsOptions{Period: chronometer, Concurrency: 1, Bots: bots(services.BotsOnly)})
+		assert.Len(t, comments, 2)
+		for _, c := range comments {
+			assert.NotEqual(t, "alice", c.Author)
+		}
+	})
+}
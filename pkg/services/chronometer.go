@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -11,6 +12,26 @@ type Chronometer struct {
 	jst   *time.Location
 }
 
+// Bucket sizes for splitting a period into consecutive intervals
+const (
+	BucketWeek    = "week"
+	BucketMonth   = "month"
+	BucketQuarter = "quarter"
+)
+
+// Bucket is one interval of a period split by Chronometer.Buckets
+type Bucket struct {
+	// Label names the interval, e.g. "2024-W05", "2024-02" or "2024-Q1"
+	Label  string
+	Period *Chronometer
+}
+
+// Periods to compare against, besides an explicit "YYYY-MM-DD..YYYY-MM-DD" range
+const (
+	ComparePrevious     = "previous"
+	CompareSameLastYear = "same-last-year"
+)
+
 type ChronometerOption struct {
 	Days      *int
 	StartDate *string
@@ -37,13 +58,21 @@ func createChronometer(opt ChronometerOption) (*Chronometer, error) {
 	return nil, fmt.Errorf("invalid chronometer options")
 }
 
+// jstLocation returns the Asia/Tokyo time zone, falling back to a fixed offset without tzdata
+func jstLocation() *time.Location {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		return time.FixedZone("JST", 9*60*60)
+	}
+	return jst
+}
+
 func createByDefault() (*Chronometer, error) {
-	jst, _ := time.LoadLocation("Asia/Tokyo")
-	return &Chronometer{jst: jst}, nil
+	return &Chronometer{jst: jstLocation()}, nil
 }
 
 func createByDateRange(startDate, endDate string) (*Chronometer, error) {
-	jst, _ := time.LoadLocation("Asia/Tokyo")
+	jst := jstLocation()
 
 	start, err := time.Parse("2006-01-02", startDate)
 	if err != nil {
@@ -66,7 +95,7 @@ func createByDateRange(startDate, endDate string) (*Chronometer, error) {
 }
 
 func createByDays(days int) (*Chronometer, error) {
-	jst, _ := time.LoadLocation("Asia/Tokyo")
+	jst := jstLocation()
 	end := time.Now().In(jst)
 	start := end.AddDate(0, 0, -days)
 	return &Chronometer{
@@ -89,6 +118,82 @@ func (c *Chronometer) EndTime() time.Time {
 	return c.End
 }
 
+// Comparison returns the period to compare this one against: ComparePrevious is the period of the
+// same length ending right before this one, CompareSameLastYear the same dates a year earlier, and
+// anything else is read as a "YYYY-MM-DD..YYYY-MM-DD" range of days like --start and --end.
+func (c *Chronometer) Comparison(spec string) (*Chronometer, error) {
+	switch spec {
+	case ComparePrevious:
+		end := c.Start.Add(-time.Second)
+		return &Chronometer{Start: end.Add(-c.End.Sub(c.Start)), End: end, jst: c.jst}, nil
+	case CompareSameLastYear:
+		return &Chronometer{Start: c.Start.AddDate(-1, 0, 0), End: c.End.AddDate(-1, 0, 0), jst: c.jst}, nil
+	}
+
+	startDate, endDate, ok := strings.Cut(spec, "..")
+	if !ok {
+		return nil, fmt.Errorf("invalid comparison %q: must be %s, %s or YYYY-MM-DD..YYYY-MM-DD", spec, ComparePrevious, CompareSameLastYear)
+	}
+	return createByDateRange(startDate, endDate)
+}
+
+// Buckets splits the period into consecutive ISO weeks (starting on Monday), calendar months or
+// quarters, which start at midnight JST. The first and last buckets are cut to the period.
+func (c *Chronometer) Buckets(size string) ([]Bucket, error) {
+	jst := c.jst
+	if jst == nil {
+		jst = jstLocation()
+	}
+
+	var buckets []Bucket
+	for start := c.Start; !start.After(c.End); {
+		first, label, err := bucketStart(start.In(jst), size)
+		if err != nil {
+			return nil, err
+		}
+		next := nextBucket(first, size)
+		end := next.Add(-time.Nanosecond)
+		if end.After(c.End) {
+			end = c.End
+		}
+		buckets = append(buckets, Bucket{Label: label, Period: &Chronometer{Start: start, End: end, jst: jst}})
+		start = next
+	}
+	return buckets, nil
+}
+
+// bucketStart returns the start and label of the bucket containing t
+func bucketStart(t time.Time, size string) (time.Time, string, error) {
+	switch size {
+	case BucketWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+		monday := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+		year, week := monday.ISOWeek()
+		return monday, fmt.Sprintf("%d-W%02d", year, week), nil
+	case BucketMonth:
+		first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		return first, first.Format("2006-01"), nil
+	case BucketQuarter:
+		quarter := (int(t.Month()) - 1) / 3
+		first := time.Date(t.Year(), time.Month(quarter*3+1), 1, 0, 0, 0, 0, t.Location())
+		return first, fmt.Sprintf("%d-Q%d", t.Year(), quarter+1), nil
+	default:
+		return time.Time{}, "", fmt.Errorf("invalid bucket %q: must be %s, %s or %s", size, BucketWeek, BucketMonth, BucketQuarter)
+	}
+}
+
+// nextBucket returns the start of the bucket following the one starting at first
+func nextBucket(first time.Time, size string) time.Time {
+	switch size {
+	case BucketWeek:
+		return first.AddDate(0, 0, 7)
+	case BucketMonth:
+		return first.AddDate(0, 1, 0)
+	default:
+		return first.AddDate(0, 3, 0)
+	}
+}
+
 func (c *Chronometer) GetLast7Days() (time.Time, time.Time) {
 	now := time.Now().In(c.jst)
 	c.Start = now.AddDate(0, 0, -7)
@@ -125,3 +125,96 @@ func TestChronometer_Contains(t *testing.T) {
 		})
 	}
 }
+
+func TestChronometer_Buckets(t *testing.T) {
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	dates := func(startDate, endDate string) *services.Chronometer {
+		chronometer, err := services.NewChronometer(services.ChronometerOption{StartDate: &startDate, EndDate: &endDate})
+		assert.NoError(t, err)
+		return chronometer
+	}
+	labels := func(buckets []services.Bucket) []string {
+		result := make([]string, len(buckets))
+		for i, b := range buckets {
+			result[i] = b.Label
+		}
+		return result
+	}
+
+	t.Run("ISO weeks start on Monday and belong to the ISO year", func(t *testing.T) {
+		// 2020-12-28 is the Monday of 2020-W53 and 2021-01-04 the Monday of 2021-W01
+		buckets, err := dates("2020-12-30", "2021-01-12").Buckets(services.BucketWeek)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2020-W53", "2021-W01", "2021-W02"}, labels(buckets))
+		assert.True(t, buckets[0].Period.StartTime().Equal(time.Date(2020, 12, 30, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, buckets[1].Period.StartTime().Equal(time.Date(2021, 1, 4, 0, 0, 0, 0, jst)))
+		assert.True(t, buckets[2].Period.EndTime().Equal(time.Date(2021, 1, 12, 23, 59, 59, 0, time.UTC)))
+	})
+
+	t.Run("months split at midnight JST", func(t *testing.T) {
+		buckets, err := dates("2024-01-15", "2024-03-10").Buckets(services.BucketMonth)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2024-01", "2024-02", "2024-03"}, labels(buckets))
+
+		february := buckets[1].Period
+		assert.True(t, february.Contains(time.Date(2024, 1, 31, 15, 0, 0, 0, time.UTC)))
+		assert.False(t, february.Contains(time.Date(2024, 1, 31, 14, 59, 59, 0, time.UTC)))
+		assert.True(t, buckets[0].Period.Contains(time.Date(2024, 1, 31, 14, 59, 59, 0, time.UTC)))
+		assert.True(t, february.Contains(time.Date(2024, 2, 29, 23, 59, 59, 0, jst)))
+	})
+
+	t.Run("quarters", func(t *testing.T) {
+		buckets, err := dates("2024-03-01", "2024-10-01").Buckets(services.BucketQuarter)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2024-Q1", "2024-Q2", "2024-Q3", "2024-Q4"}, labels(buckets))
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := dates("2024-01-01", "2024-01-31").Buckets("day")
+		assert.Error(t, err)
+	})
+}
+
+func TestChronometer_Comparison(t *testing.T) {
+	startDate, endDate := "2024-03-01", "2024-03-31"
+	chronometer, err := services.NewChronometer(services.ChronometerOption{StartDate: &startDate, EndDate: &endDate})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		spec      string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "previous period of the same length",
+			spec:      services.ComparePrevious,
+			wantStart: time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:      "same dates last year",
+			spec:      services.CompareSameLastYear,
+			wantStart: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2023, 3, 31, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:      "explicit range",
+			spec:      "2023-12-01..2023-12-31",
+			wantStart: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison, err := chronometer.Comparison(tt.spec)
+			assert.NoError(t, err)
+			assert.True(t, comparison.StartTime().Equal(tt.wantStart), comparison.StartTime())
+			assert.True(t, comparison.EndTime().Equal(tt.wantEnd), comparison.EndTime())
+		})
+	}
+
+	_, err = chronometer.Comparison("last-week")
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+)
+
+// CIOptions represents configuration for the CI report
+type CIOptions struct {
+	Period *Chronometer
+	// ByBranch reports every workflow separately for each branch
+	ByBranch bool
+	// ShowHost prefixes repository names with their host, for runs mixing several hosts
+	ShowHost bool
+}
+
+// ExecuteCI summarizes the CI workflow runs of a repository created in the period, one row per
+// workflow, or per workflow and branch when grouping by branch
+func ExecuteCI(repo models.Repository, opts CIOptions) []models.CIMetrics {
+	runs, err := repository.GetWorkflowRuns(repo, opts.Period.StartTime(), opts.Period.EndTime())
+	incomplete := false
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		// Repositories without GitHub Actions simply have no rows
+		incomplete = !errors.Is(err, errors.ErrUnsupported)
+	}
+
+	metrics := calculateCIMetrics(runs, opts.ByBranch)
+	for i := range metrics {
+		metrics[i].Repository = repo.DisplayName(opts.ShowHost)
+		metrics[i].Incomplete = incomplete
+	}
+	return metrics
+}
+
+// calculateCIMetrics groups runs by workflow, and by branch when byBranch is set, sorted by workflow and branch
+func calculateCIMetrics(runs []models.WorkflowRun, byBranch bool) []models.CIMetrics {
+	type group struct{ workflow, branch string }
+	groups := make(map[group][]models.WorkflowRun)
+	for _, run := range runs {
+		g := group{workflow: run.Workflow}
+		if byBranch {
+			g.branch = run.Branch
+		}
+		groups[g] = append(groups[g], run)
+	}
+
+	keys := make([]group, 0, len(groups))
+	for g := range groups {
+		keys = append(keys, g)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].workflow != keys[j].workflow {
+			return keys[i].workflow < keys[j].workflow
+		}
+		return keys[i].branch < keys[j].branch
+	})
+
+	result := make([]models.CIMetrics, 0, len(keys))
+	for _, g := range keys {
+		metrics := analyzeRuns(groups[g])
+		metrics.Workflow = g.workflow
+		metrics.Branch = g.branch
+		result = append(result, metrics)
+	}
+	return result
+}
+
+// analyzeRuns computes the CI metrics of the runs of one workflow. Outcome rates, durations and
+// the flaky rate cover completed runs; timed out runs count as failures. A run is flaky when it
+// only succeeded after being re-run. Queue time is how long each job waited for a runner.
+func analyzeRuns(runs []models.WorkflowRun) models.CIMetrics {
+	completed, succeeded, failed, cancelled, flaky := 0, 0, 0, 0, 0
+	var durations, queued []time.Duration
+	for _, run := range runs {
+		for _, job := range run.Jobs {
+			if wait, ok := job.Queued(); ok {
+				queued = append(queued, wait)
+			}
+		}
+		if !run.Completed() {
+			continue
+		}
+
+		completed++
+		switch run.Conclusion {
+		case models.ConclusionSuccess:
+			succeeded++
+			if run.Attempt > 1 {
+				flaky++
+			}
+		case models.ConclusionFailure, models.ConclusionTimedOut:
+			failed++
+		case models.ConclusionCancelled:
+			cancelled++
+		}
+		if d := run.Duration(); d > 0 {
+			durations = append(durations, d)
+		}
+	}
+
+	return models.CIMetrics{
+		Runs:        len(runs),
+		SuccessRate: calculateRate(succeeded, completed),
+		FailureRate: calculateRate(failed, completed),
+		CancelRate:  calculateRate(cancelled, completed),
+		Duration:    summarizeDurations(durations),
+		FlakyRate:   calculateRate(flaky, completed),
+		QueueTime:   summarizeDurations(queued),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of the durations
+func percentile(times []time.Duration, p float64) time.Duration {
+	sorted := append([]time.Duration(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// SortCIMetrics sorts CI metrics by repository name, keeping the workflow order within a repository
+func SortCIMetrics(metrics []models.CIMetrics) {
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].Repository < metrics[j].Repository
+	})
+}
@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteCI(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	start := chronometer.StartTime()
+	at := func(minutes int) string {
+		return start.Add(24*time.Hour + time.Duration(minutes)*time.Minute).Format(time.RFC3339)
+	}
+	run := func(id int, workflow, branch, status, conclusion string, attempt, minutes int) map[string]any {
+		return map[string]any{
+			"id": id, "name": workflow, "head_branch": branch,
+			"status": status, "conclusion": conclusion, "run_attempt": attempt,
+			"created_at": at(0), "run_started_at": at(0), "updated_at": at(minutes),
+		}
+	}
+
+	repository.Executor = func(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error) {
+		var page map[string]any
+		switch {
+		case resourceType == "workflow runs":
+			page = map[string]any{"workflow_runs": []map[string]any{
+				run(1, "CI", "main", "completed", "success", 1, 4),
+				run(2, "CI", "main", "completed", "success", 2, 6),
+				run(3, "CI", "feature", "completed", "failure", 1, 10),
+				run(4, "CI", "feature", "completed", "cancelled", 1, 1),
+				run(5, "Lint", "main", "in_progress", "", 1, 0),
+			}}
+		case strings.HasSuffix(endpoint, "/1/jobs"):
+			page = map[string]any{"jobs": []map[string]any{
+				{"id": 10, "name": "test", "created_at": at(0), "started_at": at(2), "completed_at": at(4)},
+			}}
+		default:
+			page = map[string]any{"jobs": []map[string]any{}}
+		}
+		data, err := json.Marshal(page)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	repo := models.Repository{Owner: "test-owner", Name: "test-repo"}
+	metrics := services.ExecuteCI(repo, services.CIOptions{Period: chronometer})
+	assert.Equal(t, []models.CIMetrics{
+		{
+			Repository:  "test-owner/test-repo",
+			Workflow:    "CI",
+			Runs:        4,
+			SuccessRate: "50%",
+			FailureRate: "25%",
+			CancelRate:  "25%",
+			// Runs took 1, 4, 6 and 10 minutes
+			Duration: models.DurationSummary{
+				Count: 4, Mean: 315 * time.Second, Min: time.Minute, Median: 4 * time.Minute, P75: 6 * time.Minute, P90: 10 * time.Minute, Max: 10 * time.Minute,
+			},
+			// Run 2 only succeeded on its second attempt
+			FlakyRate: "25%",
+			QueueTime: models.DurationSummary{
+				Count: 1, Mean: 2 * time.Minute, Min: 2 * time.Minute, Median: 2 * time.Minute, P75: 2 * time.Minute, P90: 2 * time.Minute, Max: 2 * time.Minute,
+			},
+		},
+		{
+			Repository:  "test-owner/test-repo",
+			Workflow:    "Lint",
+			Runs:        1,
+			SuccessRate: "None",
+			FailureRate: "None",
+			CancelRate:  "None",
+			FlakyRate:   "None",
+		},
+	}, metrics)
+
+	byBranch := services.ExecuteCI(repo, services.CIOptions{Period: chronometer, ByBranch: true})
+	assert.Len(t, byBranch, 3)
+	assert.Equal(t, "feature", byBranch[0].Branch)
+	assert.Equal(t, 2, byBranch[0].Runs)
+	assert.Equal(t, "main", byBranch[1].Branch)
+	assert.Equal(t, "100%", byBranch[1].SuccessRate)
+}
@@ -12,15 +12,23 @@ import (
 type CommitsOptions struct {
 	Period        *Chronometer
 	DetailedStats bool
+	// ShowHost prefixes repository names with their host, for runs mixing several hosts
+	ShowHost bool
+	// Bots decides whether commits of bot accounts are listed, and under which author
+	Bots BotOptions
 }
 
 // ExecuteCommits fetches commits for the given repository, filters them to the
 // configured period, tags each commit with its repository name, and returns the list.
 func ExecuteCommits(repo models.Repository, opts CommitsOptions) []models.Commit {
-	repoFullName := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
-	commits := repository.GetCommits(repo, opts.Period.StartTime(), opts.DetailedStats)
+	repoFullName := repo.DisplayName(opts.ShowHost)
+	commits, err := repository.GetCommits(repo, opts.Period.StartTime(), opts.DetailedStats)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
 
-	filtered := filterCommitsInPeriod(commits, opts.Period)
+	filtered := filterBots(filterCommitsInPeriod(commits, opts.Period), opts.Bots, func(c models.Commit) string { return c.Author })
+	filtered = groupBotAuthors(filtered, opts.Bots, func(c *models.Commit) *string { return &c.Author })
 	for i := range filtered {
 		filtered[i].Repository = repoFullName
 	}
@@ -29,8 +37,9 @@ func ExecuteCommits(repo models.Repository, opts CommitsOptions) []models.Commit
 }
 
 // SortCommitsByDate sorts commits by date in descending order (newest first).
+// Commits with equal dates keep their relative order.
 func SortCommitsByDate(commits []models.Commit) {
-	sort.Slice(commits, func(i, j int) bool {
+	sort.SliceStable(commits, func(i, j int) bool {
 		return commits[i].Date.After(commits[j].Date)
 	})
 }
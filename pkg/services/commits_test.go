@@ -26,7 +26,7 @@ func TestExecuteCommits(t *testing.T) {
 	repository.SetTestMode(true)
 
 	commitDate := chronometer.StartTime().Add(24 * time.Hour)
-	repository.Executor = func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
 		return []map[string]any{
 			{
 				"sha": "abc1234567890",
@@ -40,7 +40,7 @@ func TestExecuteCommits(t *testing.T) {
 				"html_url": "https://github.com/test/url",
 			},
 		}, nil
-	}
+	})
 
 	repo := models.Repository{Owner: "test-owner", Name: "test-repo"}
 	opts := services.CommitsOptions{
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"yokiyoki/pkg/models"
+)
+
+// comparedMetric extracts one metric from a row, reporting false when it could not be measured
+type comparedMetric struct {
+	name  string
+	unit  string
+	value func(m models.Metrics) (float64, bool)
+}
+
+// CompareMetrics pairs the rows of the period with those of the period compared against by
+// repository and user or team, and lists each metric with its value in both. A row missing from one of the
+// periods counts as no activity. Rows follow the order of current, then those only in previous.
+// options chooses the metrics like it chooses what is collected.
+func CompareMetrics(current, previous []models.Metrics, options MetricsOptions) []models.MetricComparison {
+	type key struct{ repository, user, team string }
+	previousRows := make(map[key]models.Metrics)
+	for _, m := range previous {
+		previousRows[key{m.Repository, m.User, m.Team}] = m
+	}
+
+	type pair struct{ current, previous models.Metrics }
+	var pairs []pair
+	seen := make(map[key]bool)
+	for _, m := range current {
+		k := key{m.Repository, m.User, m.Team}
+		seen[k] = true
+		p, ok := previousRows[k]
+		if !ok {
+			p = models.Metrics{Repository: m.Repository, User: m.User, Team: m.Team}
+		}
+		pairs = append(pairs, pair{current: m, previous: p})
+	}
+	for _, m := range previous {
+		if !seen[key{m.Repository, m.User, m.Team}] {
+			pairs = append(pairs, pair{current: models.Metrics{Repository: m.Repository, User: m.User, Team: m.Team}, previous: m})
+		}
+	}
+
+	metrics := comparedMetrics(options)
+	var result []models.MetricComparison
+	for _, p := range pairs {
+		for _, metric := range metrics {
+			result = append(result, models.MetricComparison{
+				Repository: p.current.Repository,
+				User:       p.current.User,
+				Team:       p.current.Team,
+				Metric:     metric.name,
+				Unit:       metric.unit,
+				Current:    comparedValue(metric, p.current),
+				Previous:   comparedValue(metric, p.previous),
+				Incomplete: p.current.Incomplete || p.previous.Incomplete,
+			})
+		}
+	}
+	return result
+}
+
+func comparedValue(metric comparedMetric, m models.Metrics) *float64 {
+	value, ok := metric.value(m)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// comparedMetrics lists the metrics compared, in the order of the metrics table
+func comparedMetrics(options MetricsOptions) []comparedMetric {
+	count := func(name string, value func(m models.Metrics) float64) comparedMetric {
+		return comparedMetric{name: name, unit: models.UnitCount, value: func(m models.Metrics) (float64, bool) { return value(m), true }}
+	}
+	rate := func(name string, completed, total func(m models.Metrics) int) comparedMetric {
+		return comparedMetric{name: name, unit: models.UnitPercent, value: func(m models.Metrics) (float64, bool) {
+			if total(m) == 0 {
+				return 0, false
+			}
+			return float64(completed(m)) / float64(total(m)) * 100, true
+		}}
+	}
+	perItem := func(name string, count, items func(m models.Metrics) int) comparedMetric {
+		return comparedMetric{name: name, unit: models.UnitCount, value: func(m models.Metrics) (float64, bool) {
+			if items(m) == 0 {
+				return 0, false
+			}
+			return float64(count(m)) / float64(items(m)), true
+		}}
+	}
+	duration := func(name string, summary func(m models.Metrics) models.DurationSummary) comparedMetric {
+		return comparedMetric{name: name, unit: models.UnitSeconds, value: func(m models.Metrics) (float64, bool) {
+			s := summary(m)
+			return s.Mean.Seconds(), s.Count > 0
+		}}
+	}
+
+	metrics := []comparedMetric{
+		count("Commits", func(m models.Metrics) float64 { return m.Commits }),
+		count("PRs Created", func(m models.Metrics) float64 { return float64(m.PRsCreated) }),
+		count("PRs Merged", func(m models.Metrics) float64 { return float64(m.PRsMerged) }),
+		rate("PR Merge Rate", func(m models.Metrics) int { return m.PRsMerged }, func(m models.Metrics) int { return m.PRsCreated }),
+		duration("PR Merge Time", func(m models.Metrics) models.DurationSummary { return m.PRMergeTime }),
+		count("Issues Created", func(m models.Metrics) float64 { return float64(m.IssuesCreated) }),
+		count("Issues Closed", func(m models.Metrics) float64 { return float64(m.IssuesClosed) }),
+		rate("Issue Resolve Rate", func(m models.Metrics) int { return m.IssuesClosed }, func(m models.Metrics) int { return m.IssuesCreated }),
+		duration("Issue Resolve Time", func(m models.Metrics) models.DurationSummary { return m.IssueCloseTime }),
+		count("Active Issues", func(m models.Metrics) float64 { return float64(m.OpenIssues) }),
+	}
+
+	if options.DetailedStats {
+		metrics = append(metrics,
+			count("Lines Added", func(m models.Metrics) float64 { return float64(m.LinesAdded) }),
+			count("Lines Deleted", func(m models.Metrics) float64 { return float64(m.LinesDeleted) }),
+		)
+	}
+
+	if options.Reviews {
+		metrics = append(metrics,
+			duration("First Review", func(m models.Metrics) models.DurationSummary { return m.TimeToFirstReview }),
+			duration("Approval to Merge", func(m models.Metrics) models.DurationSummary { return m.ApprovalToMerge }),
+			perItem("Reviews/PR", func(m models.Metrics) int { return m.ReviewsReceived }, func(m models.Metrics) int { return m.PRsCreated }),
+			count("Reviews Given", func(m models.Metrics) float64 { return float64(m.ReviewsGiven) }),
+		)
+	}
+
+	if options.Timeline {
+		metrics = append(metrics,
+			duration("First Response", func(m models.Metrics) models.DurationSummary { return m.TimeToFirstResponse }),
+			rate("Reopen Rate", func(m models.Metrics) int { return m.ItemsReopened }, func(m models.Metrics) int { return m.ItemsClosed }),
+			duration("Time to Assign", func(m models.Metrics) models.DurationSummary { return m.TimeToAssignment }),
+		)
+	}
+
+	return metrics
+}
@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/services"
+)
+
+func TestCompareMetrics(t *testing.T) {
+	current := []models.Metrics{
+		{
+			Repository:  "owner/repo",
+			User:        "alice",
+			Commits:     12,
+			PRsCreated:  4,
+			PRsMerged:   3,
+			PRMergeTime: models.DurationSummary{Count: 3, Mean: 2 * time.Hour},
+			// Reviews/PR and Reopen Rate are compared from their counts
+			ReviewsReceived: 6,
+			ItemsClosed:     4,
+			ItemsReopened:   1,
+		},
+		{Repository: "owner/repo", User: "carol", Commits: 1, Incomplete: true},
+	}
+	previous := []models.Metrics{
+		{
+			Repository:      "owner/repo",
+			User:            "alice",
+			Commits:         8,
+			PRsCreated:      2,
+			PRsMerged:       2,
+			PRMergeTime:     models.DurationSummary{Count: 2, Mean: 3 * time.Hour},
+			ReviewsReceived: 2,
+		},
+		{Repository: "owner/repo", User: "bob", Commits: 5},
+	}
+
+	comparisons := services.CompareMetrics(current, previous, services.MetricsOptions{Reviews: true, Timeline: true})
+
+	find := func(user, metric string) models.MetricComparison {
+		for _, c := range comparisons {
+			if c.User == user && c.Metric == metric {
+				return c
+			}
+		}
+		t.Fatalf("no %s comparison for %s", metric, user)
+		return models.MetricComparison{}
+	}
+
+	commits := find("alice", "Commits")
+	assert.Equal(t, 12.0, *commits.Current)
+	assert.Equal(t, 8.0, *commits.Previous)
+	delta, _ := commits.Delta()
+	assert.Equal(t, 4.0, delta)
+	change, ok := commits.Change()
+	assert.True(t, ok)
+	assert.Equal(t, 50.0, change)
+
+	rate := find("alice", "PR Merge Rate")
+	assert.Equal(t, models.UnitPercent, rate.Unit)
+	assert.Equal(t, 75.0, *rate.Current)
+	assert.Equal(t, 100.0, *rate.Previous)
+
+	mergeTime := find("alice", "PR Merge Time")
+	assert.Equal(t, models.UnitSeconds, mergeTime.Unit)
+	delta, _ = mergeTime.Delta()
+	assert.Equal(t, -3600.0, delta)
+
+	reviews := find("alice", "Reviews/PR")
+	assert.Equal(t, 1.5, *reviews.Current)
+	assert.Equal(t, 1.0, *reviews.Previous)
+
+	reopen := find("alice", "Reopen Rate")
+	assert.Equal(t, models.UnitPercent, reopen.Unit)
+	assert.Equal(t, 25.0, *reopen.Current)
+	assert.Nil(t, reopen.Previous)
+
+	// Users active in only one of the periods count as inactive in the other
+	assert.Equal(t, 0.0, *find("carol", "Commits").Previous)
+	assert.True(t, find("carol", "Commits").Incomplete)
+	_, ok = find("carol", "Commits").Change()
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, *find("bob", "Commits").Current)
+	assert.Nil(t, find("bob", "PR Merge Time").Current)
+
+	assert.Equal(t, "carol", comparisons[len(comparisons)/3].User)
+	assert.Equal(t, "bob", comparisons[len(comparisons)-1].User)
+	find("alice", "Reviews Given")
+}
+
+func TestCompareMetrics_Teams(t *testing.T) {
+	current := []models.Metrics{
+		{Repository: "owner/repo", Team: "backend", Commits: 6},
+		{Repository: "owner/repo", Team: models.OtherTeam, Commits: 2},
+	}
+	previous := []models.Metrics{
+		{Repository: "owner/repo", Team: "backend", Commits: 4},
+	}
+
+	comparisons := services.CompareMetrics(current, previous, services.MetricsOptions{})
+
+	commits := make(map[string][2]*float64)
+	for _, c := range comparisons {
+		if c.Metric == "Commits" {
+			commits[c.Team] = [2]*float64{c.Previous, c.Current}
+		}
+	}
+	assert.Equal(t, 4.0, *commits["backend"][0])
+	assert.Equal(t, 6.0, *commits["backend"][1])
+	assert.Equal(t, 0.0, *commits[models.OtherTeam][0])
+	assert.Equal(t, 2.0, *commits[models.OtherTeam][1])
+}
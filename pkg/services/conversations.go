@@ -3,95 +3,175 @@ package services
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/parallel"
 	"yokiyoki/pkg/repository"
 )
 
 // ConversationsOptions represents configuration for conversation collection.
 type ConversationsOptions struct {
 	Period *Chronometer
+	// Concurrency is the maximum number of comment threads fetched in parallel.
+	Concurrency int
+	// ShowHost prefixes repository names with their host, for runs mixing several hosts
+	ShowHost bool
+	// Bots decides whether comments of bot accounts are listed, and under which author
+	Bots BotOptions
 }
 
-// ExecuteConversations fetches PR and issue conversations (initial bodies and
-// comments) for the given repository, filtered to the configured period.
+// thread identifies a PR or issue whose conversation is collected.
+type thread struct {
+	Type      string
+	Number    int
+	Title     string
+	Author    string
+	Body      string
+	URL       string
+	CreatedAt time.Time
+}
+
+// ExecuteConversations fetches PR and issue conversations (initial bodies,
+// comments, and for PRs review bodies and inline review comments) for the given
+// repository, filtered to the configured period.
 // Each PR/issue that was created within the period is included together with
-// all its comments.
+// all its comments. Comment threads are fetched concurrently but returned in
+// a deterministic order.
 func ExecuteConversations(repo models.Repository, opts ConversationsOptions) []models.Comment {
-	repoFullName := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
-	var allComments []models.Comment
+	repoFullName := repo.DisplayName(opts.ShowHost)
+	var threads []thread
 
 	// Collect PR conversations.
-	prs := repository.GetPullRequests(repo, opts.Period.StartTime())
+	prs, err := repository.GetPullRequests(repo, opts.Period.StartTime(), opts.Period.EndTime())
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
 	for _, pr := range prs {
 		if !prInPeriod(pr, opts.Period) {
 			continue
 		}
-
-		// Include the PR description as the opening comment.
-		if pr.Body != "" {
-			allComments = append(allComments, models.Comment{
-				Repository: repoFullName,
-				Type:       "pr",
-				Number:     pr.Number,
-				Title:      pr.Title,
-				Author:     pr.Author,
-				Body:       pr.Body,
-				URL:        pr.URL,
-				CreatedAt:  pr.CreatedAt,
-			})
-		}
-
-		// Append all follow-up comments on the PR.
-		prComments := repository.GetComments(repo, pr.Number)
-		for _, c := range prComments {
-			c.Repository = repoFullName
-			c.Type = "pr"
-			c.Number = pr.Number
-			c.Title = pr.Title
-			allComments = append(allComments, c)
-		}
+		threads = append(threads, thread{
+			Type:      models.KindPullRequest,
+			Number:    pr.Number,
+			Title:     pr.Title,
+			Author:    pr.Author,
+			Body:      pr.Body,
+			URL:       pr.URL,
+			CreatedAt: pr.CreatedAt,
+		})
 	}
 
 	// Collect Issue conversations.
-	issues := repository.GetIssues(repo, opts.Period.StartTime())
+	issues, err := repository.GetIssues(repo, opts.Period.StartTime(), opts.Period.EndTime())
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
 	for _, issue := range issues {
 		if !issueInPeriod(issue, opts.Period) {
 			continue
 		}
+		threads = append(threads, thread{
+			Type:      models.KindIssue,
+			Number:    issue.Number,
+			Title:     issue.Title,
+			Author:    issue.Author,
+			Body:      issue.Body,
+			URL:       issue.URL,
+			CreatedAt: issue.CreatedAt,
+		})
+	}
 
-		// Include the issue description as the opening comment.
-		if issue.Body != "" {
-			allComments = append(allComments, models.Comment{
-				Repository: repoFullName,
-				Type:       "issue",
-				Number:     issue.Number,
-				Title:      issue.Title,
-				Author:     issue.Author,
-				Body:       issue.Body,
-				URL:        issue.URL,
-				CreatedAt:  issue.CreatedAt,
-			})
-		}
+	conversations := parallel.Map(threads, opts.Concurrency, func(th thread) []models.Comment {
+		return collectThread(repo, repoFullName, th)
+	})
 
-		// Append all follow-up comments on the issue.
-		issueComments := repository.GetComments(repo, issue.Number)
-		for _, c := range issueComments {
-			c.Repository = repoFullName
-			c.Type = "issue"
-			c.Number = issue.Number
-			c.Title = issue.Title
-			allComments = append(allComments, c)
-		}
+	var allComments []models.Comment
+	for _, comments := range conversations {
+		allComments = append(allComments, comments...)
 	}
+	allComments = filterBots(allComments, opts.Bots, func(c models.Comment) string { return c.Author })
+	allComments = groupBotAuthors(allComments, opts.Bots, func(c *models.Comment) *string { return &c.Author })
 
 	SortCommentsByDate(allComments)
 	return allComments
 }
 
+// collectThread returns the opening body of a PR or issue followed by all its comments.
+// PR threads also include review summaries and inline review comments.
+func collectThread(repo models.Repository, repoFullName string, th thread) []models.Comment {
+	var comments []models.Comment
+
+	// Include the description as the opening comment.
+	if th.Body != "" {
+		comments = append(comments, models.Comment{
+			SubType:   models.CommentDescription,
+			Author:    th.Author,
+			Body:      th.Body,
+			URL:       th.URL,
+			CreatedAt: th.CreatedAt,
+		})
+	}
+
+	// Append all follow-up comments.
+	followUps, err := repository.GetComments(repo, th.Type, th.Number)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	for _, c := range followUps {
+		c.SubType = models.CommentIssueComment
+		comments = append(comments, c)
+	}
+
+	if th.Type == models.KindPullRequest {
+		comments = append(comments, collectReviews(repo, th)...)
+	}
+
+	for i := range comments {
+		comments[i].Repository = repoFullName
+		comments[i].Type = th.Type
+		comments[i].Number = th.Number
+		comments[i].Title = th.Title
+	}
+	return comments
+}
+
+// collectReviews returns the review summaries with a body and the inline review comments of a PR
+func collectReviews(repo models.Repository, th thread) []models.Comment {
+	var comments []models.Comment
+
+	reviews, err := repository.GetReviews(repo, th.Number)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	for _, review := range reviews {
+		if review.Body == "" {
+			continue
+		}
+		url := review.URL
+		if url == "" {
+			url = th.URL
+		}
+		comments = append(comments, models.Comment{
+			SubType:   models.CommentReview,
+			Author:    review.Reviewer,
+			Body:      review.Body,
+			URL:       url,
+			CreatedAt: review.SubmittedAt,
+		})
+	}
+
+	reviewComments, err := repository.GetReviewComments(repo, th.Number)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	return append(comments, reviewComments...)
+}
+
 // SortCommentsByDate sorts comments by creation date in ascending order (oldest first).
+// Comments with equal dates keep their relative order.
 func SortCommentsByDate(comments []models.Comment) {
-	sort.Slice(comments, func(i, j int) bool {
+	sort.SliceStable(comments, func(i, j int) bool {
 		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
 	})
 }
@@ -32,7 +32,8 @@ func TestExecuteConversations(t *testing.T) {
 	// 2. GetIssues (endpoint contains "/issues?")
 	// 3. GetComments for PR #1 (endpoint contains "/issues/1/comments")
 	// 4. GetComments for Issue #2 (endpoint contains "/issues/2/comments")
-	repository.Executor = func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+	// 5. GetReviews and GetReviewComments for PR #1
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
 		switch {
 		case contains(endpoint, "pulls?state=all"):
 			return []map[string]any{
@@ -59,6 +60,46 @@ func TestExecuteConversations(t *testing.T) {
 					"labels":     []any{},
 				},
 			}, nil
+		case contains(endpoint, "/pulls/1/reviews"):
+			return []map[string]any{
+				{
+					"id":           float64(10),
+					"state":        "APPROVED",
+					"submitted_at": prDate.Add(3 * time.Hour).Format(time.RFC3339),
+					"user":         map[string]any{"login": "dave"},
+				},
+				{
+					"id":           float64(11),
+					"state":        "CHANGES_REQUESTED",
+					"body":         "Please rename this",
+					"html_url":     "https://github.com/test/repo/pull/1#pullrequestreview-11",
+					"submitted_at": prDate.Add(2 * time.Hour).Format(time.RFC3339),
+					"user":         map[string]any{"login": "erin"},
+				},
+			}, nil
+		case contains(endpoint, "/pulls/1/comments"):
+			return []map[string]any{
+				{
+					"id":            float64(20),
+					"body":          "Typo here",
+					"path":          "main.go",
+					"line":          nil,
+					"original_line": float64(12),
+					"diff_hunk":     "@@ -10,3 +10,3 @@",
+					"html_url":      "https://github.com/test/repo/pull/1#discussion_r20",
+					"created_at":    prDate.Add(2 * time.Hour).Format(time.RFC3339),
+					"user":          map[string]any{"login": "erin"},
+				},
+				{
+					"id":             float64(21),
+					"body":           "Fixed",
+					"path":           "main.go",
+					"line":           float64(12),
+					"in_reply_to_id": float64(20),
+					"created_at":     prDate.Add(4 * time.Hour).Format(time.RFC3339),
+					"user":           map[string]any{"login": "alice"},
+				},
+			}, nil
 		default:
 			// Comments endpoint
 			return []map[string]any{
@@ -70,20 +111,54 @@ func TestExecuteConversations(t *testing.T) {
 				},
 			}, nil
 		}
-	}
+	})
 
 	repo := models.Repository{Owner: "test-owner", Name: "test-repo"}
 	opts := services.ConversationsOptions{Period: chronometer}
 
 	comments := services.ExecuteConversations(repo, opts)
 
-	// Should have: PR body, PR comment, Issue body, Issue comment = 4 entries
-	assert.Len(t, comments, 4)
+	// Should have: PR body, PR comment, PR review body, 2 review comments, Issue body, Issue comment = 7 entries
+	assert.Len(t, comments, 7)
 
 	// All should be tagged with the repository name
 	for _, c := range comments {
 		assert.Equal(t, "test-owner/test-repo", c.Repository)
 	}
+
+	var reviewComments []models.Comment
+	subTypes := map[string]int{}
+	for _, c := range comments {
+		subTypes[c.SubType]++
+		if c.SubType == models.CommentReviewComment {
+			reviewComments = append(reviewComments, c)
+		}
+	}
+	assert.Equal(t, map[string]int{
+		models.CommentDescription:   2,
+		models.CommentIssueComment:  2,
+		models.CommentReview:        1,
+		models.CommentReviewComment: 2,
+	}, subTypes)
+
+	// Review comments keep their location and thread, and are sorted by date
+	assert.Len(t, reviewComments, 2)
+	assert.Equal(t, models.Comment{
+		Repository: "test-owner/test-repo",
+		Type:       models.KindPullRequest,
+		SubType:    models.CommentReviewComment,
+		Number:     1,
+		Title:      "Test PR",
+		Author:     "erin",
+		Body:       "Typo here",
+		URL:        "https://github.com/test/repo/pull/1#discussion_r20",
+		CreatedAt:  reviewComments[0].CreatedAt,
+		ID:         20,
+		Path:       "main.go",
+		Line:       12,
+		DiffHunk:   "@@ -10,3 +10,3 @@",
+	}, reviewComments[0])
+	assert.Equal(t, int64(20), reviewComments[1].InReplyTo)
 }
 
 func TestSortCommentsByDate(t *testing.T) {
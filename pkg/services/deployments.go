@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+)
+
+// calculateDeploymentMetrics computes the DORA metrics of each environment deployed to, sorted by environment.
+// The commits each deployment shipped are compared against the previous deployment; environments whose
+// comparisons failed are marked incomplete.
+func calculateDeploymentMetrics(repo models.Repository, name string, deployments []models.Deployment, period *Chronometer) []models.DeploymentMetrics {
+	shipped := func(base, head string) ([]models.Commit, error) {
+		return repository.CompareCommits(repo, base, head)
+	}
+
+	byEnvironment := make(map[string][]models.Deployment)
+	for _, d := range deployments {
+		byEnvironment[d.Environment] = append(byEnvironment[d.Environment], d)
+	}
+
+	environments := make([]string, 0, len(byEnvironment))
+	for environment := range byEnvironment {
+		environments = append(environments, environment)
+	}
+	sort.Strings(environments)
+
+	var result []models.DeploymentMetrics
+	for _, environment := range environments {
+		metrics := analyzeDeployments(byEnvironment[environment], shipped, period)
+		metrics.Repository = name
+		metrics.Environment = environment
+		result = append(result, metrics)
+	}
+	return result
+}
+
+// deploymentOutcome is a deployment that succeeded or failed at a point in time
+type deploymentOutcome struct {
+	deployment models.Deployment
+	at         time.Time
+	failed     bool
+}
+
+// analyzeDeployments computes the DORA metrics of the deployments to a single environment:
+//   - deployment frequency: successful deployments in the period per day
+//   - lead time for changes: from each commit to the successful deployment that shipped it
+//   - change failure rate: failed deployments out of those that succeeded or failed in the period
+//   - time to restore: from the first failure to the next successful deployment
+func analyzeDeployments(deployments []models.Deployment, shipped shippedFunc, period *Chronometer) models.DeploymentMetrics {
+	var outcomes []deploymentOutcome
+	for _, d := range deployments {
+		if at := d.SucceededAt(); at != nil {
+			outcomes = append(outcomes, deploymentOutcome{deployment: d, at: *at})
+		} else if at := d.FailedAt(); at != nil {
+			outcomes = append(outcomes, deploymentOutcome{deployment: d, at: *at, failed: true})
+		}
+	}
+	sort.SliceStable(outcomes, func(i, j int) bool { return outcomes[i].at.Before(outcomes[j].at) })
+
+	successes, failures := 0, 0
+	var restoreTimes []time.Duration
+	var failingSince *time.Time
+	for i, outcome := range outcomes {
+		if outcome.failed {
+			if period.Contains(outcome.at) {
+				failures++
+			}
+			if failingSince == nil {
+				failingSince = &outcomes[i].at
+			}
+			continue
+		}
+		if period.Contains(outcome.at) {
+			successes++
+			if failingSince != nil {
+				restoreTimes = append(restoreTimes, outcome.at.Sub(*failingSince))
+			}
+		}
+		failingSince = nil
+	}
+
+	lead, err := leadTimes(outcomes, shipped, period)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	return models.DeploymentMetrics{
+		Deployments:         successes,
+		DeploymentFrequency: calculateFrequency(successes, period),
+		LeadTime:            summarizeDurations(lead),
+		ChangeFailureRate:   calculateRate(failures, successes+failures),
+		TimeToRestore:       summarizeDurations(restoreTimes),
+		Incomplete:          err != nil,
+	}
+}
+
+// shippedFunc returns the commits reachable from head but not from base
+type shippedFunc func(base, head string) ([]models.Commit, error)
+
+// leadTimes measures how long each commit took to reach a successful deployment in the period.
+// A deployment ships the commits between the commit of the previous successful deployment, which
+// may precede the period, and its own, skipping those already shipped, e.g. before a rollback.
+// A deployment without a previous one has no known base and is left out. A failed comparison
+// skips the deployment's commits and is returned once all deployments are measured.
+func leadTimes(outcomes []deploymentOutcome, shipped shippedFunc, period *Chronometer) ([]time.Duration, error) {
+	var times []time.Duration
+	var errs []error
+	seen := make(map[string]bool)
+	base := ""
+	for _, outcome := range outcomes {
+		if outcome.failed || outcome.at.After(period.EndTime()) {
+			continue
+		}
+		head := outcome.deployment.SHA
+		if base == "" || !period.Contains(outcome.at) {
+			base = head
+			continue
+		}
+		commits, err := shipped(base, head)
+		base = head
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, c := range commits {
+			if seen[c.SHA] {
+				continue
+			}
+			seen[c.SHA] = true
+			times = append(times, outcome.at.Sub(c.Date))
+		}
+	}
+	return times, errors.Join(errs...)
+}
+
+// calculateFrequency formats the number of events per day of the period
+func calculateFrequency(count int, period *Chronometer) string {
+	days := math.Max(1, math.Round(period.EndTime().Sub(period.StartTime()).Hours()/24))
+	if count == 0 {
+		return "None"
+	}
+	return fmt.Sprintf("%.2f/day", float64(count)/days)
+}
@@ -1,12 +1,14 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"time"
 
-	"yokiyoki/pkg/formatter"
 	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/parallel"
 	"yokiyoki/pkg/repository"
 )
 
@@ -17,27 +19,143 @@ type MetricsOptions struct {
 	NormalizeUsers bool
 	DetailedStats  bool
 	SortBy         string
+	// ShowHost prefixes repository names with their host, for runs mixing several hosts
+	ShowHost bool
+	// Reviews fetches the reviews and review requests of every pull request in the period
+	Reviews bool
+	// Concurrency is the maximum number of pull requests and issues whose reviews or timelines are fetched in parallel
+	Concurrency int
+	// Deployments fetches deployments and computes the DORA metrics of each environment
+	Deployments bool
+	// Attribution is how co-authored commits are credited when breaking down by user; empty means AttributionPrimary
+	Attribution string
+	// Bots decides how activity of bot accounts is reported; grouping applies when breaking down by user
+	Bots BotOptions
+	// Timeline fetches the timeline of every pull request and issue in the period for response,
+	// reopen, assignment and label metrics
+	Timeline bool
+	// Bucket splits the period into consecutive BucketWeek, BucketMonth or BucketQuarter intervals
+	// with a row per interval; empty reports the whole period at once
+	Bucket string
+	// ByTeam breaks down metrics by the teams in Teams instead of by user
+	ByTeam bool
+	Teams  models.Teams
+}
+
+// Attribution policies for commits with Co-authored-by trailers
+const (
+	// AttributionPrimary credits only the commit author
+	AttributionPrimary = "primary"
+	// AttributionFull credits the whole commit to the author and to each co-author
+	AttributionFull = "full"
+	// AttributionSplit splits the commit evenly between the author and the co-authors
+	AttributionSplit = "split"
+)
+
+// creditedCommit is a commit with the share of it credited to a user
+type creditedCommit struct {
+	models.Commit
+	credit float64
+}
+
+// Report holds the metrics collected for a repository
+type Report struct {
+	Metrics []models.Metrics
+	// Deployments holds one row per environment when deployments are fetched
+	Deployments []models.DeploymentMetrics
+	// Labels holds one row per label when timelines are fetched
+	Labels []models.LabelMetrics
+	// teams holds the activity behind team rows, for MergeTeams to add up across repositories
+	teams *teamActivity
 }
 
 // Execute processes metrics collection with options
 func Execute(repo models.Repository, options MetricsOptions) []models.Metrics {
-	var metrics []models.Metrics
-	if options.ByUser {
-		metrics = executeByUser(repo, options)
-	} else {
-		result := executeForRepo(repo, options)
-		metrics = []models.Metrics{result}
+	report := Collect(repo, options)
+	if options.ByTeam {
+		return MergeTeams([]Report{report}, options)
+	}
+	return report.Metrics
+}
+
+// Collect processes metrics collection with options, adding the DORA metrics when deployments are fetched.
+// Deployment and label metrics always cover the whole period, even when the metrics are split into buckets.
+// With ByTeam, the activity is kept for MergeTeams to group by team across repositories, and no rows are
+// computed for the repository alone.
+func Collect(repo models.Repository, options MetricsOptions) Report {
+	data := fetchRepoData(repo, options)
+
+	var report Report
+	activity := filterBotActivity(data, options.Bots)
+	repoFullName := repo.DisplayName(options.ShowHost)
+	switch {
+	case options.ByTeam:
+		report.teams = &teamActivity{repository: repoFullName, data: activity}
+	case options.Bucket != "":
+		report.Metrics = executeByBucket(repoFullName, activity, options)
+	default:
+		report.Metrics = executeForPeriod(repoFullName, activity, options)
 	}
 
 	if options.SortBy != "" {
-		sortMetrics(metrics, options.SortBy)
+		SortMetrics(report.Metrics, options.SortBy)
+	}
+
+	if options.Timeline {
+		report.Labels = calculateLabelMetrics(repo.DisplayName(options.ShowHost), activity.prs, activity.issues, options.Period)
+		for i := range report.Labels {
+			report.Labels[i].Incomplete = data.incomplete
+		}
+	}
+
+	if options.Deployments {
+		report.Deployments = calculateDeploymentMetrics(repo, repo.DisplayName(options.ShowHost), data.deployments, options.Period)
+		for i := range report.Deployments {
+			report.Deployments[i].Incomplete = report.Deployments[i].Incomplete || data.incomplete
+		}
 	}
 
+	return report
+}
+
+// executeForPeriod computes the metrics of the repository or of each user or team over options.Period
+func executeForPeriod(repoFullName string, data repoData, options MetricsOptions) []models.Metrics {
+	if options.ByTeam {
+		return executeByTeam(repoFullName, data, options)
+	}
+	if options.ByUser {
+		return executeByUser(repoFullName, data, options)
+	}
+	return []models.Metrics{executeForRepo(repoFullName, data, options)}
+}
+
+// executeByBucket computes the metrics of every bucket of the period in order. With ByUser or ByTeam,
+// every user or team active in the period gets a row in every bucket.
+func executeByBucket(repoFullName string, data repoData, options MetricsOptions) []models.Metrics {
+	buckets, err := options.Period.Buckets(options.Bucket)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return nil
+	}
+
+	var metrics []models.Metrics
+	for _, bucket := range buckets {
+		bucketOptions := options
+		bucketOptions.Period = bucket.Period
+		rows := executeForPeriod(repoFullName, data, bucketOptions)
+		for i := range rows {
+			rows[i].Bucket = bucket.Label
+			rows[i].BucketStart = bucket.Period.StartTime()
+			rows[i].BucketEnd = bucket.Period.EndTime()
+		}
+		metrics = append(metrics, rows...)
+	}
 	return metrics
 }
 
-func sortMetrics(metrics []models.Metrics, sortBy string) {
-	sort.Slice(metrics, func(i, j int) bool {
+// SortMetrics sorts the rows by sortBy, keeping the buckets of each row in order
+func SortMetrics(metrics []models.Metrics, sortBy string) {
+	sort.SliceStable(metrics, func(i, j int) bool {
 		switch sortBy {
 		case "repository,user":
 			if metrics[i].Repository != metrics[j].Repository {
@@ -49,42 +167,160 @@ func sortMetrics(metrics []models.Metrics, sortBy string) {
 				return metrics[i].User < metrics[j].User
 			}
 			return metrics[i].Repository < metrics[j].Repository
+		case "repository,team":
+			if metrics[i].Repository != metrics[j].Repository {
+				return metrics[i].Repository < metrics[j].Repository
+			}
+			return metrics[i].Team < metrics[j].Team
+		case "team,repository":
+			if metrics[i].Team != metrics[j].Team {
+				return metrics[i].Team < metrics[j].Team
+			}
+			return metrics[i].Repository < metrics[j].Repository
 		default: // "repository"
 			return metrics[i].Repository < metrics[j].Repository
 		}
 	})
 }
 
-func executeForRepo(repo models.Repository, options MetricsOptions) models.Metrics {
-	repoFullName := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+// repoData holds the raw data fetched for a repository
+type repoData struct {
+	commits []models.Commit
+	prs     []models.PullRequest
+	issues  []models.Issue
+	// deployments are only fetched when requested
+	deployments []models.Deployment
+	// incomplete is set when any fetch failed, so the metrics may undercount
+	incomplete bool
+}
 
-	commits := repository.GetCommits(repo, options.Period.StartTime(), options.DetailedStats)
-	prs := repository.GetPullRequests(repo, options.Period.StartTime())
-	issues := repository.GetIssues(repo, options.Period.StartTime())
+// filterBotActivity leaves out the commits, pull requests, issues and reviews whose author is not
+// reported under the bot policy. Deployments still see every commit.
+func filterBotActivity(data repoData, bots BotOptions) repoData {
+	data.commits = filterBots(data.commits, bots, func(c models.Commit) string { return c.Author })
+	data.prs = filterBots(data.prs, bots, func(pr models.PullRequest) string { return pr.Author })
+	for i := range data.prs {
+		data.prs[i].Reviews = filterBots(data.prs[i].Reviews, bots, func(r models.Review) string { return r.Reviewer })
+	}
+	data.issues = filterBots(data.issues, bots, func(i models.Issue) string { return i.Author })
+	return data
+}
 
-	return calculateMetricsFromData(repoFullName, "", commits, prs, issues, options.Period, options.DetailedStats)
+func fetchRepoData(repo models.Repository, options MetricsOptions) repoData {
+	var data repoData
+	var errs [3]error
+	data.commits, errs[0] = repository.GetCommits(repo, options.Period.StartTime(), options.DetailedStats)
+	data.prs, errs[1] = repository.GetPullRequests(repo, options.Period.StartTime(), options.Period.EndTime())
+	data.issues, errs[2] = repository.GetIssues(repo, options.Period.StartTime(), options.Period.EndTime())
+
+	for _, err := range errs {
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			data.incomplete = true
+		}
+	}
+
+	if options.Reviews || options.Timeline {
+		data.prs = filterPRsInPeriod(data.prs, options.Period)
+	}
+	if options.Reviews {
+		if !attachReviews(repo, data.prs, options.Concurrency) {
+			data.incomplete = true
+		}
+	}
+	if options.Timeline {
+		data.issues = filterIssuesInPeriod(data.issues, options.Period)
+		if !attachTimelines(repo, data.prs, data.issues, options.Concurrency) {
+			data.incomplete = true
+		}
+	}
+
+	if options.Deployments {
+		// Deployments from one period before are fetched too, to find the base of the first ones in the period
+		start, end := options.Period.StartTime(), options.Period.EndTime()
+		var err error
+		data.deployments, err = repository.GetDeployments(repo, start.Add(-end.Sub(start)), end)
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			// Providers without deployments simply have no DORA metrics
+			if !errors.Is(err, errors.ErrUnsupported) {
+				data.incomplete = true
+			}
+		}
+	}
+	return data
 }
 
-func executeByUser(repo models.Repository, options MetricsOptions) []models.Metrics {
-	repoFullName := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+// attachReviews fetches the reviews and review requests of each pull request in parallel.
+// It reports false when any of them could not be fetched.
+func attachReviews(repo models.Repository, prs []models.PullRequest, concurrency int) bool {
+	type result struct {
+		reviews  []models.Review
+		requests []models.ReviewRequest
+		err      error
+	}
+	results := parallel.Map(prs, concurrency, func(pr models.PullRequest) result {
+		reviews, err := repository.GetReviews(repo, pr.Number)
+		if err != nil {
+			return result{err: err}
+		}
+		requests, err := repository.GetReviewRequests(repo, pr.Number)
+		return result{reviews: reviews, requests: requests, err: err}
+	})
+
+	complete := true
+	for i, r := range results {
+		if r.err != nil {
+			fmt.Printf("Warning: %v\n", r.err)
+			complete = false
+		}
+		prs[i].Reviews = r.reviews
+		prs[i].ReviewRequests = r.requests
+	}
+	return complete
+}
 
-	commits := repository.GetCommits(repo, options.Period.StartTime(), options.DetailedStats)
-	prs := repository.GetPullRequests(repo, options.Period.StartTime())
-	issues := repository.GetIssues(repo, options.Period.StartTime())
+func executeForRepo(repoFullName string, data repoData, options MetricsOptions) models.Metrics {
+	metrics := calculateMetricsFromData(repoFullName, "", fullCredit(data.commits), data.prs, data.issues, options.Period, options.DetailedStats)
+	if options.Reviews {
+		applyReviewMetrics(&metrics, data.prs, reviewsInPeriod(data.prs, options.Period), options.Period)
+	}
+	if options.Timeline {
+		applyTimelineMetrics(&metrics, data.prs, data.issues, options.Period, options.Bots.Bots)
+	}
+	metrics.Incomplete = data.incomplete
+	return metrics
+}
 
-	userCommits := groupCommitsByUser(commits, options.NormalizeUsers)
-	userPRs := groupPRsByUser(prs, options.NormalizeUsers)
-	userIssues := groupIssuesByUser(issues, options.NormalizeUsers)
+func executeByUser(repoFullName string, data repoData, options MetricsOptions) []models.Metrics {
+	userCommits := applyBotPolicy(groupCommitsByUser(data.commits, options.NormalizeUsers, options.Attribution), options.Bots)
+	userPRs := applyBotPolicy(groupPRsByUser(data.prs, options.NormalizeUsers), options.Bots)
+	userIssues := applyBotPolicy(groupIssuesByUser(data.issues, options.NormalizeUsers), options.Bots)
+	userReviews := applyBotPolicy(groupReviewsByUser(reviewsInPeriod(data.prs, options.Period), options.NormalizeUsers), options.Bots)
 
 	users := extractUniqueUsers(userCommits, userPRs, userIssues)
+	for user := range userReviews {
+		users[user] = true
+	}
 
 	// ユーザーがいない場合は"-"で表示
 	if len(users) == 0 {
-		emptyMetrics := calculateMetricsFromData(repoFullName, "-", []models.Commit{}, []models.PullRequest{}, []models.Issue{}, options.Period, options.DetailedStats)
+		emptyMetrics := calculateMetricsFromData(repoFullName, "-", []creditedCommit{}, []models.PullRequest{}, []models.Issue{}, options.Period, options.DetailedStats)
+		emptyMetrics.Incomplete = data.incomplete
 		return []models.Metrics{emptyMetrics}
 	}
 
-	return calculateUserMetrics(repoFullName, users, userCommits, userPRs, userIssues, options)
+	metrics := calculateUserMetrics(repoFullName, users, userCommits, userPRs, userIssues, options)
+	for i := range metrics {
+		if options.Reviews {
+			applyReviewMetrics(&metrics[i], userPRs[metrics[i].User], userReviews[metrics[i].User], options.Period)
+		}
+		if options.Timeline {
+			applyTimelineMetrics(&metrics[i], userPRs[metrics[i].User], userIssues[metrics[i].User], options.Period, options.Bots.Bots)
+		}
+		metrics[i].Incomplete = data.incomplete
+	}
+	return metrics
 }
 
 func userName(author string, normalizeUsers bool) string {
@@ -92,15 +328,51 @@ func userName(author string, normalizeUsers bool) string {
 	return username.Name(normalizeUsers)
 }
 
-func groupCommitsByUser(commits []models.Commit, normalizeUsers bool) map[string][]models.Commit {
-	userCommits := make(map[string][]models.Commit)
+// groupCommitsByUser credits each commit to its author and, unless attribution is AttributionPrimary,
+// to its co-authors
+func groupCommitsByUser(commits []models.Commit, normalizeUsers bool, attribution string) map[string][]creditedCommit {
+	userCommits := make(map[string][]creditedCommit)
 	for _, commit := range commits {
-		author := userName(commit.Author, normalizeUsers)
-		userCommits[author] = append(userCommits[author], commit)
+		users := commitUsers(commit, normalizeUsers, attribution)
+		credit := 1.0
+		if attribution == AttributionSplit {
+			credit /= float64(len(users))
+		}
+		for _, user := range users {
+			userCommits[user] = append(userCommits[user], creditedCommit{Commit: commit, credit: credit})
+		}
 	}
 	return userCommits
 }
 
+// commitUsers returns the author of a commit followed by its co-authors when they are credited,
+// without duplicates
+func commitUsers(commit models.Commit, normalizeUsers bool, attribution string) []string {
+	users := []string{userName(commit.Author, normalizeUsers)}
+	if attribution != AttributionFull && attribution != AttributionSplit {
+		return users
+	}
+
+	seen := map[string]bool{users[0]: true}
+	for _, co := range commit.CoAuthors {
+		user := userName(co.User(), normalizeUsers)
+		if !seen[user] {
+			seen[user] = true
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// fullCredit credits every commit in full, for repository-wide metrics
+func fullCredit(commits []models.Commit) []creditedCommit {
+	credited := make([]creditedCommit, len(commits))
+	for i, commit := range commits {
+		credited[i] = creditedCommit{Commit: commit, credit: 1}
+	}
+	return credited
+}
+
 func groupPRsByUser(prs []models.PullRequest, normalizeUsers bool) map[string][]models.PullRequest {
 	userPRs := make(map[string][]models.PullRequest)
 	for _, pr := range prs {
@@ -119,7 +391,30 @@ func groupIssuesByUser(issues []models.Issue, normalizeUsers bool) map[string][]
 	return userIssues
 }
 
-func extractUniqueUsers(userCommits map[string][]models.Commit, userPRs map[string][]models.PullRequest, userIssues map[string][]models.Issue) map[string]bool {
+// groupReviewsByUser groups reviews by their reviewer
+func groupReviewsByUser(reviews []models.Review, normalizeUsers bool) map[string][]models.Review {
+	userReviews := make(map[string][]models.Review)
+	for _, review := range reviews {
+		reviewer := userName(review.Reviewer, normalizeUsers)
+		userReviews[reviewer] = append(userReviews[reviewer], review)
+	}
+	return userReviews
+}
+
+// reviewsInPeriod returns the reviews submitted within the period, excluding authors reviewing their own pull requests
+func reviewsInPeriod(prs []models.PullRequest, period *Chronometer) []models.Review {
+	var reviews []models.Review
+	for _, pr := range prs {
+		for _, review := range pr.Reviews {
+			if review.Reviewer != pr.Author && period.Contains(review.SubmittedAt) {
+				reviews = append(reviews, review)
+			}
+		}
+	}
+	return reviews
+}
+
+func extractUniqueUsers(userCommits map[string][]creditedCommit, userPRs map[string][]models.PullRequest, userIssues map[string][]models.Issue) map[string]bool {
 	users := make(map[string]bool)
 	for user := range userCommits {
 		users[user] = true
@@ -136,7 +431,7 @@ func extractUniqueUsers(userCommits map[string][]models.Commit, userPRs map[stri
 func calculateUserMetrics(
 	repoFullName string,
 	users map[string]bool,
-	userCommits map[string][]models.Commit,
+	userCommits map[string][]creditedCommit,
 	userPRs map[string][]models.PullRequest,
 	userIssues map[string][]models.Issue,
 	options MetricsOptions,
@@ -217,24 +512,24 @@ func analyzeIssues(issues []models.Issue, period *Chronometer) (int, int, int, [
 
 func calculateMetricsFromData(
 	repo, user string,
-	commits []models.Commit,
+	commits []creditedCommit,
 	prs []models.PullRequest,
 	issues []models.Issue,
 	period *Chronometer,
 	detailedStats bool,
 ) models.Metrics {
-	filteredCommits := filterCommitsInPeriod(commits, period)
 	filteredPRs := filterPRsInPeriod(prs, period)
 	filteredIssues := filterIssuesInPeriod(issues, period)
 
-	commitCount := len(filteredCommits)
-	linesAdded := 0
-	linesDeleted := 0
-
-	if detailedStats {
-		for _, commit := range filteredCommits {
-			linesAdded += commit.Additions
-			linesDeleted += commit.Deletions
+	var commitCount, linesAdded, linesDeleted float64
+	for _, commit := range commits {
+		if !period.Contains(commit.Date) {
+			continue
+		}
+		commitCount += commit.credit
+		if detailedStats {
+			linesAdded += commit.credit * float64(commit.Additions)
+			linesDeleted += commit.credit * float64(commit.Deletions)
 		}
 	}
 
@@ -252,40 +547,111 @@ func calculateMetricsFromData(
 
 	issuesCreated, issuesClosed, openIssues, closeTimes := analyzeIssues(filteredIssues, period)
 
-	avgPRMergeTime := calculateAverageTime(mergeTimes)
-	avgIssueCloseTime := calculateAverageTime(closeTimes)
 	prMergeRate := calculateRate(prsMerged, prsCreated)
 	issueResolveRate := calculateRate(issuesClosed, issuesCreated)
 
 	return models.Metrics{
-		Repository:        repo,
-		User:              user,
-		Commits:           commitCount,
-		LinesAdded:        linesAdded,
-		LinesDeleted:      linesDeleted,
-		PRsCreated:        prsCreated,
-		PRsMerged:         prsMerged,
-		PRMergeRate:       prMergeRate,
-		AvgPRMergeTime:    avgPRMergeTime,
-		IssuesCreated:     issuesCreated,
-		IssuesClosed:      issuesClosed,
-		IssueResolveRate:  issueResolveRate,
-		AvgIssueCloseTime: avgIssueCloseTime,
-		OpenIssues:        openIssues,
-	}
-}
-
-func calculateAverageTime(times []time.Duration) string {
+		Repository:       repo,
+		User:             user,
+		Commits:          commitCount,
+		LinesAdded:       int(math.Round(linesAdded)),
+		LinesDeleted:     int(math.Round(linesDeleted)),
+		PRsCreated:       prsCreated,
+		PRsMerged:        prsMerged,
+		PRMergeRate:      prMergeRate,
+		PRMergeTime:      summarizeDurations(mergeTimes),
+		IssuesCreated:    issuesCreated,
+		IssuesClosed:     issuesClosed,
+		IssueResolveRate: issueResolveRate,
+		IssueCloseTime:   summarizeDurations(closeTimes),
+		OpenIssues:       openIssues,
+	}
+}
+
+// applyReviewMetrics fills in the review metrics of pull requests in the period and of reviews given
+func applyReviewMetrics(metrics *models.Metrics, prs []models.PullRequest, given []models.Review, period *Chronometer) {
+	filteredPRs := filterPRsInPeriod(prs, period)
+	firstReviewTimes, approvalToMergeTimes, reviewCount := analyzeReviews(filteredPRs, period)
+
+	metrics.TimeToFirstReview = summarizeDurations(firstReviewTimes)
+	metrics.ApprovalToMerge = summarizeDurations(approvalToMergeTimes)
+	metrics.ReviewsPerPR = calculatePerItem(reviewCount, len(filteredPRs))
+	metrics.ReviewsReceived = reviewCount
+	metrics.ReviewsGiven = len(given)
+}
+
+// analyzeReviews measures how long pull requests waited for their first review, counted from the
+// first review request or from creation when none was requested later, and how long approved pull
+// requests waited from their last approval to the merge. Reviews by the author are ignored.
+func analyzeReviews(prs []models.PullRequest, period *Chronometer) ([]time.Duration, []time.Duration, int) {
+	var firstReviewTimes, approvalToMergeTimes []time.Duration
+	reviewCount := 0
+
+	for _, pr := range prs {
+		var first, lastApproval *models.Review
+		for i, review := range pr.Reviews {
+			if review.Reviewer == pr.Author {
+				continue
+			}
+			reviewCount++
+			if first == nil || review.SubmittedAt.Before(first.SubmittedAt) {
+				first = &pr.Reviews[i]
+			}
+			if review.State == models.ReviewApproved && (pr.MergedAt == nil || !review.SubmittedAt.After(*pr.MergedAt)) &&
+				(lastApproval == nil || review.SubmittedAt.After(lastApproval.SubmittedAt)) {
+				lastApproval = &pr.Reviews[i]
+			}
+		}
+
+		if first != nil && period.Contains(first.SubmittedAt) {
+			waitingSince := pr.CreatedAt
+			var firstRequest *time.Time
+			for _, request := range pr.ReviewRequests {
+				if request.RequestedAt.After(pr.CreatedAt) && request.RequestedAt.Before(first.SubmittedAt) &&
+					(firstRequest == nil || request.RequestedAt.Before(*firstRequest)) {
+					firstRequest = &request.RequestedAt
+				}
+			}
+			if firstRequest != nil {
+				waitingSince = *firstRequest
+			}
+			firstReviewTimes = append(firstReviewTimes, first.SubmittedAt.Sub(waitingSince))
+		}
+		if lastApproval != nil && pr.MergedAt != nil && period.Contains(*pr.MergedAt) {
+			approvalToMergeTimes = append(approvalToMergeTimes, pr.MergedAt.Sub(lastApproval.SubmittedAt))
+		}
+	}
+
+	return firstReviewTimes, approvalToMergeTimes, reviewCount
+}
+
+// summarizeDurations computes the mean and the nearest-rank percentiles of the durations
+func summarizeDurations(times []time.Duration) models.DurationSummary {
 	if len(times) == 0 {
-		return "None"
+		return models.DurationSummary{}
 	}
 
 	var total time.Duration
 	for _, d := range times {
 		total += d
 	}
-	avg := total / time.Duration(len(times))
-	return formatter.FormatDuration(avg)
+	return models.DurationSummary{
+		Count:  len(times),
+		Mean:   total / time.Duration(len(times)),
+		Min:    percentile(times, 0),
+		Median: percentile(times, 50),
+		P75:    percentile(times, 75),
+		P90:    percentile(times, 90),
+		Max:    percentile(times, 100),
+	}
+}
+
+// calculatePerItem formats the average count per item with one decimal
+func calculatePerItem(count, items int) string {
+	if items == 0 {
+		return "None"
+	}
+	return fmt.Sprintf("%.1f", float64(count)/float64(items))
 }
 
 func calculateRate(completed, total int) string {
@@ -1,6 +1,10 @@
 package services_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -25,7 +29,7 @@ func TestExecute(t *testing.T) {
 
 	repository.SetTestMode(true)
 
-	repository.Executor = func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
 		switch resourceType {
 		case "commits":
 			return []map[string]any{
@@ -75,7 +79,7 @@ func TestExecute(t *testing.T) {
 		default:
 			return []map[string]any{}, nil
 		}
-	}
+	})
 
 	repo := models.Repository{
 		Owner: "test-owner",
@@ -92,20 +96,400 @@ func TestExecute(t *testing.T) {
 
 	metrics := services.Execute(repo, options)
 	expected := []models.Metrics{{
-		Repository:        "test-owner/test-repo",
-		User:              "",
-		Commits:           1,
-		LinesAdded:        0,
-		LinesDeleted:      0,
-		PRsCreated:        1,
-		PRsMerged:         1,
-		PRMergeRate:       "100%",
-		AvgPRMergeTime:    "1d 12h 00m",
-		IssuesCreated:     1,
-		IssuesClosed:      0,
-		IssueResolveRate:  "0%",
-		AvgIssueCloseTime: "None",
-		OpenIssues:        1,
+		Repository:       "test-owner/test-repo",
+		User:             "",
+		Commits:          1,
+		LinesAdded:       0,
+		LinesDeleted:     0,
+		PRsCreated:       1,
+		PRsMerged:        1,
+		PRMergeRate:      "100%",
+		PRMergeTime:      models.DurationSummary{Count: 1, Mean: 36 * time.Hour, Min: 36 * time.Hour, Median: 36 * time.Hour, P75: 36 * time.Hour, P90: 36 * time.Hour, Max: 36 * time.Hour},
+		IssuesCreated:    1,
+		IssuesClosed:     0,
+		IssueResolveRate: "0%",
+		OpenIssues:       1,
 	}}
 	assert.Equal(t, expected, metrics)
 }
+
+func TestExecute_DurationPercentiles(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	start := chronometer.StartTime().Add(time.Hour)
+	at := func(hours int) string { return start.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		if resourceType != "pull requests" {
+			return []map[string]any{}, nil
+		}
+		// One pull request that sat for over two weeks among ones merged within hours
+		var prs []map[string]any
+		for i, hours := range []int{1, 2, 2, 3, 4, 5, 6, 8, 10, 400} {
+			prs = append(prs, map[string]any{
+				"number":     float64(i + 1),
+				"state":      "closed",
+				"created_at": at(0),
+				"merged_at":  at(hours),
+			})
+		}
+		return prs, nil
+	})
+
+	metrics := services.Execute(models.Repository{Owner: "test-owner", Name: "test-repo"}, services.MetricsOptions{Period: chronometer})
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, models.DurationSummary{
+		Count:  10,
+		Mean:   time.Duration(441) * time.Hour / 10,
+		Min:    time.Hour,
+		Median: 4 * time.Hour,
+		P75:    8 * time.Hour,
+		P90:    10 * time.Hour,
+		Max:    400 * time.Hour,
+	}, metrics[0].PRMergeTime)
+}
+
+func TestExecute_CoAuthorAttribution(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	date := chronometer.StartTime().Add(24 * time.Hour).Format(time.RFC3339)
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		if resourceType != "commits" {
+			return []map[string]any{}, nil
+		}
+		return []map[string]any{
+			{
+				"sha":    "aaa",
+				"author": map[string]any{"login": "alice"},
+				"commit": map[string]any{
+					"message": "Pair on parser\n\nCo-authored-by: Bob <1+bob@users.noreply.github.com>\nCo-authored-by: Carol <carol@example.com>",
+					"author":  map[string]any{"name": "Alice", "email": "alice@example.com", "date": date},
+				},
+			},
+			{
+				"sha":    "bbb",
+				"author": map[string]any{"login": "alice"},
+				"commit": map[string]any{
+					"message": "Solo fix",
+					"author":  map[string]any{"name": "Alice", "email": "alice@example.com", "date": date},
+				},
+			},
+		}, nil
+	})
+
+	tests := []struct {
+		attribution string
+		want        map[string]float64
+	}{
+		{attribution: services.AttributionPrimary, want: map[string]float64{"alice": 2}},
+		{attribution: services.AttributionFull, want: map[string]float64{"alice": 2, "bob": 1, "Carol": 1}},
+		{attribution: services.AttributionSplit, want: map[string]float64{"alice": 1 + 1.0/3, "bob": 1.0 / 3, "Carol": 1.0 / 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attribution, func(t *testing.T) {
+			options := services.MetricsOptions{Period: chronometer, ByUser: true, Attribution: tt.attribution}
+			metrics := services.Execute(models.Repository{Owner: "test-owner", Name: "test-repo"}, options)
+
+			commits := make(map[string]float64)
+			for _, m := range metrics {
+				commits[m.User] = m.Commits
+			}
+			assert.InDeltaMapValues(t, tt.want, commits, 1e-9)
+		})
+	}
+}
+
+func TestExecute_MarksIncomplete(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		if resourceType == "issues" {
+			return nil, errors.New("HTTP 404")
+		}
+		return []map[string]any{}, nil
+	})
+
+	metrics := services.Execute(models.Repository{Owner: "test-owner", Name: "test-repo"}, services.MetricsOptions{Period: chronometer})
+	assert.Len(t, metrics, 1)
+	assert.True(t, metrics[0].Incomplete)
+}
+
+func TestExecute_Reviews(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	start := chronometer.StartTime()
+	at := func(hours int) string { return start.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch resourceType {
+		case "pull requests":
+			return []map[string]any{
+				{
+					"number":     float64(1),
+					"state":      "closed",
+					"created_at": at(0),
+					"merged_at":  at(30),
+					"user":       map[string]any{"login": "alice"},
+				},
+			}, nil
+		case "reviews":
+			return []map[string]any{
+				{"user": map[string]any{"login": "alice"}, "state": "COMMENTED", "submitted_at": at(1)},
+				{"user": map[string]any{"login": "bob"}, "state": "CHANGES_REQUESTED", "submitted_at": at(6)},
+				{"user": map[string]any{"login": "bob"}, "state": "APPROVED", "submitted_at": at(24)},
+			}, nil
+		case "review requests":
+			return []map[string]any{
+				{"event": "labeled", "created_at": at(1)},
+				{"event": "review_requested", "created_at": at(2), "requested_reviewer": map[string]any{"login": "bob"}},
+			}, nil
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+
+	options := services.MetricsOptions{Period: chronometer, ByUser: true, Reviews: true, SortBy: "repository,user"}
+	metrics := services.Execute(models.Repository{Owner: "test-owner", Name: "test-repo"}, options)
+	assert.Len(t, metrics, 2)
+
+	alice, bob := metrics[0], metrics[1]
+	assert.Equal(t, "alice", alice.User)
+	// Waiting starts at the review request; the author's own review does not count
+	assert.Equal(t, 4*time.Hour, alice.TimeToFirstReview.Mean)
+	assert.Equal(t, 6*time.Hour, alice.ApprovalToMerge.Mean)
+	assert.Equal(t, "2.0", alice.ReviewsPerPR)
+	assert.Equal(t, 0, alice.ReviewsGiven)
+
+	assert.Equal(t, "bob", bob.User)
+	assert.Equal(t, 2, bob.ReviewsGiven)
+	assert.Equal(t, "None", bob.ReviewsPerPR)
+}
+
+func TestCollect_Deployments(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	start := chronometer.StartTime()
+	at := func(hours int) string { return start.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+	commit := func(sha string, hours int) map[string]any {
+		return map[string]any{
+			"sha":    sha,
+			"commit": map[string]any{"author": map[string]any{"name": "alice", "date": at(hours)}},
+		}
+	}
+	statuses := map[string][]map[string]any{
+		"/deployments/1/statuses": {{"state": "pending", "created_at": at(3)}, {"state": "success", "created_at": at(4)}},
+		"/deployments/2/statuses": {{"state": "failure", "created_at": at(12)}},
+		"/deployments/3/statuses": {{"state": "success", "created_at": at(14)}, {"state": "inactive", "created_at": at(20)}},
+		"/deployments/4/statuses": {{"state": "success", "created_at": at(5)}},
+		"/deployments/5/statuses": {{"state": "success", "created_at": at(-47)}},
+	}
+
+	lists := streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch resourceType {
+		case "commits":
+			return []map[string]any{commit("c3", 10), commit("c2", 2), commit("c1", 0)}, nil
+		case "deployments":
+			return []map[string]any{
+				{"id": float64(4), "sha": "c1", "environment": "staging", "created_at": at(5)},
+				{"id": float64(3), "sha": "c3", "environment": "production", "created_at": at(13)},
+				{"id": float64(2), "sha": "c3", "environment": "production", "created_at": at(11)},
+				{"id": float64(1), "sha": "c2", "environment": "production", "created_at": at(3)},
+				{"id": float64(5), "sha": "c0", "environment": "production", "created_at": at(-48)},
+			}, nil
+		case "deployment statuses":
+			for suffix, items := range statuses {
+				if contains(endpoint, suffix) {
+					return items, nil
+				}
+			}
+			return nil, errors.New("HTTP 404")
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+	commits := map[string][]map[string]any{
+		"/repos/test-owner/test-repo/compare/c0...c2": {commit("c1", 0), commit("c2", 2)},
+		"/repos/test-owner/test-repo/compare/c2...c3": {commit("c3", 10)},
+	}
+	var compared []string
+	repository.Executor = func(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error) {
+		if resourceType != "compared commits" {
+			return lists(endpoint, repo, resourceType)
+		}
+		compared = append(compared, endpoint)
+		data, err := json.Marshal(map[string]any{"commits": commits[endpoint]})
+		return io.NopCloser(bytes.NewReader(data)), err
+	}
+
+	options := services.MetricsOptions{Period: chronometer, Deployments: true}
+	report := services.Collect(models.Repository{Owner: "test-owner", Name: "test-repo"}, options)
+	assert.Len(t, report.Metrics, 1)
+	assert.Equal(t, []models.DeploymentMetrics{
+		{
+			Repository:  "test-owner/test-repo",
+			Environment: "production",
+			Deployments: 2,
+			// 2 successful deployments in 30 days
+			DeploymentFrequency: "0.07/day",
+			// #1 shipped c1 and c2 since #5 before the period, 4h and 2h after authoring.
+			// #3 shipped c3, the only commit after c2, 4h after.
+			LeadTime: models.DurationSummary{
+				Count: 3, Mean: 200 * time.Minute, Min: 2 * time.Hour, Median: 4 * time.Hour, P75: 4 * time.Hour, P90: 4 * time.Hour, Max: 4 * time.Hour,
+			},
+			ChangeFailureRate: "33%",
+			// #2 failed at 12h and #3 restored at 14h
+			TimeToRestore: models.DurationSummary{
+				Count: 1, Mean: 2 * time.Hour, Min: 2 * time.Hour, Median: 2 * time.Hour, P75: 2 * time.Hour, P90: 2 * time.Hour, Max: 2 * time.Hour,
+			},
+		},
+		{
+			Repository:          "test-owner/test-repo",
+			Environment:         "staging",
+			Deployments:         1,
+			DeploymentFrequency: "0.03/day",
+			// #4 is the first staging deployment, so what it shipped is unknown
+			ChangeFailureRate: "0%",
+		},
+	}, report.Deployments)
+	// The failed #2 ships nothing, so #3 is compared with #1
+	assert.Equal(t, []string{"/repos/test-owner/test-repo/compare/c0...c2", "/repos/test-owner/test-repo/compare/c2...c3"}, compared)
+}
+
+// streamExecutor adapts a mock returning decoded items into an Executor that
+// streams them as a single JSON page, the way `gh api --paginate` does.
+func streamExecutor(fn func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error)) func(string, models.Repository, string) (io.ReadCloser, error) {
+	return func(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error) {
+		items, err := fn(endpoint, repo, resourceType)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(items)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+func TestExecute_Buckets(t *testing.T) {
+	startDate, endDate := "2024-01-15", "2024-02-20"
+	chronometer, err := services.NewChronometer(services.ChronometerOption{StartDate: &startDate, EndDate: &endDate})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	commit := func(author, date string) map[string]any {
+		return map[string]any{"sha": author + date, "commit": map[string]any{"author": map[string]any{"name": author, "date": date}}}
+	}
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch resourceType {
+		case "commits":
+			return []map[string]any{
+				commit("alice", "2024-01-20T10:00:00+09:00"),
+				// 2024-02-01 in JST, so it counts for February
+				commit("alice", "2024-01-31T16:00:00Z"),
+				commit("bob", "2024-02-10T10:00:00+09:00"),
+			}, nil
+		case "pull requests":
+			return []map[string]any{
+				{
+					"number":     float64(1),
+					"state":      "closed",
+					"created_at": "2024-01-30T10:00:00+09:00",
+					"merged_at":  "2024-02-02T10:00:00+09:00",
+					"user":       map[string]any{"login": "bob"},
+				},
+			}, nil
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+
+	options := services.MetricsOptions{Period: chronometer, ByUser: true, Bucket: services.BucketMonth, SortBy: "repository,user"}
+	metrics := services.Execute(models.Repository{Owner: "test-owner", Name: "test-repo"}, options)
+
+	type row struct {
+		user, bucket string
+		commits      float64
+		prsCreated   int
+		prsMerged    int
+	}
+	var rows []row
+	for _, m := range metrics {
+		rows = append(rows, row{m.User, m.Bucket, m.Commits, m.PRsCreated, m.PRsMerged})
+	}
+	// Every user gets a row in every bucket, and the pull request counts in each bucket it was active in
+	assert.Equal(t, []row{
+		{"alice", "2024-01", 1, 0, 0},
+		{"alice", "2024-02", 1, 0, 0},
+		{"bob", "2024-01", 0, 1, 1},
+		{"bob", "2024-02", 1, 1, 1},
+	}, rows)
+
+	assert.Equal(t, "2024-01-15", metrics[0].BucketStart.Format("2006-01-02"))
+	assert.Equal(t, "2024-02-01", metrics[1].BucketStart.Format("2006-01-02"))
+	assert.Equal(t, "2024-02-20", metrics[1].BucketEnd.Format("2006-01-02"))
+	assert.Equal(t, 72*time.Hour, metrics[3].PRMergeTime.Mean)
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+)
+
+// ReleasesOptions represents configuration for the release report
+type ReleasesOptions struct {
+	Period *Chronometer
+	// ShowHost prefixes repository names with their host, for runs mixing several hosts
+	ShowHost bool
+}
+
+// ExecuteReleases summarizes the releases of a repository published in the period: how often they
+// were published and which commits and merged pull requests each shipped. Repositories without
+// releases are reported from their tags.
+func ExecuteReleases(repo models.Repository, opts ReleasesOptions) models.ReleaseMetrics {
+	incomplete := false
+	warn := func(err error) {
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			incomplete = true
+		}
+	}
+
+	all, err := repository.GetReleases(repo)
+	warn(err)
+	tags, err := repository.GetTags(repo)
+	warn(err)
+	releases := publishedReleases(all, tags)
+
+	// The first release in the period ships the changes made since the release before it
+	since := opts.Period.StartTime()
+	var previous *models.Release
+	for i := range releases {
+		if releases[i].PublishedAt.Before(opts.Period.StartTime()) {
+			previous = &releases[i]
+		}
+	}
+	if previous != nil && !previous.CreatedAt.IsZero() {
+		since = minTime(since, previous.CreatedAt)
+	}
+
+	commits, err := repository.GetCommits(repo, since, false)
+	warn(err)
+	prs, err := repository.GetPullRequestsMerged(repo, since, opts.Period.EndTime())
+	warn(err)
+
+	if len(releases) == 0 {
+		releases = tagReleases(tags, commits)
+	}
+
+	shipped := func(base, head string) ([]models.Commit, error) {
+		switch {
+		case head == "":
+			return nil, nil
+		case base == "":
+			return branchHistory(commits, head), nil
+		default:
+			return repository.CompareCommits(repo, base, head)
+		}
+	}
+	metrics, err := analyzeReleases(releases, commits, shipped, prs, opts.Period)
+	warn(err)
+	metrics.Repository = repo.DisplayName(opts.ShowHost)
+	metrics.Incomplete = incomplete
+	return metrics
+}
+
+// publishedReleases returns the published releases sorted by publication date, with the commit
+// of each release resolved from its tag
+func publishedReleases(releases []models.Release, tags []models.Tag) []models.Release {
+	tagSHAs := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		tagSHAs[tag.Name] = tag.SHA
+	}
+
+	var published []models.Release
+	for _, release := range releases {
+		if release.Draft || release.PublishedAt.IsZero() {
+			continue
+		}
+		if release.SHA == "" {
+			release.SHA = tagSHAs[release.TagName]
+		}
+		published = append(published, release)
+	}
+	sort.SliceStable(published, func(i, j int) bool { return published[i].PublishedAt.Before(published[j].PublishedAt) })
+	return published
+}
+
+// tagReleases treats the tags pointing at known commits as releases published at their commit date
+func tagReleases(tags []models.Tag, commits []models.Commit) []models.Release {
+	commitDates := make(map[string]time.Time, len(commits))
+	for _, c := range commits {
+		commitDates[c.SHA] = c.Date
+	}
+
+	var releases []models.Release
+	for _, tag := range tags {
+		if date, ok := commitDates[tag.SHA]; ok {
+			releases = append(releases, models.Release{TagName: tag.Name, SHA: tag.SHA, CreatedAt: date, PublishedAt: date})
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool { return releases[i].PublishedAt.Before(releases[j].PublishedAt) })
+	return releases
+}
+
+// analyzeReleases computes the release metrics of the releases published in the period.
+// A release ships the commits between the tag of the previous release and its own, as returned
+// by shipped, and the pull requests whose merge commit is among them. Pull requests without a
+// known merge commit are shipped by the first release cut after they were merged, the cut being
+// the date of the tagged commit. A failed comparison skips the release's commits and is returned
+// once all releases are measured.
+func analyzeReleases(releases []models.Release, commits []models.Commit, shipped shippedFunc, prs []models.PullRequest, period *Chronometer) (models.ReleaseMetrics, error) {
+	commitDates := make(map[string]time.Time, len(commits))
+	for _, c := range commits {
+		commitDates[c.SHA] = c.Date
+	}
+	cut := func(release models.Release) time.Time {
+		if date, ok := commitDates[release.SHA]; ok {
+			return date
+		}
+		// GitHub reports the date of the released commit as the creation date
+		if !release.CreatedAt.IsZero() {
+			return minTime(release.CreatedAt, release.PublishedAt)
+		}
+		return release.PublishedAt
+	}
+
+	var metrics models.ReleaseMetrics
+	var intervals, mergeToRelease []time.Duration
+	var errs []error
+	shippedCommits, shippedPRs := 0, 0
+	var previousCut time.Time
+	for i, release := range releases {
+		releaseCut := cut(release)
+		if period.Contains(release.PublishedAt) {
+			metrics.Releases++
+			metrics.LatestRelease = release.TagName
+			base := ""
+			if i > 0 {
+				intervals = append(intervals, release.PublishedAt.Sub(releases[i-1].PublishedAt))
+				base = releases[i-1].SHA
+			}
+
+			commits, err := shipped(base, release.SHA)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			shippedCommits += len(commits)
+			shippedSHAs := make(map[string]bool, len(commits))
+			for _, c := range commits {
+				shippedSHAs[c.SHA] = true
+			}
+			for _, pr := range prs {
+				if pr.MergedAt == nil {
+					continue
+				}
+				if pr.MergeCommitSHA != "" && shippedSHAs[pr.MergeCommitSHA] ||
+					pr.MergeCommitSHA == "" && pr.MergedAt.After(previousCut) && !pr.MergedAt.After(releaseCut) {
+					shippedPRs++
+					mergeToRelease = append(mergeToRelease, release.PublishedAt.Sub(*pr.MergedAt))
+				}
+			}
+		}
+		previousCut = releaseCut
+	}
+
+	metrics.Interval = summarizeDurations(intervals)
+	metrics.CommitsPerRelease = calculatePerItem(shippedCommits, metrics.Releases)
+	metrics.PRsPerRelease = calculatePerItem(shippedPRs, metrics.Releases)
+	metrics.MergeToRelease = summarizeDurations(mergeToRelease)
+	return metrics, errors.Join(errs...)
+}
+
+// branchHistory returns head and the commits listed after it. Commits are listed newest first along
+// the default branch, so these are the fetched history of head. Unknown heads have no history.
+func branchHistory(commits []models.Commit, head string) []models.Commit {
+	for i, c := range commits {
+		if c.SHA == head {
+			return commits[i:]
+		}
+	}
+	return nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// SortReleaseMetrics sorts release metrics by repository name
+func SortReleaseMetrics(metrics []models.ReleaseMetrics) {
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return metrics[i].Repository < metrics[j].Repository
+	})
+}
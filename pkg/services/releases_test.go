@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteReleases(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	start := chronometer.StartTime()
+	at := func(hours int) string { return start.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+	commit := func(sha string, hours int) map[string]any {
+		return map[string]any{
+			"sha":    sha,
+			"commit": map[string]any{"author": map[string]any{"name": "alice", "date": at(hours)}},
+		}
+	}
+	pr := func(number, mergedHours int, mergeCommit string) map[string]any {
+		return map[string]any{
+			"number":           float64(number),
+			"state":            "closed",
+			"created_at":       at(0),
+			"merged_at":        at(mergedHours),
+			"merge_commit_sha": mergeCommit,
+			"user":             map[string]any{"login": "alice"},
+		}
+	}
+
+	lists := streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch resourceType {
+		case "releases":
+			return []map[string]any{
+				{"tag_name": "v1.2", "draft": true, "created_at": at(100)},
+				{"tag_name": "v1.1", "prerelease": true, "created_at": at(50), "published_at": at(72)},
+				{"tag_name": "v1.0", "created_at": at(20), "published_at": at(24)},
+				{"tag_name": "v0.9", "created_at": at(-241), "published_at": at(-240)},
+			}, nil
+		case "tags":
+			return []map[string]any{
+				{"name": "v1.1", "commit": map[string]any{"sha": "c3"}},
+				{"name": "v1.0", "commit": map[string]any{"sha": "c2"}},
+				{"name": "v0.9", "commit": map[string]any{"sha": "c0"}},
+			}, nil
+		case "commits":
+			return []map[string]any{
+				commit("c4", 80), commit("c3", 50), commit("c2", 20), commit("c1", 1), commit("c0", -241),
+			}, nil
+		case "pull requests":
+			// #2 is merged before v1.0 is cut but its merge commit only lands with v1.1
+			return []map[string]any{pr(1, 2, "c1"), pr(2, 15, "c3"), pr(3, 90, "c4"), pr(4, 30, "")}, nil
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+	compared := map[string][]map[string]any{
+		"/repos/test-owner/test-repo/compare/c0...c2": {commit("c1", 1), commit("c2", 20)},
+		"/repos/test-owner/test-repo/compare/c2...c3": {commit("c3", 50)},
+	}
+	repository.Executor = func(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error) {
+		if resourceType != "compared commits" {
+			return lists(endpoint, repo, resourceType)
+		}
+		data, err := json.Marshal(map[string]any{"commits": compared[endpoint]})
+		return io.NopCloser(bytes.NewReader(data)), err
+	}
+
+	metrics := services.ExecuteReleases(models.Repository{Owner: "test-owner", Name: "test-repo"}, services.ReleasesOptions{Period: chronometer})
+	assert.Equal(t, models.ReleaseMetrics{
+		Repository:    "test-owner/test-repo",
+		Releases:      2,
+		LatestRelease: "v1.1",
+		// Intervals of 11 days since v0.9 and 2 days since v1.0
+		Interval: models.DurationSummary{
+			Count: 2, Mean: 156 * time.Hour, Min: 48 * time.Hour, Median: 48 * time.Hour, P75: 264 * time.Hour, P90: 264 * time.Hour, Max: 264 * time.Hour,
+		},
+		// v1.0 ships c1 and c2, v1.1 ships c3
+		CommitsPerRelease: "1.5",
+		// #1 is released 22h after merging and #2 57h after; #4 has no known merge commit and is
+		// released by v1.1, the first release cut after its merge, 42h after; #3 is unreleased
+		PRsPerRelease: "1.5",
+		MergeToRelease: models.DurationSummary{
+			Count: 3, Mean: 2420 * time.Minute, Min: 22 * time.Hour, Median: 42 * time.Hour, P75: 57 * time.Hour, P90: 57 * time.Hour, Max: 57 * time.Hour,
+		},
+	}, metrics)
+}
+
+func TestExecuteReleases_FromTags(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	at := func(hours int) string {
+		return chronometer.StartTime().Add(time.Duration(hours) * time.Hour).Format(time.RFC3339)
+	}
+
+	commit := func(sha string, hours int) map[string]any {
+		return map[string]any{"sha": sha, "commit": map[string]any{"author": map[string]any{"name": "bob", "date": at(hours)}}}
+	}
+	lists := streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch resourceType {
+		case "tags":
+			return []map[string]any{
+				{"name": "v2", "commit": map[string]any{"sha": "b"}},
+				{"name": "v1", "commit": map[string]any{"sha": "a"}},
+			}, nil
+		case "commits":
+			return []map[string]any{commit("b", 48), commit("a", 24)}, nil
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+	var compared []string
+	repository.Executor = func(endpoint string, repo models.Repository, resourceType string) (io.ReadCloser, error) {
+		if resourceType != "compared commits" {
+			return lists(endpoint, repo, resourceType)
+		}
+		compared = append(compared, endpoint)
+		data, err := json.Marshal(map[string]any{"commits": []map[string]any{commit("b", 48)}})
+		return io.NopCloser(bytes.NewReader(data)), err
+	}
+
+	metrics := services.ExecuteReleases(models.Repository{Owner: "test-owner", Name: "test-repo"}, services.ReleasesOptions{Period: chronometer})
+	assert.Equal(t, 2, metrics.Releases)
+	assert.Equal(t, "v2", metrics.LatestRelease)
+	assert.Equal(t, 24*time.Hour, metrics.Interval.Median)
+	// v1 ships the history of a, v2 the commits compared since v1
+	assert.Equal(t, "1.0", metrics.CommitsPerRelease)
+	assert.Equal(t, []string{"/repos/test-owner/test-repo/compare/a...b"}, compared)
+	assert.Zero(t, metrics.MergeToRelease.Count)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"yokiyoki/pkg/models"
+)
+
+// teamActivity is the activity of a repository that its team rows were computed from
+type teamActivity struct {
+	repository string
+	data       repoData
+}
+
+// MergeTeams computes one row per team, or per team and bucket, from the activity of every
+// repository of the reports, which must have been collected with options.ByTeam. Rows list the
+// repositories they cover, and are incomplete when any of them is.
+func MergeTeams(reports []Report, options MetricsOptions) []models.Metrics {
+	var merged repoData
+	var repositories []string
+	for _, report := range reports {
+		if report.teams == nil {
+			continue
+		}
+		repositories = append(repositories, report.teams.repository)
+		merged.commits = append(merged.commits, report.teams.data.commits...)
+		merged.prs = append(merged.prs, report.teams.data.prs...)
+		merged.issues = append(merged.issues, report.teams.data.issues...)
+		merged.incomplete = merged.incomplete || report.teams.data.incomplete
+	}
+	if len(repositories) == 0 {
+		return nil
+	}
+
+	sort.Strings(repositories)
+	name := strings.Join(repositories, ", ")
+	var metrics []models.Metrics
+	if options.Bucket != "" {
+		metrics = executeByBucket(name, merged, options)
+	} else {
+		metrics = executeForPeriod(name, merged, options)
+	}
+	if options.SortBy != "" {
+		SortMetrics(metrics, options.SortBy)
+	}
+	return metrics
+}
+
+// executeByTeam computes the metrics of each team from the activity of its members. Users in no team
+// are reported under models.OtherTeam, and grouped bots under their own automation team.
+func executeByTeam(repoFullName string, data repoData, options MetricsOptions) []models.Metrics {
+	teamOf := func(user string) string {
+		if options.Bots.Policy == BotsGroup && user == models.AutomationUser {
+			return models.AutomationUser
+		}
+		return options.Teams.Team(user)
+	}
+
+	teamCommits := groupCommitsByTeam(applyBotPolicy(groupCommitsByUser(data.commits, options.NormalizeUsers, options.Attribution), options.Bots), teamOf)
+	teamPRs := groupByTeam(applyBotPolicy(groupPRsByUser(data.prs, options.NormalizeUsers), options.Bots), teamOf)
+	teamIssues := groupByTeam(applyBotPolicy(groupIssuesByUser(data.issues, options.NormalizeUsers), options.Bots), teamOf)
+	teamReviews := groupByTeam(applyBotPolicy(groupReviewsByUser(reviewsInPeriod(data.prs, options.Period), options.NormalizeUsers), options.Bots), teamOf)
+
+	teams := extractUniqueUsers(teamCommits, teamPRs, teamIssues)
+	for team := range teamReviews {
+		teams[team] = true
+	}
+
+	if len(teams) == 0 {
+		emptyMetrics := calculateMetricsFromData(repoFullName, "", []creditedCommit{}, []models.PullRequest{}, []models.Issue{}, options.Period, options.DetailedStats)
+		emptyMetrics.Team = "-"
+		emptyMetrics.Incomplete = data.incomplete
+		return []models.Metrics{emptyMetrics}
+	}
+
+	names := make([]string, 0, len(teams))
+	for team := range teams {
+		names = append(names, team)
+	}
+	sort.Strings(names)
+
+	metrics := make([]models.Metrics, 0, len(names))
+	for _, team := range names {
+		m := calculateMetricsFromData(repoFullName, "", teamCommits[team], teamPRs[team], teamIssues[team], options.Period, options.DetailedStats)
+		m.Team = team
+		if options.Reviews {
+			applyReviewMetrics(&m, teamPRs[team], teamReviews[team], options.Period)
+		}
+		if options.Timeline {
+			applyTimelineMetrics(&m, teamPRs[team], teamIssues[team], options.Period, options.Bots.Bots)
+		}
+		m.Incomplete = data.incomplete
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+// groupByTeam merges the items of the users of each team
+func groupByTeam[T any](byUser map[string][]T, teamOf func(user string) string) map[string][]T {
+	byTeam := make(map[string][]T)
+	for user, items := range byUser {
+		team := teamOf(user)
+		byTeam[team] = append(byTeam[team], items...)
+	}
+	return byTeam
+}
+
+// groupCommitsByTeam merges the commits credited to the users of each team. A commit credited to
+// several members counts once, with their shares added up to at most the whole commit.
+func groupCommitsByTeam(byUser map[string][]creditedCommit, teamOf func(user string) string) map[string][]creditedCommit {
+	byTeam := make(map[string][]creditedCommit)
+	index := make(map[string]map[string]int)
+	for user, commits := range byUser {
+		team := teamOf(user)
+		if index[team] == nil {
+			index[team] = make(map[string]int)
+		}
+		for _, commit := range commits {
+			if i, ok := index[team][commit.SHA]; ok && commit.SHA != "" {
+				byTeam[team][i].credit = math.Min(1, byTeam[team][i].credit+commit.credit)
+				continue
+			}
+			index[team][commit.SHA] = len(byTeam[team])
+			byTeam[team] = append(byTeam[team], commit)
+		}
+	}
+	return byTeam
+}
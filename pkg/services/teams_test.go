@@ -0,0 +1,181 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute_ByTeam(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	date := chronometer.StartTime().Add(24 * time.Hour).Format(time.RFC3339)
+	commit := func(sha, login, name, message string) map[string]any {
+		c := map[string]any{
+			"sha":    sha,
+			"commit": map[string]any{"message": message, "author": map[string]any{"name": name, "date": date}},
+		}
+		if login != "" {
+			c["author"] = map[string]any{"login": login}
+		}
+		return c
+	}
+	item := func(number int, login string) map[string]any {
+		return map[string]any{
+			"number":     float64(number),
+			"state":      "open",
+			"created_at": date,
+			"user":       map[string]any{"login": login},
+		}
+	}
+
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch resourceType {
+		case "commits":
+			return []map[string]any{
+				commit("aaa", "alice", "Alice", "Pair on parser\n\nCo-authored-by: Bob <1+bob@users.noreply.github.com>\nCo-authored-by: Carol <carol@example.com>"),
+				// Committed from a machine whose author is not linked to a login
+				commit("bbb", "", "Alice Smith", "Fix typo"),
+				commit("ccc", "dave", "Dave", "Update docs"),
+			}, nil
+		case "pull requests":
+			return []map[string]any{item(1, "alice"), item(2, "carol"), item(3, "dave")}, nil
+		case "issues":
+			return []map[string]any{item(4, "bob")}, nil
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+
+	repo := models.Repository{Owner: "test-owner", Name: "test-repo"}
+	teams := models.NewTeams(map[string][]string{
+		"backend":  {"alice", "bob", "Alice Smith"},
+		"frontend": {"carol"},
+	})
+
+	tests := []struct {
+		attribution string
+		want        map[string]float64
+	}{
+		{attribution: services.AttributionPrimary, want: map[string]float64{"backend": 2, "frontend": 0, models.OtherTeam: 1}},
+		// The commit alice and bob paired on counts once for their team
+		{attribution: services.AttributionFull, want: map[string]float64{"backend": 2, "frontend": 1, models.OtherTeam: 1}},
+		{attribution: services.AttributionSplit, want: map[string]float64{"backend": 1 + 2.0/3, "frontend": 1.0 / 3, models.OtherTeam: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.attribution, func(t *testing.T) {
+			options := services.MetricsOptions{Period: chronometer, ByTeam: true, Teams: teams, Attribution: tt.attribution}
+			metrics := services.Execute(repo, options)
+
+			commits := make(map[string]float64)
+			for _, m := range metrics {
+				assert.Empty(t, m.User)
+				commits[m.Team] = m.Commits
+			}
+			assert.InDeltaMapValues(t, tt.want, commits, 1e-9)
+		})
+	}
+
+	t.Run("pull requests and issues", func(t *testing.T) {
+		metrics := services.Execute(repo, services.MetricsOptions{Period: chronometer, ByTeam: true, Teams: teams, SortBy: "repository,team"})
+		assert.Len(t, metrics, 3)
+		assert.Equal(t, []string{"backend", "frontend", models.OtherTeam}, []string{metrics[0].Team, metrics[1].Team, metrics[2].Team})
+		assert.Equal(t, 1, metrics[0].PRsCreated)
+		assert.Equal(t, 1, metrics[0].IssuesCreated)
+		assert.Equal(t, 1, metrics[1].PRsCreated)
+		assert.Equal(t, 0, metrics[1].IssuesCreated)
+		assert.Equal(t, 1, metrics[2].PRsCreated)
+	})
+}
+
+func TestMergeTeams(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	date := chronometer.StartTime().Add(24 * time.Hour).Format(time.RFC3339)
+	commit := func(sha, login string) map[string]any {
+		return map[string]any{
+			"sha":    sha,
+			"author": map[string]any{"login": login},
+			"commit": map[string]any{"message": "Change", "author": map[string]any{"name": login, "date": date}},
+		}
+	}
+	pr := func(number int, login string) map[string]any {
+		return map[string]any{
+			"number":     float64(number),
+			"state":      "open",
+			"created_at": date,
+			"user":       map[string]any{"login": login},
+		}
+	}
+
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch {
+		case resourceType == "commits" && repo.Name == "api":
+			return []map[string]any{commit("a1", "alice"), commit("a2", "carol")}, nil
+		case resourceType == "commits" && repo.Name == "web":
+			return []map[string]any{commit("w1", "bob"), commit("w2", "carol"), commit("w3", "dave")}, nil
+		case resourceType == "pull requests" && repo.Name == "api":
+			return []map[string]any{pr(1, "alice")}, nil
+		case resourceType == "pull requests" && repo.Name == "web":
+			return []map[string]any{pr(1, "bob"), pr(2, "carol")}, nil
+		case resourceType == "pull requests" && repo.Name == "docs":
+			return nil, errors.New("HTTP 502")
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+
+	options := services.MetricsOptions{
+		Period: chronometer,
+		ByTeam: true,
+		Teams:  models.NewTeams(map[string][]string{"backend": {"alice", "bob"}, "frontend": {"carol"}}),
+	}
+	var reports []services.Report
+	for _, name := range []string{"web", "docs", "api"} {
+		report := services.Collect(models.Repository{Owner: "test-owner", Name: name}, options)
+		// Team rows are only computed across repositories
+		assert.Empty(t, report.Metrics)
+		reports = append(reports, report)
+	}
+
+	metrics := services.MergeTeams(reports, options)
+	services.SortMetrics(metrics, "team,repository")
+	assert.Len(t, metrics, 3)
+	for _, m := range metrics {
+		assert.Equal(t, "test-owner/api, test-owner/docs, test-owner/web", m.Repository)
+		// The pull requests of docs could not be fetched
+		assert.True(t, m.Incomplete)
+	}
+	assert.Equal(t, []string{"backend", "frontend", models.OtherTeam}, []string{metrics[0].Team, metrics[1].Team, metrics[2].Team})
+	assert.Equal(t, []float64{2, 2, 1}, []float64{metrics[0].Commits, metrics[1].Commits, metrics[2].Commits})
+	assert.Equal(t, []int{2, 1, 0}, []int{metrics[0].PRsCreated, metrics[1].PRsCreated, metrics[2].PRsCreated})
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/parallel"
+	"yokiyoki/pkg/repository"
+)
+
+// timelineItem is an issue or pull request together with its timeline
+type timelineItem struct {
+	kind      string
+	number    int
+	author    string
+	createdAt time.Time
+	events    []models.TimelineEvent
+}
+
+// timelineItems returns the issues and pull requests as timeline items, pull requests first
+func timelineItems(prs []models.PullRequest, issues []models.Issue) []timelineItem {
+	items := make([]timelineItem, 0, len(prs)+len(issues))
+	for _, pr := range prs {
+		items = append(items, timelineItem{kind: models.KindPullRequest, number: pr.Number, author: pr.Author, createdAt: pr.CreatedAt, events: pr.Timeline})
+	}
+	for _, issue := range issues {
+		items = append(items, timelineItem{kind: models.KindIssue, number: issue.Number, author: issue.Author, createdAt: issue.CreatedAt, events: issue.Timeline})
+	}
+	return items
+}
+
+// attachTimelines fetches the timeline of each pull request and issue in parallel.
+// It reports false when any of them could not be fetched.
+func attachTimelines(repo models.Repository, prs []models.PullRequest, issues []models.Issue, concurrency int) bool {
+	type result struct {
+		events []models.TimelineEvent
+		err    error
+	}
+	results := parallel.Map(timelineItems(prs, issues), concurrency, func(item timelineItem) result {
+		events, err := repository.GetTimeline(repo, item.kind, item.number)
+		return result{events: events, err: err}
+	})
+
+	complete := true
+	for i, r := range results {
+		if r.err != nil {
+			fmt.Printf("Warning: %v\n", r.err)
+			complete = false
+		}
+		if i < len(prs) {
+			prs[i].Timeline = r.events
+		} else {
+			issues[i-len(prs)].Timeline = r.events
+		}
+	}
+	return complete
+}
+
+// applyTimelineMetrics fills in the timeline metrics of the pull requests and issues in the period
+func applyTimelineMetrics(metrics *models.Metrics, prs []models.PullRequest, issues []models.Issue, period *Chronometer, bots models.Bots) {
+	items := timelineItems(filterPRsInPeriod(prs, period), filterIssuesInPeriod(issues, period))
+	responseTimes, assignmentTimes, closed, reopened := analyzeTimelines(items, period, bots)
+
+	metrics.TimeToFirstResponse = summarizeDurations(responseTimes)
+	metrics.ReopenRate = calculateRate(reopened, closed)
+	metrics.ItemsClosed = closed
+	metrics.ItemsReopened = reopened
+	metrics.TimeToAssignment = summarizeDurations(assignmentTimes)
+}
+
+// analyzeTimelines measures, for items created in the period, how long they waited for the first
+// comment or review by someone other than the author or a bot, and for their first assignment.
+// It also counts the items that were closed in the period and those of them that were reopened in it.
+func analyzeTimelines(items []timelineItem, period *Chronometer, bots models.Bots) ([]time.Duration, []time.Duration, int, int) {
+	var responseTimes, assignmentTimes []time.Duration
+	closed, reopened := 0, 0
+
+	for _, item := range items {
+		var response, assignment *time.Time
+		wasClosed, wasReopened := false, false
+		for i, event := range item.events {
+			switch event.Event {
+			case models.EventCommented, models.EventReviewed:
+				if response == nil && event.Actor != item.author && !bots.IsBot(event.Actor) {
+					response = &item.events[i].CreatedAt
+				}
+			case models.EventAssigned:
+				if assignment == nil {
+					assignment = &item.events[i].CreatedAt
+				}
+			case models.EventClosed:
+				wasClosed = wasClosed || period.Contains(event.CreatedAt)
+			case models.EventReopened:
+				wasReopened = wasReopened || period.Contains(event.CreatedAt)
+			}
+		}
+
+		if wasClosed {
+			closed++
+			if wasReopened {
+				reopened++
+			}
+		}
+		if !period.Contains(item.createdAt) {
+			continue
+		}
+		if response != nil && period.Contains(*response) {
+			responseTimes = append(responseTimes, response.Sub(item.createdAt))
+		}
+		if assignment != nil && period.Contains(*assignment) {
+			assignmentTimes = append(assignmentTimes, assignment.Sub(item.createdAt))
+		}
+	}
+
+	return responseTimes, assignmentTimes, closed, reopened
+}
+
+// calculateLabelMetrics computes how long the issues and pull requests in the period carried each
+// label, sorted by label
+func calculateLabelMetrics(repo string, prs []models.PullRequest, issues []models.Issue, period *Chronometer) []models.LabelMetrics {
+	times := make(map[string][]time.Duration)
+	for _, item := range timelineItems(filterPRsInPeriod(prs, period), filterIssuesInPeriod(issues, period)) {
+		for label, d := range timeInLabels(item.events, period) {
+			times[label] = append(times[label], d)
+		}
+	}
+
+	labels := make([]string, 0, len(times))
+	for label := range times {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	result := make([]models.LabelMetrics, 0, len(labels))
+	for _, label := range labels {
+		result = append(result, models.LabelMetrics{
+			Repository:  repo,
+			Label:       label,
+			Items:       len(times[label]),
+			TimeInLabel: summarizeDurations(times[label]),
+		})
+	}
+	return result
+}
+
+// timeInLabels returns how long an item carried each label within the period. A label counts from
+// being added until it is removed or the item is closed, and again after the item is reopened.
+func timeInLabels(events []models.TimelineEvent, period *Chronometer) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	attached := make(map[string]bool)
+	since := make(map[string]time.Time)
+	stop := func(label string, at time.Time) {
+		if start, ok := since[label]; ok {
+			if d := overlap(start, at, period); d >= 0 {
+				durations[label] += d
+			}
+			delete(since, label)
+		}
+	}
+
+	for _, event := range events {
+		switch event.Event {
+		case models.EventLabeled:
+			attached[event.Label] = true
+			if _, ok := since[event.Label]; !ok {
+				since[event.Label] = event.CreatedAt
+			}
+		case models.EventUnlabeled:
+			delete(attached, event.Label)
+			stop(event.Label, event.CreatedAt)
+		case models.EventClosed:
+			for label := range attached {
+				stop(label, event.CreatedAt)
+			}
+		case models.EventReopened:
+			for label := range attached {
+				if _, ok := since[label]; !ok {
+					since[label] = event.CreatedAt
+				}
+			}
+		}
+	}
+
+	end := period.EndTime()
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+	for label := range since {
+		stop(label, end)
+	}
+	return durations
+}
+
+// overlap returns how much of [start, end] falls within the period, or -1 when none of it does
+func overlap(start, end time.Time, period *Chronometer) time.Duration {
+	if start.Before(period.StartTime()) {
+		start = period.StartTime()
+	}
+	if end.After(period.EndTime()) {
+		end = period.EndTime()
+	}
+	if end.Before(start) {
+		return -1
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,86 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"yokiyoki/pkg/models"
+	"yokiyoki/pkg/repository"
+	"yokiyoki/pkg/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollect_Timeline(t *testing.T) {
+	chronometer, err := services.NewChronometer(services.ChronometerOption{
+		Days: func() *int { d := 30; return &d }(),
+	})
+	assert.NoError(t, err)
+
+	originalExecutor := repository.Executor
+	defer func() {
+		repository.Executor = originalExecutor
+		repository.SetTestMode(false)
+	}()
+
+	repository.SetTestMode(true)
+
+	// Start an hour into the period so that timestamps truncated to seconds stay inside it
+	start := chronometer.StartTime().Add(time.Hour)
+	at := func(hours int) string { return start.Add(time.Duration(hours) * time.Hour).Format(time.RFC3339) }
+	user := func(login string) map[string]any { return map[string]any{"login": login} }
+	label := func(name string) map[string]any { return map[string]any{"name": name} }
+
+	repository.Executor = streamExecutor(func(endpoint string, repo models.Repository, resourceType string) ([]map[string]any, error) {
+		switch resourceType {
+		case "pull requests":
+			return []map[string]any{
+				{"number": float64(1), "state": "open", "created_at": at(0), "user": user("alice")},
+			}, nil
+		case "issues":
+			return []map[string]any{
+				{"number": float64(2), "state": "closed", "created_at": at(0), "closed_at": at(40), "user": user("carol")},
+			}, nil
+		case "timeline events":
+			if strings.Contains(endpoint, "/issues/1/") {
+				return []map[string]any{
+					{"event": "commented", "user": user("alice"), "created_at": at(1)},
+					{"event": "commented", "user": user("dependabot[bot]"), "created_at": at(2)},
+					{"event": "reviewed", "user": user("bob"), "submitted_at": at(4)},
+					// Closed after the period, so not counted
+					{"event": "closed", "actor": user("alice"), "created_at": at(800)},
+				}, nil
+			}
+			return []map[string]any{
+				{"event": "labeled", "actor": user("carol"), "label": label("bug"), "created_at": at(0)},
+				{"event": "assigned", "actor": user("dave"), "assignee": user("dave"), "created_at": at(6)},
+				{"event": "commented", "user": user("dave"), "created_at": at(8)},
+				{"event": "closed", "actor": user("dave"), "created_at": at(10)},
+				{"event": "reopened", "actor": user("carol"), "created_at": at(20)},
+				{"event": "unlabeled", "actor": user("dave"), "label": label("bug"), "created_at": at(30)},
+				{"event": "closed", "actor": user("dave"), "created_at": at(40)},
+			}, nil
+		default:
+			return []map[string]any{}, nil
+		}
+	})
+
+	options := services.MetricsOptions{Period: chronometer, Timeline: true, Bots: services.BotOptions{Bots: models.NewBots(nil)}}
+	report := services.Collect(models.Repository{Owner: "test-owner", Name: "test-repo"}, options)
+	assert.Len(t, report.Metrics, 1)
+
+	metrics := report.Metrics[0]
+	// The author's own comment and the bot's comment do not count as a response
+	assert.Equal(t, 6*time.Hour, metrics.TimeToFirstResponse.Mean)
+	// Only the issue was closed in the period, and it was reopened
+	assert.Equal(t, "100%", metrics.ReopenRate)
+	assert.Equal(t, 6*time.Hour, metrics.TimeToAssignment.Mean)
+
+	// The label is not counted while the issue is closed
+	assert.Equal(t, []models.LabelMetrics{
+		{Repository: "test-owner/test-repo", Label: "bug", Items: 1, TimeInLabel: models.DurationSummary{
+			Count: 1, Mean: 20 * time.Hour, Min: 20 * time.Hour, Median: 20 * time.Hour, P75: 20 * time.Hour, P90: 20 * time.Hour, Max: 20 * time.Hour,
+		}},
+	}, report.Labels)
+}